@@ -0,0 +1,362 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+)
+
+// BatchResult is the outcome of checking one cluster or CI zone of a batch.
+type BatchResult struct {
+	Name     string  `json:"name"`
+	Location string  `json:"location"`
+	Mode     RunMode `json:"mode,omitempty"`
+
+	// The worst status of the objects, or ERROR when the cluster could not be checked
+	// or some of its objects could not be queried.
+	Status CheckStatus `json:"status"`
+
+	// The status of each kind of object, keyed by the object name.
+	Objects map[string]CheckStatus `json:"objects,omitempty"`
+
+	// Set when the cluster could not be checked at all, for example because of its metadata.
+	Error string `json:"error,omitempty"`
+
+	Report *Report `json:"report,omitempty"`
+}
+
+// BatchReport is the aggregated result of check-batch.
+type BatchReport struct {
+	Command   string      `json:"command"`
+	Version   string      `json:"version"`
+	Release   string      `json:"release"`
+	Status    CheckStatus `json:"status"`
+	Cancelled bool        `json:"cancelled,omitempty"`
+
+	// The kinds of objects which were checked, in the order of the columns of the matrix.
+	ObjectTypes []string       `json:"objectTypes"`
+	Clusters    []*BatchResult `json:"clusters"`
+}
+
+// readBatchDirectory returns the metadata files (*.json) and the installation directories
+// (which have a metadata.json) in the directory.
+func readBatchDirectory(directory string) ([]string, error) {
+	var (
+		entries   []os.DirEntry
+		location  string
+		locations []string
+		err       error
+	)
+
+	entries, err = os.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not read the batch directory (%v)", err)
+	}
+
+	for _, entry := range entries {
+		location = filepath.Join(directory, entry.Name())
+
+		switch {
+		case entry.IsDir():
+			if _, err = os.Stat(filepath.Join(location, installMetadataFile)); err != nil {
+				log.Debugf("readBatchDirectory: skipping %s", location)
+				continue
+			}
+		case !strings.HasSuffix(entry.Name(), ".json"):
+			log.Debugf("readBatchDirectory: skipping %s", location)
+			continue
+		}
+
+		locations = append(locations, location)
+	}
+
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("Error: There are no metadata files or installation directories in %s", directory)
+	}
+
+	return locations, nil
+}
+
+// readBatchManifest returns the locations listed in the manifest, one per line.  Empty
+// lines and lines starting with # are ignored, and relative locations are relative to
+// the manifest.
+func readBatchManifest(manifest string) ([]string, error) {
+	var (
+		file      *os.File
+		scanner   *bufio.Scanner
+		line      string
+		locations []string
+		err       error
+	)
+
+	file, err = os.Open(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not read the batch manifest (%v)", err)
+	}
+	defer file.Close()
+
+	scanner = bufio.NewScanner(file)
+	for scanner.Scan() {
+		line = strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if !filepath.IsAbs(line) {
+			line = filepath.Join(filepath.Dir(manifest), line)
+		}
+		locations = append(locations, line)
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not read the batch manifest (%v)", err)
+	}
+
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("Error: The batch manifest %s is empty", manifest)
+	}
+
+	return locations, nil
+}
+
+// batchName names a cluster in the matrix after its metadata file or installation directory.
+func batchName(location string) string {
+	return strings.TrimSuffix(filepath.Base(location), ".json")
+}
+
+// NewBatchResult returns the result of a cluster which has not been checked yet.
+func NewBatchResult(location string) *BatchResult {
+	return &BatchResult{
+		Name:     batchName(location),
+		Location: location,
+		Status:   CheckStatusSkipped,
+	}
+}
+
+// Fail records that the cluster could not be checked.
+func (b *BatchResult) Fail(err error) {
+	b.Status = CheckStatusError
+	b.Error = err.Error()
+}
+
+// SetResults records the results of the checks of the cluster.  A kind of object whose
+// objects could not be queried is an ERROR.
+func (b *BatchResult) SetResults(command string, robjsFuncs []NewRunnableObjectsEntry, results []*ObjectResult, discoveryErrs []error) {
+	var (
+		discoveryErr *DiscoveryError
+	)
+
+	b.Report = NewReport(command, results, discoveryErrs)
+	b.Status = b.Report.Status
+	b.Objects = make(map[string]CheckStatus)
+
+	for _, robjsFunc := range robjsFuncs {
+		for _, r := range results {
+			if r.ObjectType == robjsFunc.Name {
+				b.setObjectStatus(robjsFunc.Name, r.Status)
+			}
+		}
+	}
+
+	for _, err := range discoveryErrs {
+		if errors.As(err, &discoveryErr) {
+			b.setObjectStatus(discoveryErr.ObjectName, CheckStatusError)
+		}
+		b.Status = CheckStatusError
+	}
+}
+
+func (b *BatchResult) setObjectStatus(objectName string, status CheckStatus) {
+	var (
+		current CheckStatus
+		found   bool
+	)
+
+	current, found = b.Objects[objectName]
+	if !found || statusRank(status) > statusRank(current) {
+		b.Objects[objectName] = status
+	}
+}
+
+// NewBatchReport aggregates the results of the clusters.  The columns are the kinds of
+// objects in the order they were registered.
+func NewBatchReport(command string, clusters []*BatchResult) *BatchReport {
+	var (
+		report *BatchReport
+		used   = make(map[string]bool)
+	)
+
+	report = &BatchReport{
+		Command:     command,
+		Version:     version,
+		Release:     release,
+		Status:      CheckStatusOK,
+		ObjectTypes: make([]string, 0),
+		Clusters:    clusters,
+	}
+
+	for _, cluster := range clusters {
+		if statusRank(cluster.Status) > statusRank(report.Status) {
+			report.Status = cluster.Status
+		}
+		for objectName := range cluster.Objects {
+			used[objectName] = true
+		}
+	}
+
+	for _, registration := range registry {
+		if used[registration.Name] {
+			report.ObjectTypes = append(report.ObjectTypes, registration.Name)
+		}
+	}
+
+	return report
+}
+
+// ExitError returns the error check-batch should return.  As with a single cluster, an
+// interruption and then errors take precedence over failed checks.
+func (report *BatchReport) ExitError() error {
+	var (
+		notOK bool
+	)
+
+	if report.Cancelled {
+		return &ExitError{Code: exitCodeInterrupted}
+	}
+
+	for _, cluster := range report.Clusters {
+		switch cluster.Status {
+		case CheckStatusError:
+			return &ExitError{Code: exitCodeDiscoveryErrors}
+		case CheckStatusOK:
+		default:
+			notOK = true
+		}
+	}
+
+	if notOK {
+		return &ExitError{Code: exitCodeChecksFailed}
+	}
+
+	return nil
+}
+
+// renderBatchReport writes the batch report in the requested format.
+func renderBatchReport(w io.Writer, format OutputFormat, report *BatchReport) error {
+	switch format {
+	case OutputFormatText:
+		renderBatchText(w, report)
+		return nil
+	case OutputFormatJSON:
+		return renderJSON(w, report)
+	case OutputFormatYAML:
+		return renderYAML(w, report)
+	case OutputFormatJUnit:
+		return renderBatchJUnit(w, report)
+	}
+
+	return fmt.Errorf("Error: unknown output format %s", format)
+}
+
+// renderBatchText writes the matrix of cluster by kind of object, followed by what is
+// wrong with each cluster which is not OK.
+func renderBatchText(w io.Writer, report *BatchReport) {
+	var (
+		tw     *tabwriter.Writer
+		status CheckStatus
+		found  bool
+	)
+
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "CLUSTER\tMODE")
+	for _, objectType := range report.ObjectTypes {
+		fmt.Fprintf(tw, "\t%s", objectType)
+	}
+	fmt.Fprintf(tw, "\tSTATUS\n")
+
+	for _, cluster := range report.Clusters {
+		fmt.Fprintf(tw, "%s\t%s", cluster.Name, cluster.Mode)
+		for _, objectType := range report.ObjectTypes {
+			status, found = cluster.Objects[objectType]
+			if found {
+				fmt.Fprintf(tw, "\t%s", status)
+			} else {
+				fmt.Fprintf(tw, "\t-")
+			}
+		}
+		fmt.Fprintf(tw, "\t%s\n", cluster.Status)
+	}
+
+	tw.Flush()
+
+	for _, cluster := range report.Clusters {
+		if cluster.Status == CheckStatusOK {
+			continue
+		}
+
+		fmt.Fprintf(w, "\n%s (%s):\n", cluster.Name, cluster.Location)
+
+		if cluster.Error != "" {
+			fmt.Fprintf(w, "%s\n", cluster.Error)
+		}
+
+		if cluster.Report != nil {
+			for _, r := range cluster.Report.Results {
+				if !r.IsOK() {
+					renderObjectText(w, r)
+				}
+			}
+			for _, discoveryErr := range cluster.Report.Errors {
+				fmt.Fprintf(w, "Error: %s\n", discoveryErr)
+			}
+		}
+	}
+
+	if report.Cancelled {
+		fmt.Fprintf(w, "The run was interrupted, the results are incomplete.\n")
+	}
+}
+
+// renderBatchJUnit writes the test suites of every cluster, prefixed with its name.
+func renderBatchJUnit(w io.Writer, report *BatchReport) error {
+	var (
+		suites junitTestSuites
+	)
+
+	suites = junitTestSuites{
+		Name:   fmt.Sprintf("PowerVS-Check %s", report.Command),
+		Suites: make([]junitTestSuite, 0),
+	}
+
+	for _, cluster := range report.Clusters {
+		if cluster.Error != "" {
+			suites.addErrors(cluster.Name, []string{cluster.Error})
+		}
+		if cluster.Report != nil {
+			suites.addReport(cluster.Report, cluster.Name+" ")
+		}
+	}
+
+	return writeJUnit(w, &suites)
+}
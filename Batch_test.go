@@ -0,0 +1,312 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeTestFile writes the content into the file, creating its directory.
+func writeTestFile(t *testing.T, filename string, content string) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(filename), 0700)
+	if err == nil {
+		err = os.WriteFile(filename, []byte(content), 0600)
+	}
+	if err != nil {
+		t.Fatalf("writeTestFile: %v", err)
+	}
+}
+
+func TestReadBatchDirectory(t *testing.T) {
+	var (
+		directory = t.TempDir()
+		empty     = t.TempDir()
+	)
+
+	writeTestFile(t, filepath.Join(directory, "zone-a.json"), "{}")
+	writeTestFile(t, filepath.Join(directory, "README.txt"), "")
+	writeTestFile(t, filepath.Join(directory, "cluster-b", installMetadataFile), "{}")
+	writeTestFile(t, filepath.Join(directory, "other", "install-config.yaml"), "")
+	writeTestFile(t, filepath.Join(empty, "README.txt"), "")
+
+	locations, err := readBatchDirectory(directory)
+	if err != nil {
+		t.Fatalf("readBatchDirectory: %v", err)
+	}
+	want := []string{filepath.Join(directory, "cluster-b"), filepath.Join(directory, "zone-a.json")}
+	if !slices.Equal(locations, want) {
+		t.Errorf("readBatchDirectory() = %v, want %v", locations, want)
+	}
+
+	_, err = readBatchDirectory(empty)
+	if err == nil || !strings.Contains(err.Error(), "There are no metadata files") {
+		t.Errorf("readBatchDirectory() of a directory without clusters returned %v", err)
+	}
+
+	_, err = readBatchDirectory(filepath.Join(directory, "missing"))
+	if err == nil || !strings.Contains(err.Error(), "Could not read the batch directory") {
+		t.Errorf("readBatchDirectory() of a missing directory returned %v", err)
+	}
+}
+
+func TestReadBatchManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr string
+	}{
+		{
+			"relative and absolute locations",
+			"zone-a.json\n/clusters/cluster-b\n",
+			[]string{"zone-a.json", "/clusters/cluster-b"},
+			"",
+		},
+		{
+			"comments and empty lines",
+			"# CI zones\n\n  zone-a.json  \n\t\n# zone-b.json\nzone-c.json",
+			[]string{"zone-a.json", "zone-c.json"},
+			"",
+		},
+		{
+			"nothing listed",
+			"# nothing yet\n\n",
+			nil,
+			"is empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				directory = t.TempDir()
+				manifest  = filepath.Join(directory, "clusters.txt")
+				want      []string
+			)
+
+			writeTestFile(t, manifest, tt.content)
+
+			locations, err := readBatchManifest(manifest)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readBatchManifest() returned %v, want an error with %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readBatchManifest: %v", err)
+			}
+
+			// Relative locations are relative to the manifest.
+			for _, location := range tt.want {
+				if !filepath.IsAbs(location) {
+					location = filepath.Join(directory, location)
+				}
+				want = append(want, location)
+			}
+			if !slices.Equal(locations, want) {
+				t.Errorf("readBatchManifest() = %v, want %v", locations, want)
+			}
+		})
+	}
+
+	_, err := readBatchManifest(filepath.Join(t.TempDir(), "missing.txt"))
+	if err == nil || !strings.Contains(err.Error(), "Could not read the batch manifest") {
+		t.Errorf("readBatchManifest() of a missing manifest returned %v", err)
+	}
+}
+
+// newTestObjectResult returns a result of a test kind with the status.
+func newTestObjectResult(objectType string, name string, status CheckStatus) *ObjectResult {
+	return &ObjectResult{
+		ObjectType: objectType,
+		Name:       name,
+		Status:     status,
+		Checks:     []*CheckResult{{ID: "test.check", Status: status}},
+	}
+}
+
+func TestBatchResultSetResults(t *testing.T) {
+	var (
+		robjsFuncs = []NewRunnableObjectsEntry{{Name: "test-a"}, {Name: "test-b"}, {Name: "test-c"}}
+	)
+
+	tests := []struct {
+		name          string
+		results       []*ObjectResult
+		discoveryErrs []error
+		wantStatus    CheckStatus
+		wantObjects   map[string]CheckStatus
+	}{
+		{
+			"all OK",
+			[]*ObjectResult{
+				newTestObjectResult("test-a", "a1", CheckStatusOK),
+				newTestObjectResult("test-b", "b1", CheckStatusOK),
+			},
+			nil,
+			CheckStatusOK,
+			map[string]CheckStatus{"test-a": CheckStatusOK, "test-b": CheckStatusOK},
+		},
+		{
+			"the worst object of a kind",
+			[]*ObjectResult{
+				newTestObjectResult("test-a", "a1", CheckStatusNotOK),
+				newTestObjectResult("test-a", "a2", CheckStatusOK),
+				newTestObjectResult("test-b", "b1", CheckStatusSkipped),
+			},
+			nil,
+			CheckStatusNotOK,
+			map[string]CheckStatus{"test-a": CheckStatusNotOK, "test-b": CheckStatusSkipped},
+		},
+		{
+			"a kind which could not be queried",
+			[]*ObjectResult{
+				newTestObjectResult("test-a", "a1", CheckStatusOK),
+			},
+			[]error{&DiscoveryError{ObjectName: "test-c", Err: errors.New("test error")}},
+			CheckStatusError,
+			map[string]CheckStatus{"test-a": CheckStatusOK, "test-c": CheckStatusError},
+		},
+		{
+			"an error of no kind",
+			[]*ObjectResult{
+				newTestObjectResult("test-a", "a1", CheckStatusOK),
+			},
+			[]error{errors.New("test error")},
+			CheckStatusError,
+			map[string]CheckStatus{"test-a": CheckStatusOK},
+		},
+		{
+			"a kind which was not selected",
+			[]*ObjectResult{
+				newTestObjectResult("test-a", "a1", CheckStatusOK),
+				newTestObjectResult("test-d", "d1", CheckStatusOK),
+			},
+			nil,
+			CheckStatusOK,
+			map[string]CheckStatus{"test-a": CheckStatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := NewBatchResult("/clusters/zone-a.json")
+			if cluster.Name != "zone-a" || cluster.Status != CheckStatusSkipped {
+				t.Fatalf("NewBatchResult() = %s %s, want zone-a %s", cluster.Name, cluster.Status, CheckStatusSkipped)
+			}
+
+			cluster.SetResults("check-ci", robjsFuncs, tt.results, tt.discoveryErrs)
+
+			if cluster.Status != tt.wantStatus {
+				t.Errorf("SetResults() status = %s, want %s", cluster.Status, tt.wantStatus)
+			}
+			if !maps.Equal(cluster.Objects, tt.wantObjects) {
+				t.Errorf("SetResults() objects = %v, want %v", cluster.Objects, tt.wantObjects)
+			}
+			if cluster.Report == nil || cluster.Report.Command != "check-ci" {
+				t.Errorf("SetResults() did not keep the report of check-ci")
+			}
+		})
+	}
+}
+
+func TestNewBatchReport(t *testing.T) {
+	registerTestKinds(t, map[string][]string{
+		"test-a": nil,
+		"test-b": nil,
+		"test-c": nil,
+	})
+
+	failed := NewBatchResult("bad.json")
+	failed.Fail(errors.New("test error"))
+
+	clusters := []*BatchResult{
+		{Name: "zone-a", Status: CheckStatusOK, Objects: map[string]CheckStatus{"test-c": CheckStatusOK}},
+		{Name: "zone-b", Status: CheckStatusNotOK, Objects: map[string]CheckStatus{"test-a": CheckStatusNotOK, "test-c": CheckStatusOK}},
+	}
+
+	report := NewBatchReport("check-batch", clusters)
+	if report.Status != CheckStatusNotOK {
+		t.Errorf("NewBatchReport() status = %s, want %s", report.Status, CheckStatusNotOK)
+	}
+	// The columns are in the order of the registry, without the kinds nobody has.
+	if want := []string{"test-a", "test-c"}; !slices.Equal(report.ObjectTypes, want) {
+		t.Errorf("NewBatchReport() object types = %v, want %v", report.ObjectTypes, want)
+	}
+
+	report = NewBatchReport("check-batch", append(clusters, failed))
+	if report.Status != CheckStatusError {
+		t.Errorf("NewBatchReport() with a failed cluster status = %s, want %s", report.Status, CheckStatusError)
+	}
+
+	report = NewBatchReport("check-batch", nil)
+	if report.Status != CheckStatusOK || len(report.ObjectTypes) != 0 {
+		t.Errorf("NewBatchReport() without clusters = %s %v, want %s", report.Status, report.ObjectTypes, CheckStatusOK)
+	}
+}
+
+func TestBatchReportExitError(t *testing.T) {
+	tests := []struct {
+		name      string
+		cancelled bool
+		statuses  []CheckStatus
+		wantCode  int
+	}{
+		{"all OK", false, []CheckStatus{CheckStatusOK, CheckStatusOK}, exitCodeOK},
+		{"no clusters", false, nil, exitCodeOK},
+		{"failed checks", false, []CheckStatus{CheckStatusOK, CheckStatusNotOK}, exitCodeChecksFailed},
+		{"a cluster which was not checked", false, []CheckStatus{CheckStatusOK, CheckStatusSkipped}, exitCodeChecksFailed},
+		{"errors before failed checks", false, []CheckStatus{CheckStatusNotOK, CheckStatusError, CheckStatusOK}, exitCodeDiscoveryErrors},
+		{"an interruption before errors", true, []CheckStatus{CheckStatusError, CheckStatusNotOK}, exitCodeInterrupted},
+		{"an interruption of OK clusters", true, []CheckStatus{CheckStatusOK}, exitCodeInterrupted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				clusters []*BatchResult
+				exitErr  *ExitError
+				code     = exitCodeOK
+			)
+
+			for _, status := range tt.statuses {
+				clusters = append(clusters, &BatchResult{Name: "zone", Status: status})
+			}
+
+			report := NewBatchReport("check-batch", clusters)
+			report.Cancelled = tt.cancelled
+
+			err := report.ExitError()
+			if err != nil {
+				if !errors.As(err, &exitErr) {
+					t.Fatalf("ExitError() returned %v, want an *ExitError", err)
+				}
+				code = exitErr.Code
+			}
+			if code != tt.wantCode {
+				t.Errorf("ExitError() exit code = %d, want %d", code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// CallCache remembers the results of the IBM Cloud calls which list or get resources for
+// the length of a run, so that the objects which need the same listing share one call.
+// A call which changes the resources must invalidate the results it makes stale.
+type CallCache struct {
+	lock sync.Mutex
+
+	// The results, keyed by cacheKey.
+	entries map[string]*cacheEntry
+
+	// How many calls were answered from the cache and how many went to IBM Cloud.
+	hits   int
+	misses int
+}
+
+type cacheEntry struct {
+	// Closed once value and err are set.
+	ready chan struct{}
+
+	value any
+	err   error
+}
+
+func NewCallCache() *CallCache {
+	return &CallCache{
+		entries: make(map[string]*cacheEntry),
+	}
+}
+
+// cacheKey returns the key of a call with its arguments, for example
+// "pvs.instance(GUID,ID)".
+func cacheKey(call string, args ...string) string {
+	return call + "(" + strings.Join(args, ",") + ")"
+}
+
+// cachedCall returns the result of fetch, which is only called the first time the key is
+// asked for.  Callers which ask for a key while it is being fetched wait for that result
+// instead of making the same call.  Errors are not remembered, so the next caller tries
+// again.  A nil cache always calls fetch.
+func cachedCall[T any](cache *CallCache, key string, fetch func() (T, error)) (T, error) {
+	var (
+		entry   *cacheEntry
+		found   bool
+		fetched bool
+		value   T
+	)
+
+	if cache == nil {
+		return fetch()
+	}
+
+	cache.lock.Lock()
+	entry, found = cache.entries[key]
+	if found {
+		cache.hits++
+		cache.lock.Unlock()
+
+		<-entry.ready
+		log.Debugf("cachedCall: HIT  %s", key)
+		if entry.err != nil {
+			return value, entry.err
+		}
+		return entry.value.(T), nil
+	}
+
+	entry = &cacheEntry{ready: make(chan struct{})}
+	cache.entries[key] = entry
+	cache.misses++
+	cache.lock.Unlock()
+
+	// The waiters are released even if fetch panics, they get an error then.
+	defer func() {
+		if !fetched {
+			entry.err = fmt.Errorf("Error: The call %s did not return", key)
+		}
+		if entry.err != nil {
+			cache.lock.Lock()
+			if cache.entries[key] == entry {
+				delete(cache.entries, key)
+			}
+			cache.lock.Unlock()
+		}
+		close(entry.ready)
+	}()
+
+	log.Debugf("cachedCall: MISS %s", key)
+	value, entry.err = fetch()
+	entry.value = value
+	fetched = true
+
+	return value, entry.err
+}
+
+// Invalidate forgets the results whose keys start with one of the prefixes.  Pass a
+// cacheKey to forget one call, or just the call and "(" to forget it for all arguments.
+func (cache *CallCache) Invalidate(prefixes ...string) {
+	if cache == nil {
+		return
+	}
+
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	for key := range cache.entries {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) {
+				log.Debugf("Invalidate: %s", key)
+				delete(cache.entries, key)
+				break
+			}
+		}
+	}
+}
+
+// Stats returns how many calls were answered from the cache and how many were not.
+func (cache *CallCache) Stats() (int, int) {
+	if cache == nil {
+		return 0, 0
+	}
+
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	return cache.hits, cache.misses
+}
@@ -0,0 +1,255 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitForCacheHits waits until the cache answered hits calls, which are then waiting for
+// the call in flight.
+func waitForCacheHits(t *testing.T, cache *CallCache, hits int) {
+	t.Helper()
+
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(time.Millisecond) {
+		if got, _ := cache.Stats(); got >= hits {
+			return
+		}
+	}
+
+	t.Fatalf("the cache did not answer %d calls", hits)
+}
+
+func TestCachedCallSingleFlight(t *testing.T) {
+	var (
+		cache   = NewCallCache()
+		release = make(chan struct{})
+		started = make(chan struct{})
+		mutex   sync.Mutex
+		fetches int
+		wg      sync.WaitGroup
+		values  = make([]int, 5)
+		errs    = make([]error, 5)
+	)
+
+	fetch := func() (int, error) {
+		mutex.Lock()
+		fetches++
+		mutex.Unlock()
+
+		close(started)
+		<-release
+		return 42, nil
+	}
+
+	wg.Add(len(values))
+	go func() {
+		defer wg.Done()
+		values[0], errs[0] = cachedCall(cache, cacheKey("test.list", "a"), fetch)
+	}()
+	<-started
+
+	for i := 1; i < len(values); i++ {
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = cachedCall(cache, cacheKey("test.list", "a"), fetch)
+		}()
+	}
+	waitForCacheHits(t, cache, len(values)-1)
+
+	close(release)
+	wg.Wait()
+
+	if fetches != 1 {
+		t.Errorf("cachedCall() called fetch %d times, want 1", fetches)
+	}
+	for i := range values {
+		if values[i] != 42 || errs[i] != nil {
+			t.Errorf("cachedCall() %d = %d, %v, want 42", i, values[i], errs[i])
+		}
+	}
+	if hits, misses := cache.Stats(); hits != len(values)-1 || misses != 1 {
+		t.Errorf("Stats() = %d, %d, want %d, 1", hits, misses, len(values)-1)
+	}
+}
+
+func TestCachedCallErrorsAreNotRemembered(t *testing.T) {
+	var (
+		cache   = NewCallCache()
+		fetches int
+	)
+
+	fetch := func() (int, error) {
+		fetches++
+		if fetches == 1 {
+			return 0, errors.New("test error")
+		}
+		return fetches, nil
+	}
+
+	if _, err := cachedCall(cache, "test.list()", fetch); err == nil {
+		t.Fatalf("cachedCall() did not return the error of fetch")
+	}
+	if value, err := cachedCall(cache, "test.list()", fetch); value != 2 || err != nil {
+		t.Errorf("cachedCall() after an error = %d, %v, want 2", value, err)
+	}
+	if value, err := cachedCall(cache, "test.list()", fetch); value != 2 || err != nil {
+		t.Errorf("cachedCall() after a result = %d, %v, want 2", value, err)
+	}
+}
+
+func TestCachedCallPanic(t *testing.T) {
+	var (
+		cache     = NewCallCache()
+		release   = make(chan struct{})
+		started   = make(chan struct{})
+		recovered = make(chan any)
+		waiterErr = make(chan error)
+	)
+
+	go func() {
+		defer func() {
+			recovered <- recover()
+		}()
+		_, _ = cachedCall(cache, "test.list()", func() (int, error) {
+			close(started)
+			<-release
+			panic("test panic")
+		})
+	}()
+	<-started
+
+	go func() {
+		_, err := cachedCall(cache, "test.list()", func() (int, error) {
+			return 0, nil
+		})
+		waiterErr <- err
+	}()
+	waitForCacheHits(t, cache, 1)
+
+	close(release)
+	if r := <-recovered; r != "test panic" {
+		t.Errorf("cachedCall() recovered %v, want the panic of fetch", r)
+	}
+
+	select {
+	case err := <-waiterErr:
+		if err == nil {
+			t.Errorf("cachedCall() waiting for a fetch which panicked returned no error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("cachedCall() waiting for a fetch which panicked did not return")
+	}
+
+	if value, err := cachedCall(cache, "test.list()", func() (int, error) { return 7, nil }); value != 7 || err != nil {
+		t.Errorf("cachedCall() after a panic = %d, %v, want 7", value, err)
+	}
+}
+
+func TestCallCacheInvalidate(t *testing.T) {
+	var (
+		keys = []string{
+			cacheKey("pvs.instances", "g1"),
+			cacheKey("pvs.instance", "g1", "i1"),
+			cacheKey("pvs.instance", "g1", "i2"),
+			cacheKey("pvs.instance", "g2", "i1"),
+			cacheKey("pvs.volumes", "g1"),
+		}
+	)
+
+	tests := []struct {
+		name      string
+		prefixes  []string
+		wantFetch []string
+	}{
+		{
+			"nothing",
+			nil,
+			nil,
+		},
+		{
+			"one call",
+			[]string{cacheKey("pvs.instance", "g1", "i1")},
+			[]string{cacheKey("pvs.instance", "g1", "i1")},
+		},
+		{
+			"a call for all arguments",
+			[]string{"pvs.instance("},
+			[]string{cacheKey("pvs.instance", "g1", "i1"), cacheKey("pvs.instance", "g1", "i2"), cacheKey("pvs.instance", "g2", "i1")},
+		},
+		{
+			"a call for the first argument",
+			[]string{"pvs.instance(g1,"},
+			[]string{cacheKey("pvs.instance", "g1", "i1"), cacheKey("pvs.instance", "g1", "i2")},
+		},
+		{
+			"several prefixes",
+			[]string{cacheKey("pvs.instances", "g1"), "pvs.volumes("},
+			[]string{cacheKey("pvs.instances", "g1"), cacheKey("pvs.volumes", "g1")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				cache   = NewCallCache()
+				fetched []string
+			)
+
+			for _, key := range keys {
+				_, _ = cachedCall(cache, key, func() (string, error) {
+					return key, nil
+				})
+			}
+
+			cache.Invalidate(tt.prefixes...)
+
+			for _, key := range keys {
+				value, err := cachedCall(cache, key, func() (string, error) {
+					fetched = append(fetched, key)
+					return key, nil
+				})
+				if value != key || err != nil {
+					t.Errorf("cachedCall(%s) = %q, %v, want %q", key, value, err, key)
+				}
+			}
+
+			if !slices.Equal(fetched, tt.wantFetch) {
+				t.Errorf("Invalidate(%v) made the cache fetch %v again, want %v", tt.prefixes, fetched, tt.wantFetch)
+			}
+		})
+	}
+}
+
+func TestCachedCallNilCache(t *testing.T) {
+	var (
+		fetches int
+	)
+
+	for i := 0; i < 2; i++ {
+		_, _ = cachedCall(nil, "test.list()", func() (int, error) {
+			fetches++
+			return fetches, nil
+		})
+	}
+
+	if fetches != 2 {
+		t.Errorf("cachedCall() without a cache called fetch %d times, want 2", fetches)
+	}
+}
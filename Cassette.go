@@ -0,0 +1,503 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	gohttp "net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// What secrets are replaced with in a cassette.
+	redactedValue = "REDACTED"
+)
+
+var (
+	// Set by -record or -replay, otherwise nil and the SDK clients talk to IBM Cloud.
+	cassette *Cassette
+
+	// The headers which carry credentials.
+	redactedHeaders = []string{
+		"Authorization",
+		"Cookie",
+		"Set-Cookie",
+		"X-Auth-Refresh-Token",
+		"X-Auth-Token",
+	}
+
+	// The form and JSON fields which carry credentials.
+	redactedFields = map[string]bool{
+		"access_token":            true,
+		"api_key":                 true,
+		"apikey":                  true,
+		"client_secret":           true,
+		"delegated_refresh_token": true,
+		"ims_token":               true,
+		"password":                true,
+		"refresh_token":           true,
+		"uaa_refresh_token":       true,
+		"uaa_token":               true,
+	}
+)
+
+// Cassette records the HTTP exchanges of the IBM Cloud SDK clients into a directory, or
+// replays them from a directory without using the network.  Every exchange is saved in
+// its own file as soon as it completes, so an interrupted run still leaves a usable
+// cassette.  The API key, the tokens and the cookies are redacted before saving.
+//
+// When replaying, a request is answered with the first unused recorded response for the
+// same method, URL and body.  Once they are used up, the last one is answered again so
+// that, for example, extra IAM token requests still work.
+type Cassette struct {
+	mutex sync.Mutex
+
+	directory string
+
+	replaying bool
+
+	// Strings, like the API key, which are redacted wherever they appear.
+	secrets []string
+
+	// The number of exchanges recorded so far.
+	count int
+
+	// The recorded exchanges keyed by cassetteKey.
+	interactions map[string][]*CassetteInteraction
+
+	// How many of the recorded exchanges for a key have been used.
+	used map[string]int
+}
+
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+	// The transport error, instead of a response.
+	Error string `json:"error,omitempty"`
+}
+
+type CassetteRequest struct {
+	Method string        `json:"method"`
+	URL    string        `json:"url"`
+	Header gohttp.Header `json:"header,omitempty"`
+	Body   string        `json:"body,omitempty"`
+}
+
+type CassetteResponse struct {
+	StatusCode int           `json:"statusCode"`
+	Header     gohttp.Header `json:"header,omitempty"`
+	Body       string        `json:"body,omitempty"`
+}
+
+// NewCassetteRecorder returns a cassette which records into directory.  The secrets are
+// redacted wherever they appear.
+func NewCassetteRecorder(directory string, secrets ...string) (*Cassette, error) {
+	var (
+		err error
+	)
+
+	err = os.MkdirAll(directory, 0o750)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not create the cassette directory %s (%v)", directory, err)
+	}
+
+	return &Cassette{
+		directory: directory,
+		secrets:   secrets,
+	}, nil
+}
+
+// NewCassettePlayer returns a cassette which replays the exchanges recorded in directory.
+func NewCassettePlayer(directory string) (*Cassette, error) {
+	var (
+		files       []string
+		content     []byte
+		interaction *CassetteInteraction
+		key         string
+		cassette    *Cassette
+		err         error
+	)
+
+	files, err = filepath.Glob(filepath.Join(directory, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not list the cassette directory %s (%v)", directory, err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("Error: The cassette directory %s has no recordings", directory)
+	}
+	sort.Strings(files)
+
+	cassette = &Cassette{
+		directory:    directory,
+		replaying:    true,
+		interactions: make(map[string][]*CassetteInteraction),
+		used:         make(map[string]int),
+	}
+
+	for _, file := range files {
+		content, err = os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("Error: Could not read the cassette file %s (%v)", file, err)
+		}
+
+		interaction = new(CassetteInteraction)
+		err = json.Unmarshal(content, interaction)
+		if err != nil {
+			return nil, fmt.Errorf("Error: Could not parse the cassette file %s (%v)", file, err)
+		}
+
+		key = cassetteKey(interaction.Request.Method, interaction.Request.URL, interaction.Request.Body)
+		cassette.interactions[key] = append(cassette.interactions[key], interaction)
+	}
+	log.Debugf("NewCassettePlayer: loaded %d recordings from %s", len(files), directory)
+
+	return cassette, nil
+}
+
+// NewCassette returns a recorder, a player or nil depending on which of the -record and
+// -replay flags were used.
+func NewCassette(record string, replay string, secrets ...string) (*Cassette, error) {
+	switch {
+	case record != "" && replay != "":
+		return nil, fmt.Errorf("Error: -record and -replay cannot be used together")
+	case record != "":
+		return NewCassetteRecorder(record, secrets...)
+	case replay != "":
+		return NewCassettePlayer(replay)
+	}
+
+	return nil, nil
+}
+
+// IsReplaying returns true when the cassette answers the requests instead of IBM Cloud.
+func (cassette *Cassette) IsReplaying() bool {
+	return cassette != nil && cassette.replaying
+}
+
+// RoundTrip implements gohttp.RoundTripper.
+func (cassette *Cassette) RoundTrip(request *gohttp.Request) (*gohttp.Response, error) {
+	var (
+		requestBody []byte
+		err         error
+	)
+
+	if request.Body != nil {
+		requestBody, err = io.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		request.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	if cassette.replaying {
+		return cassette.replay(request, requestBody)
+	}
+
+	return cassette.record(request, requestBody)
+}
+
+func (cassette *Cassette) record(request *gohttp.Request, requestBody []byte) (*gohttp.Response, error) {
+	var (
+		interaction  CassetteInteraction
+		response     *gohttp.Response
+		responseBody []byte
+		err          error
+	)
+
+	interaction.Request = CassetteRequest{
+		Method: request.Method,
+		URL:    cassette.redactString(request.URL.String()),
+		Header: cassette.redactHeader(request.Header),
+		Body:   cassette.redactBody(request.Header.Get("Content-Type"), requestBody),
+	}
+
+	response, err = gohttp.DefaultTransport.RoundTrip(request)
+	if err != nil {
+		interaction.Error = cassette.redactString(err.Error())
+		cassette.save(&interaction)
+		return nil, err
+	}
+
+	responseBody, err = readResponseBody(response)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction.Response = CassetteResponse{
+		StatusCode: response.StatusCode,
+		Header:     cassette.redactHeader(response.Header),
+		Body:       cassette.redactBody(response.Header.Get("Content-Type"), responseBody),
+	}
+	// The body may be shorter once it is redacted.
+	interaction.Response.Header.Del("Content-Length")
+	cassette.save(&interaction)
+
+	response.Body = io.NopCloser(bytes.NewReader(responseBody))
+	response.ContentLength = int64(len(responseBody))
+
+	return response, nil
+}
+
+func (cassette *Cassette) replay(request *gohttp.Request, requestBody []byte) (*gohttp.Response, error) {
+	var (
+		key          string
+		interactions []*CassetteInteraction
+		interaction  *CassetteInteraction
+		used         int
+	)
+
+	if err := request.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	key = cassetteKey(
+		request.Method,
+		redactURL(request.URL.String()),
+		cassette.redactBody(request.Header.Get("Content-Type"), requestBody),
+	)
+
+	cassette.mutex.Lock()
+	interactions = cassette.interactions[key]
+	used = cassette.used[key]
+	if used < len(interactions) {
+		cassette.used[key] = used + 1
+	} else {
+		used = len(interactions) - 1
+	}
+	cassette.mutex.Unlock()
+
+	if used < 0 {
+		return nil, fmt.Errorf("Error: No recording for %s %s in %s", request.Method, request.URL, cassette.directory)
+	}
+	interaction = interactions[used]
+	log.Debugf("Cassette.replay: %s %s returns %d", request.Method, request.URL, interaction.Response.StatusCode)
+
+	if interaction.Error != "" {
+		return nil, errors.New(interaction.Error)
+	}
+
+	return &gohttp.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, gohttp.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Response.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       request,
+	}, nil
+}
+
+// save writes the exchange to the next file of the cassette.
+func (cassette *Cassette) save(interaction *CassetteInteraction) {
+	var (
+		content []byte
+		file    string
+		err     error
+	)
+
+	content, err = json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		log.Debugf("Cassette.save: json.MarshalIndent returns %v", err)
+		return
+	}
+
+	cassette.mutex.Lock()
+	defer cassette.mutex.Unlock()
+
+	cassette.count++
+	file = filepath.Join(cassette.directory, fmt.Sprintf("%05d.json", cassette.count))
+
+	err = os.WriteFile(file, content, 0o600)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Could not write the cassette file %s (%v)\n", file, err)
+	}
+}
+
+// readResponseBody reads the whole response body and decompresses it, if needed.
+func readResponseBody(response *gohttp.Response) ([]byte, error) {
+	var (
+		reader io.Reader = response.Body
+		body   []byte
+		err    error
+	)
+
+	defer response.Body.Close()
+
+	if strings.EqualFold(response.Header.Get("Content-Encoding"), "gzip") {
+		reader, err = gzip.NewReader(response.Body)
+		if err != nil {
+			return nil, err
+		}
+		response.Header.Del("Content-Encoding")
+		response.Header.Del("Content-Length")
+	}
+
+	body, err = io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func cassetteKey(method string, url string, body string) string {
+	return method + " " + url + "\n" + body
+}
+
+// redactURL removes the credentials from the query of a URL.
+func redactURL(rawURL string) string {
+	var (
+		parsed *url.URL
+		query  url.Values
+		err    error
+	)
+
+	parsed, err = url.Parse(rawURL)
+	if err != nil || parsed.RawQuery == "" {
+		return rawURL
+	}
+
+	query = parsed.Query()
+	for field := range query {
+		if redactedFields[strings.ToLower(field)] {
+			query.Set(field, redactedValue)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
+
+// redactString replaces the secrets and the credentials in a URL.
+func (cassette *Cassette) redactString(s string) string {
+	for _, secret := range cassette.secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redactedValue)
+		}
+	}
+
+	return redactURL(s)
+}
+
+func (cassette *Cassette) redactHeader(header gohttp.Header) gohttp.Header {
+	var (
+		result = header.Clone()
+	)
+
+	for _, name := range redactedHeaders {
+		if result.Get(name) != "" {
+			result.Set(name, redactedValue)
+		}
+	}
+
+	return result
+}
+
+// redactBody redacts the credentials in a form or a JSON body.
+func (cassette *Cassette) redactBody(contentType string, body []byte) string {
+	var (
+		form      url.Values
+		document  any
+		redacted  []byte
+		bodyValue string
+		err       error
+	)
+
+	for _, secret := range cassette.secrets {
+		if secret != "" {
+			body = bytes.ReplaceAll(body, []byte(secret), []byte(redactedValue))
+		}
+	}
+	bodyValue = string(body)
+
+	switch {
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		form, err = url.ParseQuery(bodyValue)
+		if err != nil {
+			return bodyValue
+		}
+		for field := range form {
+			if redactedFields[strings.ToLower(field)] {
+				form.Set(field, redactedValue)
+			}
+		}
+		return form.Encode()
+	case strings.Contains(contentType, "json"):
+		err = json.Unmarshal(body, &document)
+		if err != nil {
+			return bodyValue
+		}
+		if !redactJSON(document) {
+			return bodyValue
+		}
+		redacted, err = json.Marshal(document)
+		if err != nil {
+			return bodyValue
+		}
+		return string(redacted)
+	}
+
+	return bodyValue
+}
+
+// redactJSON redacts the credentials in a decoded JSON document and returns true if
+// something was redacted.  An access token keeps its claims, which are used to find the
+// account, but loses its signature so that it cannot be used.
+func redactJSON(document any) bool {
+	var (
+		redacted = false
+	)
+
+	switch value := document.(type) {
+	case map[string]any:
+		for field, fieldValue := range value {
+			if !redactedFields[strings.ToLower(field)] {
+				if redactJSON(fieldValue) {
+					redacted = true
+				}
+				continue
+			}
+
+			token, ok := fieldValue.(string)
+			if ok && field == "access_token" && strings.Count(token, ".") == 2 {
+				value[field] = token[:strings.LastIndex(token, ".")+1] + redactedValue
+			} else {
+				value[field] = redactedValue
+			}
+			redacted = true
+		}
+	case []any:
+		for _, item := range value {
+			if redactJSON(item) {
+				redacted = true
+			}
+		}
+	}
+
+	return redacted
+}
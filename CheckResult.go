@@ -0,0 +1,203 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+)
+
+// CheckStatus is the outcome of a single check, or of all the checks of an object.
+type CheckStatus string
+
+const (
+	// CheckStatusOK means the check passed.
+	CheckStatusOK CheckStatus = "OK"
+
+	// CheckStatusNotOK means the check ran and found a problem.
+	CheckStatusNotOK CheckStatus = "NOTOK"
+
+	// CheckStatusError means the check could not be run, for example the object was not found.
+	CheckStatusError CheckStatus = "ERROR"
+
+	// CheckStatusSkipped means the check was not run because an object it depends on failed.
+	CheckStatusSkipped CheckStatus = "SKIPPED"
+)
+
+// CheckSeverity describes how much a check matters to the health of the cluster.
+type CheckSeverity string
+
+const (
+	CheckSeverityInfo     CheckSeverity = "info"
+	CheckSeverityWarning  CheckSeverity = "warning"
+	CheckSeverityCritical CheckSeverity = "critical"
+)
+
+// CheckResult is the result of one check made against a RunnableObject.
+type CheckResult struct {
+	ID       string            `json:"id"`
+	Status   CheckStatus       `json:"status"`
+	Severity CheckSeverity     `json:"severity"`
+	Message  string            `json:"message"`
+	Evidence map[string]string `json:"evidence,omitempty"`
+}
+
+// ObjectResult is the collection of checks made against one RunnableObject.
+type ObjectResult struct {
+	ObjectType string         `json:"objectType"`
+	Name       string         `json:"name"`
+	CRN        string         `json:"crn,omitempty"`
+	Status     CheckStatus    `json:"status"`
+	Checks     []*CheckResult `json:"checks"`
+}
+
+// statusRank orders the statuses from best to worst.
+func statusRank(status CheckStatus) int {
+	switch status {
+	case CheckStatusOK:
+		return 0
+	case CheckStatusSkipped:
+		return 1
+	case CheckStatusNotOK:
+		return 2
+	case CheckStatusError:
+		return 3
+	}
+	return -1
+}
+
+// NewObjectResult returns an empty, OK, result for the runnable object.
+func NewObjectResult(ro RunnableObject, name string) *ObjectResult {
+	var (
+		objectType string
+		crn        string
+		err        error
+	)
+
+	objectType, err = ro.ObjectName()
+	if err != nil {
+		objectType = "(error)"
+	}
+
+	crn, err = ro.CRN()
+	if err != nil || crn == "(error)" {
+		crn = ""
+	}
+
+	return &ObjectResult{
+		ObjectType: objectType,
+		Name:       name,
+		CRN:        crn,
+		Status:     CheckStatusOK,
+		Checks:     make([]*CheckResult, 0),
+	}
+}
+
+// NewSkippedResult returns the result of an object which was not checked because
+// the objects named dependency failed.
+func NewSkippedResult(ro RunnableObject, dependency string) *ObjectResult {
+	var (
+		result *ObjectResult
+		name   string
+		err    error
+	)
+
+	name, err = ro.Name()
+	if err != nil || name == "(error)" {
+		name = ""
+	}
+
+	result = NewObjectResult(ro, name)
+	result.AddCheck("dependency", CheckStatusSkipped, CheckSeverityInfo, fmt.Sprintf("skipped because %s failed", dependency)).
+		WithEvidence("dependency", dependency)
+
+	return result
+}
+
+// NewCancelledResult returns the result of an object which was not checked because
+// the command was interrupted.
+func NewCancelledResult(ro RunnableObject) *ObjectResult {
+	var (
+		result *ObjectResult
+		name   string
+		err    error
+	)
+
+	name, err = ro.Name()
+	if err != nil || name == "(error)" {
+		name = ""
+	}
+
+	result = NewObjectResult(ro, name)
+	result.AddCheck("cancelled", CheckStatusSkipped, CheckSeverityInfo, "not checked because the command was interrupted")
+
+	return result
+}
+
+// AddCheck appends a check to the result and updates the overall status.
+func (r *ObjectResult) AddCheck(id string, status CheckStatus, severity CheckSeverity, message string) *CheckResult {
+	var (
+		check *CheckResult
+	)
+
+	check = &CheckResult{
+		ID:       id,
+		Status:   status,
+		Severity: severity,
+		Message:  message,
+	}
+
+	r.Checks = append(r.Checks, check)
+
+	if statusRank(status) > statusRank(r.Status) {
+		r.Status = status
+	}
+
+	return check
+}
+
+// AddOK records a passing check.
+func (r *ObjectResult) AddOK(id string, format string, a ...any) *CheckResult {
+	return r.AddCheck(id, CheckStatusOK, CheckSeverityInfo, fmt.Sprintf(format, a...))
+}
+
+// AddNotOK records a failing check.
+func (r *ObjectResult) AddNotOK(id string, format string, a ...any) *CheckResult {
+	return r.AddCheck(id, CheckStatusNotOK, CheckSeverityCritical, fmt.Sprintf(format, a...))
+}
+
+// AddWarning records a failing check which is not, by itself, fatal to the cluster.
+func (r *ObjectResult) AddWarning(id string, format string, a ...any) *CheckResult {
+	return r.AddCheck(id, CheckStatusNotOK, CheckSeverityWarning, fmt.Sprintf(format, a...))
+}
+
+// AddError records a check which could not be made.
+func (r *ObjectResult) AddError(id string, format string, a ...any) *CheckResult {
+	return r.AddCheck(id, CheckStatusError, CheckSeverityCritical, fmt.Sprintf(format, a...))
+}
+
+// IsOK returns true if every check of the object passed.
+func (r *ObjectResult) IsOK() bool {
+	return r.Status == CheckStatusOK
+}
+
+// WithEvidence attaches a piece of supporting data to the check.
+func (c *CheckResult) WithEvidence(key string, value string) *CheckResult {
+	if c.Evidence == nil {
+		c.Evidence = make(map[string]string)
+	}
+	c.Evidence[key] = value
+
+	return c
+}
@@ -0,0 +1,446 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// CleanupMode is what check-ci -shouldClean does with the leftover resources.
+type CleanupMode string
+
+const (
+	// Only report the leftover resources.
+	CleanupModeNone CleanupMode = "false"
+
+	// Delete the leftover resources as they are found.
+	CleanupModeNow CleanupMode = "true"
+
+	// Print the plan of what would be deleted, without deleting anything.
+	CleanupModePlan CleanupMode = "plan"
+
+	// Delete what a saved plan says, and nothing else.
+	CleanupModeApply CleanupMode = "apply"
+)
+
+// The kinds of resources of a cleanup plan.
+const (
+	CleanupKindPVMInstance = "pvm-instance"
+	CleanupKindVolume      = "volume"
+	CleanupKindDHCPServer  = "dhcp-server"
+	CleanupKindNetworkPort = "network-port"
+	CleanupKindNetwork     = "network"
+	CleanupKindImage       = "image"
+)
+
+// CleanupOutcome is what happened to one resource of a cleanup.
+type CleanupOutcome string
+
+const (
+	CleanupOutcomeDeleted  CleanupOutcome = "deleted"
+	CleanupOutcomeGone     CleanupOutcome = "already gone"
+	CleanupOutcomeTimedOut CleanupOutcome = "timed out"
+	CleanupOutcomeFailed   CleanupOutcome = "failed"
+)
+
+// CleanupPolicy says how often a deletion is tried and how long the cleanup waits for
+// PowerVS to finish it.
+type CleanupPolicy struct {
+	// How many times a deletion is tried, and how many times in a row the jobs of the
+	// resource may fail to be listed.
+	MaxAttempts int
+
+	// The wait between two looks at whether the jobs of a resource finished or whether
+	// it is gone, and between two attempts.
+	PollInterval time.Duration
+
+	// How long the deletion of one resource may take, including the waits.
+	Timeout time.Duration
+}
+
+var (
+	// The policy of check-ci -shouldClean, see -cleanupTimeout.
+	cleanupPolicy = CleanupPolicy{
+		MaxAttempts:  3,
+		PollInterval: 10 * time.Second,
+		Timeout:      15 * time.Minute,
+	}
+)
+
+// ParseCleanupMode parses the -shouldClean flag.
+func ParseCleanupMode(mode string) (CleanupMode, error) {
+	switch CleanupMode(strings.ToLower(mode)) {
+	case CleanupModeNone:
+		return CleanupModeNone, nil
+	case CleanupModeNow:
+		return CleanupModeNow, nil
+	case CleanupModePlan:
+		return CleanupModePlan, nil
+	case CleanupModeApply:
+		return CleanupModeApply, nil
+	}
+
+	return "", fmt.Errorf("Error: shouldClean is not true/false/plan/apply (%s)", mode)
+}
+
+// CleanupAction is one deletion of a cleanup plan.
+type CleanupAction struct {
+	Step int `json:"step"`
+
+	// The object which owns the resource, for example the service instance.
+	ObjectType string `json:"objectType"`
+	Object     string `json:"object"`
+
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	// The network of a network port.
+	Parent string `json:"parent,omitempty"`
+
+	Reason string `json:"reason,omitempty"`
+}
+
+// CleanupPlan is the ordered list of the deletions which check-ci -shouldClean would make.
+type CleanupPlan struct {
+	Command   string    `json:"command"`
+	Version   string    `json:"version"`
+	Release   string    `json:"release"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// What the plan was made for, a plan is only applied to the same zone.
+	Metadata string `json:"metadata"`
+	Zone     string `json:"zone"`
+
+	// The CRNs of the objects which own the resources, by object type and name, for
+	// example of the service instance.  A plan is only applied to the same objects.
+	CRNs map[string]string `json:"crns"`
+
+	Actions []*CleanupAction `json:"actions"`
+}
+
+// CleanupPlanner is implemented by the objects which check-ci can clean up.
+type CleanupPlanner interface {
+	// PlanCleanup returns the deletions which -shouldClean true would make, in the
+	// order they have to be made in.
+	PlanCleanup(ctx context.Context) ([]*CleanupAction, error)
+
+	// ApplyCleanup makes one deletion of a plan and waits until it is finished.
+	ApplyCleanup(ctx context.Context, action *CleanupAction) (CleanupOutcome, error)
+}
+
+// planCleanup asks every object which can be cleaned up for its deletions.
+func planCleanup(ctx context.Context, metadata *Metadata, robjs []RunnableObject) (*CleanupPlan, error) {
+	var (
+		plan    *CleanupPlan
+		planner CleanupPlanner
+		ok      bool
+		actions []*CleanupAction
+		crn     string
+		err     error
+	)
+
+	plan = &CleanupPlan{
+		Command:   "check-ci",
+		Version:   version,
+		Release:   release,
+		CreatedAt: time.Now().UTC(),
+		Metadata:  metadata.location,
+		Zone:      metadata.GetZone(),
+		CRNs:      make(map[string]string),
+		Actions:   make([]*CleanupAction, 0),
+	}
+
+	for _, robj := range robjs {
+		planner, ok = robj.(CleanupPlanner)
+		if !ok {
+			continue
+		}
+
+		actions, err = planner.PlanCleanup(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		crn, err = robj.CRN()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, action := range actions {
+			plan.CRNs[action.ObjectType+"/"+action.Object] = crn
+
+			action.Step = len(plan.Actions) + 1
+			plan.Actions = append(plan.Actions, action)
+		}
+	}
+
+	return plan, nil
+}
+
+// checkCleanupPlanObjects makes sure that the objects which the plan deletes from are the
+// ones it was made for, and not for example another service instance with the same name.
+func checkCleanupPlanObjects(plan *CleanupPlan, robjs []RunnableObject) error {
+	var (
+		crns = make(map[string]string)
+		key  string
+		crn  string
+		ok   bool
+		err  error
+	)
+
+	for _, robj := range robjs {
+		if _, ok = robj.(CleanupPlanner); !ok {
+			continue
+		}
+		objectType, _ := robj.ObjectName()
+		name, _ := robj.Name()
+		crn, err = robj.CRN()
+		if err != nil {
+			return err
+		}
+		crns[objectType+"/"+name] = crn
+	}
+
+	for _, action := range plan.Actions {
+		key = action.ObjectType + "/" + action.Object
+
+		// applyCleanupPlan reports the steps of an object which is not found.
+		crn, ok = crns[key]
+		if !ok {
+			continue
+		}
+
+		if crn != plan.CRNs[key] {
+			return fmt.Errorf("Error: The cleanup plan is for the %s %s with the CRN %s, not %s", action.ObjectType, action.Object, plan.CRNs[key], crn)
+		}
+	}
+
+	return nil
+}
+
+// applyCleanupPlan makes the deletions of the plan in order and returns what happened to
+// each of them, as a result for each object.
+func applyCleanupPlan(ctx context.Context, plan *CleanupPlan, robjs []RunnableObject) []*ObjectResult {
+	var (
+		planners = make(map[string]RunnableObject)
+		results  = make(map[string]*ObjectResult)
+		ordered  = make([]*ObjectResult, 0)
+		key      string
+		robj     RunnableObject
+		result   *ObjectResult
+		outcome  CleanupOutcome
+		found    bool
+		ok       bool
+		err      error
+	)
+
+	for _, robj = range robjs {
+		if _, ok = robj.(CleanupPlanner); !ok {
+			continue
+		}
+		objectType, _ := robj.ObjectName()
+		name, _ := robj.Name()
+		planners[objectType+"/"+name] = robj
+	}
+
+	for _, action := range plan.Actions {
+		key = action.ObjectType + "/" + action.Object
+
+		robj, found = planners[key]
+
+		result, ok = results[key]
+		if !ok {
+			if found {
+				result = NewObjectResult(robj, action.Object)
+			} else {
+				result = &ObjectResult{
+					ObjectType: action.ObjectType,
+					Name:       action.Object,
+					Status:     CheckStatusOK,
+					Checks:     make([]*CheckResult, 0),
+				}
+			}
+			results[key] = result
+			ordered = append(ordered, result)
+		}
+
+		switch {
+		case !found:
+			outcome, err = CleanupOutcomeFailed, fmt.Errorf("there is no %s named %s", action.ObjectType, action.Object)
+		case ctx.Err() != nil:
+			result.AddCheck("cleanup.delete", CheckStatusSkipped, CheckSeverityInfo, fmt.Sprintf("step %d was not applied because the command was interrupted", action.Step)).
+				WithEvidence(action.Kind, action.ID)
+			continue
+		default:
+			outcome, err = robj.(CleanupPlanner).ApplyCleanup(ctx, action)
+		}
+
+		addCleanupOutcome(result, action, outcome, err)
+	}
+
+	return ordered
+}
+
+// addCleanupOutcome reports what happened to the resource of one step of a cleanup.
+func addCleanupOutcome(result *ObjectResult, action *CleanupAction, outcome CleanupOutcome, err error) {
+	var (
+		check *CheckResult
+	)
+
+	switch outcome {
+	case CleanupOutcomeDeleted:
+		check = result.AddOK("cleanup.delete", "step %d deleted %s %s (%s)", action.Step, action.Kind, action.Name, action.ID)
+	case CleanupOutcomeGone:
+		check = result.AddOK("cleanup.delete", "step %d found %s %s (%s) already deleted", action.Step, action.Kind, action.Name, action.ID)
+	case CleanupOutcomeTimedOut:
+		check = result.AddWarning("cleanup.delete", "step %d timed out deleting %s %s (%s): %v", action.Step, action.Kind, action.Name, action.ID, err)
+	default:
+		check = result.AddWarning("cleanup.delete", "step %d could not delete %s %s (%s): %v", action.Step, action.Kind, action.Name, action.ID, err)
+	}
+
+	check.WithEvidence(action.Kind, action.ID).
+		WithEvidence("outcome", string(outcome))
+}
+
+// mergeCleanupResults puts the deletions of applyCleanupPlan in front of the checks of the
+// same object.
+func mergeCleanupResults(applied []*ObjectResult, results []*ObjectResult) []*ObjectResult {
+	var (
+		merged bool
+	)
+
+	for _, appliedResult := range applied {
+		merged = false
+		for _, result := range results {
+			if result.ObjectType != appliedResult.ObjectType || result.Name != appliedResult.Name {
+				continue
+			}
+
+			result.Checks = append(appliedResult.Checks, result.Checks...)
+			if statusRank(appliedResult.Status) > statusRank(result.Status) {
+				result.Status = appliedResult.Status
+			}
+			merged = true
+			break
+		}
+
+		if !merged {
+			results = append(results, appliedResult)
+		}
+	}
+
+	return results
+}
+
+// saveCleanupPlan writes the plan as json, which is what -plan reads back.  Only the user
+// may read it, like the cassettes.
+func saveCleanupPlan(filename string, plan *CleanupPlan) error {
+	var (
+		content []byte
+		err     error
+	)
+
+	content, err = json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filename, append(content, '\n'), 0600)
+	if err != nil {
+		return fmt.Errorf("Error: Could not save the cleanup plan (%v)", err)
+	}
+
+	return nil
+}
+
+// readCleanupPlan reads a plan saved by -shouldClean plan and checks that it is for the
+// zone of the metadata.  Whether it is for the same objects is checked once they are
+// found, see checkCleanupPlanObjects.
+func readCleanupPlan(filename string, metadata *Metadata) (*CleanupPlan, error) {
+	var (
+		content []byte
+		plan    CleanupPlan
+		err     error
+	)
+
+	content, err = os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not read the cleanup plan (%v)", err)
+	}
+
+	err = json.Unmarshal(content, &plan)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not parse the cleanup plan %s (%v)", filename, err)
+	}
+
+	if plan.Command != "check-ci" {
+		return nil, fmt.Errorf("Error: %s is not a cleanup plan of check-ci", filename)
+	}
+
+	if plan.Zone != metadata.GetZone() {
+		return nil, fmt.Errorf("Error: The cleanup plan %s is for the zone %s, not for %s", filename, plan.Zone, metadata.GetZone())
+	}
+
+	for i, action := range plan.Actions {
+		if action == nil || action.ID == "" || action.Kind == "" || (action.Kind == CleanupKindNetworkPort && action.Parent == "") {
+			return nil, fmt.Errorf("Error: The step %d of the cleanup plan %s is not complete", i+1, filename)
+		}
+		if plan.CRNs[action.ObjectType+"/"+action.Object] == "" {
+			return nil, fmt.Errorf("Error: The step %d of the cleanup plan %s has no CRN of the %s %s", i+1, filename, action.ObjectType, action.Object)
+		}
+	}
+
+	return &plan, nil
+}
+
+// renderCleanupPlan writes the plan in the requested format.
+func renderCleanupPlan(w io.Writer, format OutputFormat, plan *CleanupPlan) error {
+	var (
+		tw *tabwriter.Writer
+	)
+
+	switch format {
+	case OutputFormatJSON:
+		return renderJSON(w, plan)
+	case OutputFormatYAML:
+		return renderYAML(w, plan)
+	case OutputFormatJUnit:
+		return fmt.Errorf("Error: A cleanup plan cannot be written as junit")
+	}
+
+	if len(plan.Actions) == 0 {
+		fmt.Fprintf(w, "Nothing would be deleted in %s.\n", plan.Zone)
+		return nil
+	}
+
+	fmt.Fprintf(w, "%d resource(s) would be deleted in %s, in this order:\n", len(plan.Actions), plan.Zone)
+
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "STEP\tOBJECT\tKIND\tNAME\tID\tREASON\n")
+	for _, action := range plan.Actions {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", action.Step, action.Object, action.Kind, action.Name, action.ID, action.Reason)
+	}
+
+	return tw.Flush()
+}
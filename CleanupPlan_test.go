@@ -0,0 +1,185 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestCleanupPlan returns the plan of the service instance of the test fixtures which
+// has leftovers, with the metadata it was made for.
+func newTestCleanupPlan(t *testing.T) (*CleanupPlan, *Metadata, *ServiceInstance, *FakeCloud) {
+	t.Helper()
+
+	si, fake := newTestLeftoverServiceInstance(t)
+	metadata := si.services.GetMetadata()
+
+	plan, err := planCleanup(context.Background(), metadata, []RunnableObject{si})
+	if err != nil {
+		t.Fatalf("planCleanup: %v", err)
+	}
+	if len(plan.Actions) == 0 {
+		t.Fatalf("planCleanup() returned an empty plan")
+	}
+
+	return plan, metadata, si, fake
+}
+
+func TestCleanupPlanRoundTrip(t *testing.T) {
+	setTestCleanupPolicy(t, CleanupPolicy{MaxAttempts: 3, PollInterval: time.Millisecond, Timeout: time.Second})
+
+	plan, metadata, _, _ := newTestCleanupPlan(t)
+	filename := filepath.Join(t.TempDir(), "plan.json")
+
+	err := saveCleanupPlan(filename, plan)
+	if err != nil {
+		t.Fatalf("saveCleanupPlan: %v", err)
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatalf("os.Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("saveCleanupPlan() wrote the plan with the permissions %v, want 0600", perm)
+	}
+
+	read, err := readCleanupPlan(filename, metadata)
+	if err != nil {
+		t.Fatalf("readCleanupPlan: %v", err)
+	}
+	if !read.CreatedAt.Equal(plan.CreatedAt) {
+		t.Errorf("readCleanupPlan() CreatedAt = %v, want %v", read.CreatedAt, plan.CreatedAt)
+	}
+	read.CreatedAt = plan.CreatedAt
+	if !reflect.DeepEqual(read, plan) {
+		t.Errorf("readCleanupPlan() = %+v, want %+v", read, plan)
+	}
+}
+
+func TestReadCleanupPlanRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(plan *CleanupPlan)
+		wantErr string
+	}{
+		{
+			"another zone",
+			func(plan *CleanupPlan) {
+				plan.Zone = "wdc06"
+			},
+			"is for the zone wdc06, not for dal10",
+		},
+		{
+			"another command",
+			func(plan *CleanupPlan) {
+				plan.Command = "check-create"
+			},
+			"is not a cleanup plan of check-ci",
+		},
+		{
+			"a step without an ID",
+			func(plan *CleanupPlan) {
+				plan.Actions[0].ID = ""
+			},
+			"The step 1 of the cleanup plan",
+		},
+		{
+			"a step without a CRN",
+			func(plan *CleanupPlan) {
+				plan.CRNs = nil
+			},
+			"has no CRN of the",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, metadata, _, _ := newTestCleanupPlan(t)
+			filename := filepath.Join(t.TempDir(), "plan.json")
+
+			tt.change(plan)
+
+			err := saveCleanupPlan(filename, plan)
+			if err != nil {
+				t.Fatalf("saveCleanupPlan: %v", err)
+			}
+
+			_, err = readCleanupPlan(filename, metadata)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("readCleanupPlan() returned %v, want an error with %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckCleanupPlanObjects(t *testing.T) {
+	plan, _, si, _ := newTestCleanupPlan(t)
+
+	err := checkCleanupPlanObjects(plan, []RunnableObject{si})
+	if err != nil {
+		t.Errorf("checkCleanupPlanObjects() returned %v for the objects the plan was made for", err)
+	}
+
+	// Another service instance with the same name.
+	for key := range plan.CRNs {
+		plan.CRNs[key] = "crn:v1:bluemix:public:power-iaas:dal10:a/test::other"
+	}
+
+	err = checkCleanupPlanObjects(plan, []RunnableObject{si})
+	if err == nil || !strings.Contains(err.Error(), "crn:v1:bluemix:public:power-iaas:dal10:a/test::other") {
+		t.Errorf("checkCleanupPlanObjects() returned %v, want an error with the CRN of the plan", err)
+	}
+}
+
+func TestApplyCleanupPlanAlreadyGone(t *testing.T) {
+	setTestCleanupPolicy(t, CleanupPolicy{MaxAttempts: 3, PollInterval: time.Millisecond, Timeout: time.Second})
+
+	plan, _, si, fake := newTestCleanupPlan(t)
+
+	// Someone else deleted the image between the plan and its application.
+	fake.mutex.Lock()
+	fake.powerVS["g-leftover"].Images = nil
+	fake.mutex.Unlock()
+
+	results := applyCleanupPlan(context.Background(), plan, []RunnableObject{si})
+	if len(results) != 1 {
+		t.Fatalf("applyCleanupPlan() returned %d results, want 1", len(results))
+	}
+	result := results[0]
+
+	if len(result.Checks) != len(plan.Actions) {
+		t.Fatalf("applyCleanupPlan() reported %d steps, want %d%s", len(result.Checks), len(plan.Actions), dumpChecks(result))
+	}
+	if result.Status != CheckStatusOK {
+		t.Errorf("applyCleanupPlan() status = %s, want %s%s", result.Status, CheckStatusOK, dumpChecks(result))
+	}
+
+	for i, action := range plan.Actions {
+		want := CleanupOutcomeDeleted
+		if action.Kind == CleanupKindImage {
+			want = CleanupOutcomeGone
+		}
+		if got := result.Checks[i].Evidence["outcome"]; got != string(want) {
+			t.Errorf("step %d (%s %s) outcome = %q, want %q", action.Step, action.Kind, action.ID, got, want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+
+	"github.com/IBM/ibm-cos-sdk-go/aws"
+	"github.com/IBM/ibm-cos-sdk-go/aws/request"
+	"github.com/IBM/ibm-cos-sdk-go/service/s3"
+
+	"github.com/IBM/networking-go-sdk/dnsrecordsv1"
+	"github.com/IBM/networking-go-sdk/transitgatewayapisv1"
+
+	"github.com/IBM/platform-services-go-sdk/resourcecontrollerv2"
+	"github.com/IBM/platform-services-go-sdk/resourcemanagerv2"
+
+	"github.com/IBM/vpc-go-sdk/vpcv1"
+
+	"github.com/IBM-Cloud/power-go-client/clients/instance"
+	"github.com/IBM-Cloud/power-go-client/power/models"
+)
+
+// The interfaces below are the parts of the IBM Cloud SDK clients which the objects
+// use.  They are satisfied by the SDK clients and by the FakeCloud backend.
+
+// VpcClient is the part of *vpcv1.VpcV1 which is used.
+type VpcClient interface {
+	GetVPCWithContext(ctx context.Context, options *vpcv1.GetVPCOptions) (*vpcv1.VPC, *core.DetailedResponse, error)
+	ListVpcsWithContext(ctx context.Context, options *vpcv1.ListVpcsOptions) (*vpcv1.VPCCollection, *core.DetailedResponse, error)
+	ListSubnetsWithContext(ctx context.Context, options *vpcv1.ListSubnetsOptions) (*vpcv1.SubnetCollection, *core.DetailedResponse, error)
+	GetVPCDefaultSecurityGroupWithContext(ctx context.Context, options *vpcv1.GetVPCDefaultSecurityGroupOptions) (*vpcv1.DefaultSecurityGroup, *core.DetailedResponse, error)
+	ListInstancesWithContext(ctx context.Context, options *vpcv1.ListInstancesOptions) (*vpcv1.InstanceCollection, *core.DetailedResponse, error)
+	GetInstanceWithContext(ctx context.Context, options *vpcv1.GetInstanceOptions) (*vpcv1.Instance, *core.DetailedResponse, error)
+	CreateInstanceWithContext(ctx context.Context, options *vpcv1.CreateInstanceOptions) (*vpcv1.Instance, *core.DetailedResponse, error)
+	ListRegionZonesWithContext(ctx context.Context, options *vpcv1.ListRegionZonesOptions) (*vpcv1.ZoneCollection, *core.DetailedResponse, error)
+	ListImagesWithContext(ctx context.Context, options *vpcv1.ListImagesOptions) (*vpcv1.ImageCollection, *core.DetailedResponse, error)
+	ListKeysWithContext(ctx context.Context, options *vpcv1.ListKeysOptions) (*vpcv1.KeyCollection, *core.DetailedResponse, error)
+	ListFloatingIpsWithContext(ctx context.Context, options *vpcv1.ListFloatingIpsOptions) (*vpcv1.FloatingIPCollection, *core.DetailedResponse, error)
+	CreateFloatingIPWithContext(ctx context.Context, options *vpcv1.CreateFloatingIPOptions) (*vpcv1.FloatingIP, *core.DetailedResponse, error)
+	ListInstanceNetworkInterfaceFloatingIpsWithContext(ctx context.Context, options *vpcv1.ListInstanceNetworkInterfaceFloatingIpsOptions) (*vpcv1.FloatingIPUnpaginatedCollection, *core.DetailedResponse, error)
+	AddInstanceNetworkInterfaceFloatingIPWithContext(ctx context.Context, options *vpcv1.AddInstanceNetworkInterfaceFloatingIPOptions) (*vpcv1.FloatingIP, *core.DetailedResponse, error)
+	GetLoadBalancerWithContext(ctx context.Context, options *vpcv1.GetLoadBalancerOptions) (*vpcv1.LoadBalancer, *core.DetailedResponse, error)
+	ListLoadBalancersWithContext(ctx context.Context, options *vpcv1.ListLoadBalancersOptions) (*vpcv1.LoadBalancerCollection, *core.DetailedResponse, error)
+	ListLoadBalancerPoolsWithContext(ctx context.Context, options *vpcv1.ListLoadBalancerPoolsOptions) (*vpcv1.LoadBalancerPoolCollection, *core.DetailedResponse, error)
+	GetLoadBalancerPoolWithContext(ctx context.Context, options *vpcv1.GetLoadBalancerPoolOptions) (*vpcv1.LoadBalancerPool, *core.DetailedResponse, error)
+	ListLoadBalancerPoolMembersWithContext(ctx context.Context, options *vpcv1.ListLoadBalancerPoolMembersOptions) (*vpcv1.LoadBalancerPoolMemberCollection, *core.DetailedResponse, error)
+}
+
+// ResourceControllerClient is the part of *resourcecontrollerv2.ResourceControllerV2 which is used.
+type ResourceControllerClient interface {
+	GetResourceInstanceWithContext(ctx context.Context, options *resourcecontrollerv2.GetResourceInstanceOptions) (*resourcecontrollerv2.ResourceInstance, *core.DetailedResponse, error)
+	ListResourceInstancesWithContext(ctx context.Context, options *resourcecontrollerv2.ListResourceInstancesOptions) (*resourcecontrollerv2.ResourceInstancesList, *core.DetailedResponse, error)
+}
+
+// ResourceManagerClient is the part of *resourcemanagerv2.ResourceManagerV2 which is used.
+type ResourceManagerClient interface {
+	ListResourceGroupsWithContext(ctx context.Context, options *resourcemanagerv2.ListResourceGroupsOptions) (*resourcemanagerv2.ResourceGroupList, *core.DetailedResponse, error)
+}
+
+// TransitGatewayClient is the part of *transitgatewayapisv1.TransitGatewayApisV1 which is used.
+type TransitGatewayClient interface {
+	GetTransitGatewayWithContext(ctx context.Context, options *transitgatewayapisv1.GetTransitGatewayOptions) (*transitgatewayapisv1.TransitGateway, *core.DetailedResponse, error)
+	ListTransitGatewaysWithContext(ctx context.Context, options *transitgatewayapisv1.ListTransitGatewaysOptions) (*transitgatewayapisv1.TransitGatewayCollection, *core.DetailedResponse, error)
+	ListConnectionsWithContext(ctx context.Context, options *transitgatewayapisv1.ListConnectionsOptions) (*transitgatewayapisv1.TransitConnectionCollection, *core.DetailedResponse, error)
+}
+
+// DnsRecordsClient is the part of *dnsrecordsv1.DnsRecordsV1 which is used.
+type DnsRecordsClient interface {
+	ListAllDnsRecordsWithContext(ctx context.Context, options *dnsrecordsv1.ListAllDnsRecordsOptions) (*dnsrecordsv1.ListDnsrecordsResp, *core.DetailedResponse, error)
+}
+
+// ObjectStorageClient is the part of *s3.S3 which is used.
+type ObjectStorageClient interface {
+	HeadBucketWithContext(ctx aws.Context, input *s3.HeadBucketInput, opts ...request.Option) (*s3.HeadBucketOutput, error)
+	ListObjectsWithContext(ctx aws.Context, input *s3.ListObjectsInput, opts ...request.Option) (*s3.ListObjectsOutput, error)
+}
+
+// PINetworkClient is the part of *instance.IBMPINetworkClient which is used.
+type PINetworkClient interface {
+	Get(id string) (*models.Network, error)
+	GetAll() (*models.Networks, error)
+	GetAllPorts(id string) (*models.NetworkPorts, error)
+	GetAllNetworkInterfaces(id string) (*models.NetworkInterfaces, error)
+	Delete(id string) error
+	DeletePort(id string, networkPortID string) error
+}
+
+// PIKeyClient is the part of *instance.IBMPIKeyClient which is used.
+type PIKeyClient interface {
+	Get(id string) (*models.SSHKey, error)
+	GetAll() (*models.SSHKeys, error)
+}
+
+// PIImageClient is the part of *instance.IBMPIImageClient which is used.
+type PIImageClient interface {
+	GetAll() (*models.Images, error)
+	GetAllStockImages(includeSAP bool, includeVTL bool) (*models.Images, error)
+	Delete(id string) error
+}
+
+// PIDhcpClient is the part of *instance.IBMPIDhcpClient which is used.
+type PIDhcpClient interface {
+	Get(id string) (*models.DHCPServerDetail, error)
+	GetAll() (models.DHCPServers, error)
+	Delete(id string) error
+}
+
+// PIInstanceClient is the part of *instance.IBMPIInstanceClient which is used.
+type PIInstanceClient interface {
+	Get(id string) (*models.PVMInstance, error)
+	GetAll() (*models.PVMInstances, error)
+	Delete(id string) error
+}
+
+// PIVolumeClient is the part of *instance.IBMPIVolumeClient which is used.
+type PIVolumeClient interface {
+	GetAll() (*models.Volumes, error)
+	GetAllInstanceVolumes(id string) (*models.Volumes, error)
+	DeleteVolume(id string) error
+}
+
+// PIJobClient is the part of *instance.IBMPIJobClient which is used.
+type PIJobClient interface {
+	Get(id string) (*models.Job, error)
+	GetAll() (*models.Jobs, error)
+}
+
+// PIDatacentersClient is the part of *instance.IBMPIDatacentersClient which is used.
+type PIDatacentersClient interface {
+	GetAll() (*models.Datacenters, error)
+}
+
+// Make sure the SDK clients keep satisfying the interfaces.
+var (
+	_ VpcClient                = (*vpcv1.VpcV1)(nil)
+	_ ResourceControllerClient = (*resourcecontrollerv2.ResourceControllerV2)(nil)
+	_ ResourceManagerClient    = (*resourcemanagerv2.ResourceManagerV2)(nil)
+	_ TransitGatewayClient     = (*transitgatewayapisv1.TransitGatewayApisV1)(nil)
+	_ DnsRecordsClient         = (*dnsrecordsv1.DnsRecordsV1)(nil)
+	_ ObjectStorageClient      = (*s3.S3)(nil)
+	_ PINetworkClient          = (*instance.IBMPINetworkClient)(nil)
+	_ PIKeyClient              = (*instance.IBMPIKeyClient)(nil)
+	_ PIImageClient            = (*instance.IBMPIImageClient)(nil)
+	_ PIDhcpClient             = (*instance.IBMPIDhcpClient)(nil)
+	_ PIInstanceClient         = (*instance.IBMPIInstanceClient)(nil)
+	_ PIVolumeClient           = (*instance.IBMPIVolumeClient)(nil)
+	_ PIJobClient              = (*instance.IBMPIJobClient)(nil)
+	_ PIDatacentersClient      = (*instance.IBMPIDatacentersClient)(nil)
+)
@@ -41,7 +41,7 @@ type CloudObjectStorage struct {
 
 	awsSession *session.Session
 
-	s3Client *s3.S3
+	s3Client ObjectStorageClient
 
 	serviceEndpoint string
 }
@@ -50,11 +50,20 @@ const (
 	cosObjectName = "Cloud Object Storage"
 )
 
-func NewCloudObjectStorage(services *Services) ([]RunnableObject, []error) {
+func init() {
+	RegisterRunnableObject(RunnableObjectRegistration{
+		Name:  cosObjectName,
+		Key:   "cos",
+		New:   NewCloudObjectStorage,
+		Modes: []RunMode{RunModeCreate},
+		Order: 20,
+	})
+}
+
+func NewCloudObjectStorage(ctx context.Context, services *Services) ([]RunnableObject, []error) {
 	var (
 		cosName        string
-		controllerSvc  *resourcecontrollerv2.ResourceControllerV2
-		ctx            context.Context
+		controllerSvc  ResourceControllerClient
 		cancel         context.CancelFunc
 		foundInstances []string
 		cos            *CloudObjectStorage
@@ -86,15 +95,15 @@ func NewCloudObjectStorage(services *Services) ([]RunnableObject, []error) {
 	}
 	log.Debugf("NewCloudObjectStorage: region = %s", cos.region)
 
-	cos.serviceEndpoint = fmt.Sprintf("s3.%s.cloud-object-storage.appdomain.cloud", cos.region)
+	cos.serviceEndpoint = services.GetMetadata().GetServiceEndpoint("COS", fmt.Sprintf("https://s3.%s.cloud-object-storage.appdomain.cloud", cos.region))
 
 	controllerSvc = services.GetControllerSvc()
 
-	ctx, cancel = services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	if fUseTagSearch {
-		foundInstances, err = listByTag(TagTypeCloudObjectStorage, services)
+		foundInstances, err = listByTag(ctx, TagTypeCloudObjectStorage, services)
 	} else {
 		foundInstances, err = findCos(cosName, controllerSvc, ctx)
 	}
@@ -120,7 +129,7 @@ func NewCloudObjectStorage(services *Services) ([]RunnableObject, []error) {
 			innerCosName string
 		)
 
-		getOptions = controllerSvc.NewGetResourceInstanceOptions(instanceID)
+		getOptions = &resourcecontrollerv2.GetResourceInstanceOptions{ID: &instanceID}
 
 		innerCos, _, err = controllerSvc.GetResourceInstanceWithContext(ctx, getOptions)
 		if err != nil {
@@ -169,40 +178,48 @@ func (cos *CloudObjectStorage) createClients() error {
 		return fmt.Errorf("Error: createClients called on nil CloudObjectStorage")
 	}
 
+	if cos.services.GetFakeCloud() != nil {
+		cos.s3Client = cos.services.GetFakeCloud()
+		return nil
+	}
+
 	options.Config = *aws.NewConfig().
 		WithRegion(cos.region).
 		WithEndpoint(cos.serviceEndpoint).
 		WithCredentials(ibmiam.NewStaticCredentials(
-			aws.NewConfig(),
-			"https://iam.cloud.ibm.com/identity/token",
+			aws.NewConfig().WithHTTPClient(cloudHTTPClient("COS")),
+			cos.services.GetMetadata().GetServiceEndpoint("IAM", defaultIAMEndpoint)+"/identity/token",
 			cos.services.GetApiKey(),
 			*cos.innerCos.GUID,
 		)).
-		WithS3ForcePathStyle(true)
+		WithS3ForcePathStyle(true).
+		// cloudHTTPClient retries, so the COS SDK should not.
+		WithHTTPClient(cloudHTTPClient("COS")).
+		WithMaxRetries(0)
 
 	// https://github.com/IBM/ibm-cos-sdk-go/blob/master/aws/session/session.go#L268
 	cos.awsSession, err = session.NewSessionWithOptions(options)
 	if err != nil {
-		log.Fatalf("Error: NewSessionWithOptions returns %v", err)
+		log.Debugf("Error: NewSessionWithOptions returns %v", err)
 		return err
 	}
 	log.Debugf("createClients: cos.awsSession = %+v", cos.awsSession)
 	if cos.awsSession == nil {
-		log.Fatalf("Error: cos.awsSession is nil")
+		log.Debugf("Error: cos.awsSession is nil")
 		return fmt.Errorf("Error: cos.awsSession is nil")
 	}
 
 	cos.s3Client = s3.New(cos.awsSession)
 	log.Debugf("createClients: cos.s3Client = %+v", cos.s3Client)
 	if cos.s3Client == nil {
-		log.Fatalf("Error: cos.s3Client is nil")
+		log.Debugf("Error: cos.s3Client is nil")
 		return fmt.Errorf("Error: cos.s3Client is nil")
 	}
 
 	return err
 }
 
-func findCos(name string, controllerSvc *resourcecontrollerv2.ResourceControllerV2, ctx context.Context) ([]string, error) {
+func findCos(name string, controllerSvc ResourceControllerClient, ctx context.Context) ([]string, error) {
 	var (
 		// https://github.com/IBM/platform-services-go-sdk/blob/main/resourcecontrollerv2/resource_controller_v2.go#L3086
 		options *resourcecontrollerv2.ListResourceInstancesOptions
@@ -215,7 +232,7 @@ func findCos(name string, controllerSvc *resourcecontrollerv2.ResourceController
 
 	log.Debugf("findCOS: name = %s", name)
 
-	options = controllerSvc.NewListResourceInstancesOptions()
+	options = &resourcecontrollerv2.ListResourceInstancesOptions{}
 	options.Limit = &perPage
 	options.SetType("service_instance")
 	//	options.SetResourcePlanID(cosStandardResourceID)
@@ -343,9 +360,8 @@ func isBucketNotFound(err interface{}) bool {
 	return false
 }
 
-func (cos *CloudObjectStorage) examineCOS() error {
+func (cos *CloudObjectStorage) examineCOS(ctx context.Context, result *ObjectResult) error {
 	var (
-		ctx              context.Context
 		cancel           context.CancelFunc
 		bucket           string
 		headBucketInput  *s3.HeadBucketInput
@@ -354,16 +370,16 @@ func (cos *CloudObjectStorage) examineCOS() error {
 		listObjectsInput  *s3.ListObjectsInput
 		listObjectsOutput *s3.ListObjectsOutput
 		s3Object          *s3.Object
-		expectedObjects   = map[string]bool{
-			"master-0": false,
-			"master-1": false,
-			"master-2": false,
-		}
-		allFound bool
-		err      error
+		expectedObjects   = make(map[string]bool)
+		allFound          bool
+		err               error
 	)
 
-	ctx, cancel = cos.services.GetContextWithTimeout()
+	for _, name := range cos.services.GetMetadata().GetExpectations().ControlPlaneNames() {
+		expectedObjects[name] = false
+	}
+
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	bucket = fmt.Sprintf("%s-bootstrap-ign", cos.services.GetMetadata().GetInfraID())
@@ -394,7 +410,9 @@ func (cos *CloudObjectStorage) examineCOS() error {
 	log.Debugf("examineCOS: listObjectsOutput = %+v", *listObjectsOutput)
 
 	for _, s3Object = range listObjectsOutput.Contents {
-		fmt.Printf("Found %s (size %d) in %s\n", *s3Object.Key, int64(*s3Object.Size), bucket)
+		result.AddOK("cos.object", "Found %s (size %d) in %s", *s3Object.Key, int64(*s3Object.Size), bucket).
+			WithEvidence("bucket", bucket).
+			WithEvidence("key", *s3Object.Key)
 
 		for expectedObjectKey := range expectedObjects {
 			if strings.Contains(*s3Object.Key, expectedObjectKey) {
@@ -437,43 +455,37 @@ func (cos *CloudObjectStorage) ObjectName() (string, error) {
 	return cosObjectName, nil
 }
 
-func (cos *CloudObjectStorage) Run() error {
+func (cos *CloudObjectStorage) Run(ctx context.Context) error {
 	// Nothing to do here.
 	return nil
 }
 
-func (cos *CloudObjectStorage) CiStatus(shouldClean bool) {
+func (cos *CloudObjectStorage) CiStatus(ctx context.Context, shouldClean bool) *ObjectResult {
+	return NewObjectResult(cos, cos.name)
 }
 
-func (cos *CloudObjectStorage) ClusterStatus() {
+func (cos *CloudObjectStorage) ClusterStatus(ctx context.Context) *ObjectResult {
 	var (
-		isOk bool
-		err  error
+		result *ObjectResult
+		err    error
 	)
 
+	result = NewObjectResult(cos, cos.name)
+
 	if cos.innerCos == nil {
-		fmt.Printf("%s: Could not find a COS named %s\n", cosObjectName, cos.name)
-		return
+		result.AddError("cos.exists", "Could not find a COS named %s", cos.name)
+		return result
 	}
 
-	isOk = true
-
 	if *cos.innerCos.State != "active" {
-		isOk = false
-		fmt.Printf("%s %s is NOTOK state is not active (%s)\n", cosObjectName, cos.name, *cos.innerCos.State)
+		result.AddNotOK("cos.state", "state is not active (%s)", *cos.innerCos.State).
+			WithEvidence("state", *cos.innerCos.State)
 	}
 
-	err = cos.examineCOS()
+	err = cos.examineCOS(ctx, result)
 	if err != nil {
-		isOk = false
-		fmt.Printf("%s %s is NOTOK (%v)\n", cosObjectName, cos.name, err)
+		result.AddNotOK("cos.bucket", "%v", err)
 	}
 
-	if isOk {
-		fmt.Printf("%s %s is OK.\n", cosObjectName, cos.name)
-	}
-}
-
-func (cos *CloudObjectStorage) Priority() (int, error) {
-	return 60, nil
+	return result
 }
@@ -0,0 +1,197 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// clusterInfrastructure is the part of infrastructures.config.openshift.io/cluster which
+// is used.
+// https://github.com/openshift/api/blob/master/config/v1/types_infrastructure.go
+type clusterInfrastructure struct {
+	Status struct {
+		InfrastructureName   string                `json:"infrastructureName"`
+		APIServerURL         string                `json:"apiServerURL"`
+		ControlPlaneTopology configv1.TopologyMode `json:"controlPlaneTopology"`
+		PlatformStatus       *struct {
+			Type    configv1.PlatformType         `json:"type"`
+			PowerVS *clusterPowerVSPlatformStatus `json:"powervs,omitempty"`
+		} `json:"platformStatus,omitempty"`
+	} `json:"status"`
+}
+
+type clusterPowerVSPlatformStatus struct {
+	configv1.PowerVSPlatformStatus `json:",inline"`
+
+	// Not in every release of the API.
+	ServiceInstanceID string `json:"serviceInstanceID,omitempty"`
+}
+
+// NewMetadataFromKubeconfig builds the metadata of a running cluster from its
+// infrastructure object, for when there is no metadata.json, for example because someone
+// else installed the cluster.  The kubeconfig is used with oc.
+func NewMetadataFromKubeconfig(ctx context.Context, kubeconfig string) (*Metadata, error) {
+	var (
+		metadata *Metadata
+		err      error
+	)
+
+	metadata, err = readKubeconfigMetadata(ctx, kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return validatedMetadata(metadata)
+}
+
+func readKubeconfigMetadata(ctx context.Context, kubeconfig string) (*Metadata, error) {
+	var (
+		cmdOcGetInfrastructure = []string{
+			"oc", "--request-timeout=5s", "get", "infrastructures.config.openshift.io/cluster", "-o", "json",
+		}
+		jsonInfrastructure map[string]interface{}
+		content            []byte
+		infrastructure     clusterInfrastructure
+		err                error
+	)
+
+	jsonInfrastructure, err = runSplitCommandJson(ctx, kubeconfig, cmdOcGetInfrastructure)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not get the infrastructure of the cluster from %s (%v)", kubeconfig, err)
+	}
+
+	content, err = json.Marshal(jsonInfrastructure)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(content, &infrastructure)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not parse the infrastructure of the cluster (%v)", err)
+	}
+
+	return newMetadataFromInfrastructure(&infrastructure, kubeconfig)
+}
+
+func newMetadataFromInfrastructure(infrastructure *clusterInfrastructure, location string) (*Metadata, error) {
+	var (
+		platformStatus = infrastructure.Status.PlatformStatus
+		powerVS        *clusterPowerVSPlatformStatus
+		clusterName    string
+		baseDomain     string
+		topology       Topology
+		err            error
+	)
+
+	if platformStatus == nil || platformStatus.Type != configv1.PowerVSPlatformType || platformStatus.PowerVS == nil {
+		return nil, fmt.Errorf("Error: The cluster of %s is not a PowerVS cluster", location)
+	}
+	powerVS = platformStatus.PowerVS
+
+	clusterName, baseDomain, err = splitAPIServerURL(infrastructure.Status.APIServerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Whether the cluster is compact or private is not in the infrastructure.
+	if infrastructure.Status.ControlPlaneTopology == configv1.SingleReplicaTopologyMode {
+		topology = TopologySNO
+	}
+
+	log.Debugf("newMetadataFromInfrastructure: infrastructure.Status = %+v", infrastructure.Status)
+	log.Debugf("newMetadataFromInfrastructure: powerVS = %+v", powerVS)
+
+	return &Metadata{
+		ciMode: false,
+		createMetadata: CreateMetadata{
+			ClusterName: clusterName,
+			InfraID:     infrastructure.Status.InfrastructureName,
+			ClusterPlatformMetadata: ClusterPlatformMetadata{
+				PowerVS: &PowerVSMetadata{
+					BaseDomain:           baseDomain,
+					CISInstanceCRN:       powerVS.CISInstanceCRN,
+					DNSInstanceCRN:       powerVS.DNSInstanceCRN,
+					PowerVSResourceGroup: powerVS.ResourceGroup,
+					Region:               powerVS.Region,
+					Zone:                 powerVS.Zone,
+					ServiceInstanceGUID:  powerVS.ServiceInstanceID,
+					ServiceEndpoints:     powerVS.ServiceEndpoints,
+				},
+			},
+		},
+		location: location,
+		topology: topology,
+	}, nil
+}
+
+// splitAPIServerURL returns the cluster name and the base domain of an API server URL,
+// which is https://api.<cluster name>.<base domain>:6443.
+func splitAPIServerURL(apiServerURL string) (string, string, error) {
+	var (
+		parsed      *url.URL
+		clusterName string
+		baseDomain  string
+		found       bool
+		err         error
+	)
+
+	parsed, err = url.Parse(apiServerURL)
+	if err != nil {
+		return "", "", fmt.Errorf("Error: Could not parse the API server URL %s (%v)", apiServerURL, err)
+	}
+
+	clusterName, baseDomain, found = strings.Cut(strings.TrimPrefix(parsed.Hostname(), "api."), ".")
+	if !strings.HasPrefix(parsed.Hostname(), "api.") || !found || baseDomain == "" {
+		return "", "", fmt.Errorf("Error: The API server URL %s is not https://api.<cluster name>.<base domain>:6443", apiServerURL)
+	}
+
+	return clusterName, baseDomain, nil
+}
+
+// loadClusterMetadata is loadMetadata which also takes the -kubeconfig of a running
+// cluster.
+func loadClusterMetadata(ctx context.Context, metadataLocation string, installDir string, kubeconfig string) (*Metadata, error) {
+	var (
+		metadata *Metadata
+		err      error
+	)
+
+	metadata, err = readClusterMetadataLocation(ctx, metadataLocation, installDir, kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return validatedMetadata(metadata)
+}
+
+// readClusterMetadataLocation is loadClusterMetadata without the validation.
+func readClusterMetadataLocation(ctx context.Context, metadataLocation string, installDir string, kubeconfig string) (*Metadata, error) {
+	if kubeconfig == "" {
+		return readMetadataLocation(metadataLocation, installDir)
+	}
+
+	if metadataLocation != "" || installDir != "" {
+		return nil, fmt.Errorf("Error: Use only one of -metadata, -installDir and -kubeconfig")
+	}
+
+	return readKubeconfigMetadata(ctx, kubeconfig)
+}
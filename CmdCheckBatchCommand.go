@@ -0,0 +1,285 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultParallelClusters = 2
+)
+
+// checkBatchCommand runs check-ci or check-create, depending on the kind of metadata,
+// against every cluster of a directory or of a manifest and prints a matrix of the results.
+func checkBatchCommand(ctx context.Context, checkBatchFlags *flag.FlagSet, args []string) error {
+	var (
+		out                 io.Writer
+		ptrApiKey           *string
+		ptrApiKeyFile       *string
+		ptrShouldDebug      *string
+		ptrDirectory        *string
+		ptrManifest         *string
+		ptrServiceEndpoints *string
+		ptrRegionCatalog    *string
+		ptrExpectations     *string
+		ptrOutput           *string
+		ptrParallel         *int
+		ptrWorkers          *int
+		ptrOnly             *string
+		ptrSkip             *string
+		ptrFixtures         *string
+		ptrTrace            *string
+		ptrTraceFile        *string
+		outputFormat        OutputFormat
+		locations           []string
+		clusters            []*BatchResult
+		metadatas           []*Metadata
+		iamEndpoint         string
+		credentials         = make(map[string]*CloudCredentials)
+		loginErrs           = make(map[string]error)
+		semaphore           chan struct{}
+		wg                  sync.WaitGroup
+		report              *BatchReport
+		err                 error
+	)
+
+	ptrApiKey = checkBatchFlags.String("apiKey", "", "Your IBM Cloud API key")
+	ptrApiKeyFile = checkBatchFlags.String("apiKeyFile", "", "A file with your IBM Cloud API key")
+	ptrShouldDebug = checkBatchFlags.String("shouldDebug", "false", "Should output debug output")
+	ptrDirectory = checkBatchFlags.String("directory", "", "A directory of metadata files and installation directories to check")
+	ptrManifest = checkBatchFlags.String("manifest", "", "A file listing the metadata files and installation directories to check, one per line")
+	ptrServiceEndpoints = checkBatchFlags.String("serviceEndpoints", "", "Override service endpoints (comma separated NAME=URL, for example IAM=https://private.iam.cloud.ibm.com)")
+	ptrRegionCatalog = checkBatchFlags.String("regionCatalog", "builtin", "Where the PowerVS regions and zones come from (builtin, api)")
+	ptrExpectations = checkBatchFlags.String("expectations", "", "A YAML profile of what to expect of the cluster, instead of the defaults of its topology")
+	ptrOutput = checkBatchFlags.String("output", "text", "The output format (text, json, yaml, junit)")
+	ptrParallel = checkBatchFlags.Int("parallel", defaultParallelClusters, "The number of clusters to check at the same time")
+	ptrWorkers = checkBatchFlags.Int("workers", defaultWorkers, "The number of objects of a cluster to query at the same time")
+	ptrOnly = checkBatchFlags.String("only", "", "Only check these objects (comma separated)")
+	ptrSkip = checkBatchFlags.String("skip", "", "Do not check these objects (comma separated)")
+	ptrFixtures = checkBatchFlags.String("fixtures", "", "Use the fake cloud loaded from this fixtures file instead of IBM Cloud")
+	ptrTrace = checkBatchFlags.String("trace", "false", "Should print a summary of the IBM Cloud API calls at exit")
+	ptrTraceFile = checkBatchFlags.String("traceFile", "", "Save the IBM Cloud API calls into this OpenTelemetry (OTLP JSON) trace file")
+
+	checkBatchFlags.Parse(args)
+
+	switch strings.ToLower(*ptrShouldDebug) {
+	case "true":
+		shouldDebug = true
+	case "false":
+		shouldDebug = false
+	default:
+		return usageErrorf("Error: shouldDebug is not true/false (%s)\n", *ptrShouldDebug)
+	}
+
+	if shouldDebug {
+		out = os.Stderr
+	} else {
+		out = io.Discard
+	}
+	log = &logrus.Logger{
+		Out:       out,
+		Formatter: new(logrus.TextFormatter),
+		Level:     logrus.DebugLevel,
+	}
+
+	outputFormat, err = ParseOutputFormat(*ptrOutput)
+	if err != nil {
+		return usageError(err)
+	}
+
+	if *ptrWorkers < 1 {
+		return usageErrorf("Error: workers must be at least 1 (%d)", *ptrWorkers)
+	}
+	numWorkers = *ptrWorkers
+
+	if *ptrParallel < 1 {
+		return usageErrorf("Error: parallel must be at least 1 (%d)", *ptrParallel)
+	}
+
+	// Check the lists before any work is done, a mode may still have nothing left.
+	_, err = parseObjectList("only", *ptrOnly)
+	if err != nil {
+		return usageError(err)
+	}
+	_, err = parseObjectList("skip", *ptrSkip)
+	if err != nil {
+		return usageError(err)
+	}
+	if *ptrExpectations != "" {
+		_, err = readExpectationsProfile(*ptrExpectations)
+		if err != nil {
+			return usageError(err)
+		}
+	}
+
+	*ptrApiKey, err = resolveApiKey(*ptrApiKey, *ptrApiKeyFile)
+	if err != nil {
+		return usageError(err)
+	}
+
+	tracer, err = NewTracer("check-batch", *ptrTrace, *ptrTraceFile)
+	if err != nil {
+		return usageError(err)
+	}
+
+	if *ptrApiKey == "" && *ptrFixtures == "" {
+		return usageErrorf("Error: No API key set, use -apiKey, -apiKeyFile or IBMCLOUD_API_KEY")
+	}
+
+	switch {
+	case *ptrDirectory != "" && *ptrManifest != "":
+		return usageErrorf("Error: Use either -directory or -manifest, not both")
+	case *ptrDirectory != "":
+		locations, err = readBatchDirectory(*ptrDirectory)
+	case *ptrManifest != "":
+		locations, err = readBatchManifest(*ptrManifest)
+	default:
+		return usageErrorf("Error: No clusters set, use -directory or -manifest")
+	}
+	if err != nil {
+		return usageError(err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Program version is %v, release = %v\n", version, release)
+
+	err = setupRegionCatalog(ctx, *ptrRegionCatalog, *ptrServiceEndpoints, *ptrFixtures)
+	if err != nil {
+		return usageError(err)
+	}
+
+	// A cluster whose metadata is bad is reported and the others are still checked.
+	clusters = make([]*BatchResult, len(locations))
+	metadatas = make([]*Metadata, len(locations))
+	for i, location := range locations {
+		clusters[i] = NewBatchResult(location)
+
+		metadatas[i], err = NewMetadata(location)
+		if err == nil {
+			err = metadatas[i].SetServiceEndpoints(*ptrServiceEndpoints)
+		}
+		if err == nil && !metadatas[i].ciMode {
+			err = metadatas[i].SetExpectations(*ptrExpectations)
+		}
+		if err != nil {
+			clusters[i].Fail(err)
+			metadatas[i] = nil
+			continue
+		}
+
+		if metadatas[i].ciMode {
+			clusters[i].Mode = RunModeCI
+		} else {
+			clusters[i].Mode = RunModeCreate
+		}
+
+		if *ptrFixtures != "" {
+			continue
+		}
+
+		// The clusters share the API key, so it is logged in once for each IAM server,
+		// which also validates it.  The clusters of a server which refuses it are not
+		// checked, the others still are.
+		iamEndpoint = metadatas[i].GetServiceEndpoint("IAM", defaultIAMEndpoint)
+		if _, ok := loginErrs[iamEndpoint]; !ok {
+			credentials[iamEndpoint], loginErrs[iamEndpoint] = NewCloudCredentials(*ptrApiKey, iamEndpoint)
+		}
+		if loginErrs[iamEndpoint] != nil {
+			clusters[i].Fail(loginErrs[iamEndpoint])
+			metadatas[i] = nil
+		}
+	}
+
+	semaphore = make(chan struct{}, *ptrParallel)
+	for i := range clusters {
+		if metadatas[i] == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-semaphore }()
+
+			fmt.Fprintf(os.Stderr, "Checking %s...\n", clusters[i].Name)
+			checkBatchCluster(ctx, clusters[i], metadatas[i], credentials[metadatas[i].GetServiceEndpoint("IAM", defaultIAMEndpoint)], *ptrFixtures, *ptrOnly, *ptrSkip)
+		}(i)
+	}
+	wg.Wait()
+
+	report = NewBatchReport("check-batch", clusters)
+	report.Cancelled = ctx.Err() != nil
+
+	err = renderBatchReport(os.Stdout, outputFormat, report)
+	if err != nil {
+		return err
+	}
+
+	return report.ExitError()
+}
+
+// checkBatchCluster runs the checks of check-ci or of check-create against one cluster,
+// with the credentials which the clusters share.  Nothing is cleaned up.
+func checkBatchCluster(ctx context.Context, cluster *BatchResult, metadata *Metadata, credentials *CloudCredentials, fixtures string, only string, skip string) {
+	var (
+		robjsFuncs    []NewRunnableObjectsEntry
+		services      *Services
+		results       []*ObjectResult
+		discoveryErrs []error
+		err           error
+	)
+
+	robjsFuncs, err = selectRunnableObjects(cluster.Mode, only, skip)
+	if err != nil {
+		cluster.Fail(err)
+		return
+	}
+
+	if fixtures != "" {
+		services, err = NewFakeServices(ctx, metadata, fixtures)
+	} else {
+		services, err = NewServicesWithCredentials(ctx, metadata, credentials)
+	}
+	if err != nil {
+		cluster.Fail(fmt.Errorf("Error: Could not create a Services object (%s)!", err))
+		return
+	}
+
+	if cluster.Mode == RunModeCI {
+		results, discoveryErrs, err = runCiChecks(ctx, services, robjsFuncs, false)
+	} else {
+		results, discoveryErrs, err = runClusterChecks(ctx, services, robjsFuncs)
+	}
+	if err != nil {
+		cluster.Fail(err)
+		return
+	}
+
+	cluster.SetResults("check-"+string(cluster.Mode), robjsFuncs, results, discoveryErrs)
+}
@@ -18,6 +18,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -27,11 +28,12 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func checkCapiKubeconfigCommand(checkCapiKubeconfigFlags *flag.FlagSet, args []string) error {
+func checkCapiKubeconfigCommand(ctx context.Context, checkCapiKubeconfigFlags *flag.FlagSet, args []string) error {
 	var (
 		out            io.Writer
 		ptrShouldDebug *string
 		ptrKubeconfig  *string
+		ptrInstallDir  *string
 		cmds           = [][]string{
 			{
 				"oc get ibmpowervscluster -n openshift-cluster-api-guests -o json",
@@ -51,6 +53,7 @@ func checkCapiKubeconfigCommand(checkCapiKubeconfigFlags *flag.FlagSet, args []s
 
 	ptrShouldDebug = checkCapiKubeconfigFlags.String("shouldDebug", "false", "Should output debug output")
 	ptrKubeconfig = checkCapiKubeconfigFlags.String("kubeconfig", "", "The KUBECONFIG file")
+	ptrInstallDir = checkCapiKubeconfigFlags.String("installDir", "", "The installation directory of openshift-install, instead of -kubeconfig")
 
 	checkCapiKubeconfigFlags.Parse(args)
 
@@ -60,7 +63,7 @@ func checkCapiKubeconfigCommand(checkCapiKubeconfigFlags *flag.FlagSet, args []s
 	case "false":
 		shouldDebug = false
 	default:
-		return fmt.Errorf("Error: shouldDebug is not true/false (%s)\n", *ptrShouldDebug)
+		return usageErrorf("Error: shouldDebug is not true/false (%s)\n", *ptrShouldDebug)
 	}
 
 	if shouldDebug {
@@ -74,14 +77,19 @@ func checkCapiKubeconfigCommand(checkCapiKubeconfigFlags *flag.FlagSet, args []s
 		Level:     logrus.DebugLevel,
 	}
 
-	if *ptrKubeconfig == "" {
-		return fmt.Errorf("Error: No KUBECONFIG key set, use -kubeconfig")
+	*ptrKubeconfig, err = resolveKubeconfig(*ptrKubeconfig, *ptrInstallDir, true)
+	if err != nil {
+		return usageError(err)
 	}
 
 	fmt.Fprintf(os.Stderr, "Program version is %v, release = %v\n", version, release)
 
 	for _, twoCmds := range cmds {
-		err = runTwoCommands(*ptrKubeconfig, twoCmds[0], twoCmds[1])
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err = runTwoCommands(ctx, *ptrKubeconfig, twoCmds[0], twoCmds[1])
 		if err != nil {
 			fmt.Printf("Error: could not run command: %v\n", err)
 		}
@@ -90,7 +98,7 @@ func checkCapiKubeconfigCommand(checkCapiKubeconfigFlags *flag.FlagSet, args []s
 	if idxCapiOutput := strings.Index(*ptrKubeconfig, ".clusterapi_output"); idxCapiOutput > -1 {
 		firstPart := (*ptrKubeconfig)[0:idxCapiOutput]
 
-		err = runSplitCommand([]string{
+		err = runSplitCommand(ctx, []string{
 			"grep",
 			"--color",
 			"-E",
@@ -18,39 +18,70 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
-func checkCiCommand(checkCiFlags *flag.FlagSet, args []string) error {
+func checkCiCommand(ctx context.Context, checkCiFlags *flag.FlagSet, args []string) error {
 	var (
-		out            io.Writer
-		ptrApiKey      *string
-		ptrShouldDebug *string
-		ptrMetadata    *string
-		ptrShouldClean *string
-		shouldClean    = false
-		metadata       *Metadata
-		services       *Services
-		robjsFuncs     = []NewRunnableObjectsEntry{
-			{NewVpc, "Virtual Private Cloud"},
-			{NewTransitGateway, "Transit Gateway"},
-			{NewServiceInstance, "Power Service Instance"},
-		}
-		robjsCluster   []RunnableObject
-		robjObjectName string
-		err            error
+		out                 io.Writer
+		ptrApiKey           *string
+		ptrApiKeyFile       *string
+		ptrShouldDebug      *string
+		ptrMetadata         *string
+		ptrInstallDir       *string
+		ptrServiceEndpoints *string
+		ptrRegionCatalog    *string
+		ptrShouldClean      *string
+		cleanupMode         CleanupMode
+		ptrPlan             *string
+		ptrCleanupTimeout   *time.Duration
+		plan                *CleanupPlan
+		ptrOutput           *string
+		ptrWorkers          *int
+		ptrOnly             *string
+		ptrSkip             *string
+		ptrFixtures         *string
+		ptrRecord           *string
+		ptrReplay           *string
+		ptrTrace            *string
+		ptrTraceFile        *string
+		outputFormat        OutputFormat
+		metadata            *Metadata
+		services            *Services
+		robjsFuncs          []NewRunnableObjectsEntry
+		results             []*ObjectResult
+		discoveryErrs       []error
+		report              *Report
+		err                 error
 	)
 
 	ptrApiKey = checkCiFlags.String("apiKey", "", "Your IBM Cloud API key")
+	ptrApiKeyFile = checkCiFlags.String("apiKeyFile", "", "A file with your IBM Cloud API key")
 	ptrShouldDebug = checkCiFlags.String("shouldDebug", "false", "Should output debug output")
-	ptrMetadata = checkCiFlags.String("metadata", "", "The location of the metadata.json file")
-	ptrShouldClean = checkCiFlags.String("shouldClean", "false", "Should we attempt to clean up?")
+	ptrMetadata = checkCiFlags.String("metadata", "", "The location of the metadata.json or CI metadata file")
+	ptrInstallDir = checkCiFlags.String("installDir", "", "The installation directory of openshift-install, instead of -metadata")
+	ptrServiceEndpoints = checkCiFlags.String("serviceEndpoints", "", "Override service endpoints (comma separated NAME=URL, for example IAM=https://private.iam.cloud.ibm.com)")
+	ptrRegionCatalog = checkCiFlags.String("regionCatalog", "builtin", "Where the PowerVS regions and zones come from (builtin, api)")
+	ptrShouldClean = checkCiFlags.String("shouldClean", "false", "Should we attempt to clean up? (false, true, plan, apply)")
+	ptrCleanupTimeout = checkCiFlags.Duration("cleanupTimeout", cleanupPolicy.Timeout, "How long the deletion of one resource may take when cleaning up")
+	ptrPlan = checkCiFlags.String("plan", "", "The file -shouldClean plan saves the cleanup plan into and -shouldClean apply reads it from")
+	ptrOutput = checkCiFlags.String("output", "text", "The output format (text, json, yaml, junit)")
+	ptrOnly = checkCiFlags.String("only", "", "Only check these objects (comma separated)")
+	ptrSkip = checkCiFlags.String("skip", "", "Do not check these objects (comma separated)")
+	ptrFixtures = checkCiFlags.String("fixtures", "", "Use the fake cloud loaded from this fixtures file instead of IBM Cloud")
+	ptrRecord = checkCiFlags.String("record", "", "Record the IBM Cloud API traffic into this directory")
+	ptrReplay = checkCiFlags.String("replay", "", "Replay the IBM Cloud API traffic recorded in this directory")
+	ptrTrace = checkCiFlags.String("trace", "false", "Should print a summary of the IBM Cloud API calls at exit")
+	ptrTraceFile = checkCiFlags.String("traceFile", "", "Save the IBM Cloud API calls into this OpenTelemetry (OTLP JSON) trace file")
+	ptrWorkers = checkCiFlags.Int("workers", defaultWorkers, "The number of objects to query at the same time")
 
 	checkCiFlags.Parse(args)
 
@@ -60,7 +91,7 @@ func checkCiCommand(checkCiFlags *flag.FlagSet, args []string) error {
 	case "false":
 		shouldDebug = false
 	default:
-		return fmt.Errorf("Error: shouldDebug is not true/false (%s)\n", *ptrShouldDebug)
+		return usageErrorf("Error: shouldDebug is not true/false (%s)\n", *ptrShouldDebug)
 	}
 
 	if shouldDebug {
@@ -74,59 +105,240 @@ func checkCiCommand(checkCiFlags *flag.FlagSet, args []string) error {
 		Level:     logrus.DebugLevel,
 	}
 
-	if *ptrApiKey == "" {
-		return fmt.Errorf("Error: No API key set, use -apiKey")
+	outputFormat, err = ParseOutputFormat(*ptrOutput)
+	if err != nil {
+		return usageError(err)
 	}
 
-	if *ptrMetadata == "" {
-		return fmt.Errorf("Error: No metadata file location iset, use -metadata")
+	if *ptrWorkers < 1 {
+		return usageErrorf("Error: workers must be at least 1 (%d)", *ptrWorkers)
 	}
+	numWorkers = *ptrWorkers
 
-	switch strings.ToLower(*ptrShouldClean) {
-	case "true":
-		shouldClean = true
-	case "false":
-		shouldClean = false
-	default:
-		return fmt.Errorf("Error: shouldClean is not true/false (%s)\n", *ptrShouldClean)
+	robjsFuncs, err = selectRunnableObjects(RunModeCI, *ptrOnly, *ptrSkip)
+	if err != nil {
+		return usageError(err)
+	}
+
+	*ptrApiKey, err = resolveApiKey(*ptrApiKey, *ptrApiKeyFile)
+	if err != nil {
+		return usageError(err)
+	}
+
+	if *ptrFixtures != "" && (*ptrRecord != "" || *ptrReplay != "") {
+		return usageErrorf("Error: -fixtures cannot be used with -record or -replay")
+	}
+
+	cassette, err = NewCassette(*ptrRecord, *ptrReplay, *ptrApiKey)
+	if err != nil {
+		return usageError(err)
+	}
+	if cassette.IsReplaying() && *ptrApiKey == "" {
+		// The recorded API key was redacted, so any key will do.
+		*ptrApiKey = redactedValue
+	}
+
+	tracer, err = NewTracer("check-ci", *ptrTrace, *ptrTraceFile)
+	if err != nil {
+		return usageError(err)
+	}
+
+	if *ptrApiKey == "" && *ptrFixtures == "" {
+		return usageErrorf("Error: No API key set, use -apiKey, -apiKeyFile or IBMCLOUD_API_KEY")
+	}
+
+	if *ptrMetadata == "" && *ptrInstallDir == "" {
+		return usageErrorf("Error: No metadata file location set, use -metadata or -installDir")
+	}
+
+	cleanupMode, err = ParseCleanupMode(*ptrShouldClean)
+	if err != nil {
+		return usageError(err)
 	}
 
+	switch {
+	case cleanupMode == CleanupModeApply && *ptrPlan == "":
+		return usageErrorf("Error: -shouldClean apply needs the -plan to apply")
+	case *ptrPlan != "" && cleanupMode != CleanupModePlan && cleanupMode != CleanupModeApply:
+		return usageErrorf("Error: -plan can only be used with -shouldClean plan or apply")
+	case cleanupMode == CleanupModePlan && outputFormat == OutputFormatJUnit:
+		return usageErrorf("Error: -shouldClean plan cannot output junit")
+	}
+
+	if *ptrCleanupTimeout <= 0 {
+		return usageErrorf("Error: cleanupTimeout must be positive (%v)", *ptrCleanupTimeout)
+	}
+	cleanupPolicy.Timeout = *ptrCleanupTimeout
+
 	fmt.Fprintf(os.Stderr, "Program version is %v, release = %v\n", version, release)
 
-	// Before we do a lot of work, validate the apikey!
-	_, err = InitBXService(*ptrApiKey)
+	err = setupRegionCatalog(ctx, *ptrRegionCatalog, *ptrServiceEndpoints, *ptrFixtures)
 	if err != nil {
-		return err
+		return usageError(err)
 	}
 
-	metadata, err = NewMetadataFromCIMetadata(*ptrMetadata)
+	metadata, err = loadMetadata(*ptrMetadata, *ptrInstallDir)
 	if err != nil {
-		return fmt.Errorf("Error: Could not read metadata from %s\n", *ptrMetadata)
+		return usageError(err)
 	}
 	log.Debugf("metadata = %+v", metadata)
 
-	services, err = NewServices(metadata, *ptrApiKey)
+	err = metadata.SetServiceEndpoints(*ptrServiceEndpoints)
+	if err != nil {
+		return usageError(err)
+	}
+
+	if cleanupMode == CleanupModeApply {
+		plan, err = readCleanupPlan(*ptrPlan, metadata)
+		if err != nil {
+			return usageError(err)
+		}
+	}
+
+	// Before we do a lot of work, validate the apikey!
+	if *ptrFixtures == "" {
+		_, err = InitBXService(*ptrApiKey, metadata.GetServiceEndpoint("IAM", defaultIAMEndpoint))
+		if err != nil {
+			return usageError(err)
+		}
+	}
+
+	if *ptrFixtures != "" {
+		services, err = NewFakeServices(ctx, metadata, *ptrFixtures)
+	} else {
+		services, err = NewServices(ctx, metadata, *ptrApiKey)
+	}
 	if err != nil {
 		return fmt.Errorf("Error: Could not create a Services object (%s)!\n", err)
 	}
 
-	robjsCluster, err = initializeRunnableObjects(services, robjsFuncs)
+	switch cleanupMode {
+	case CleanupModePlan:
+		return runCiCleanupPlan(ctx, services, robjsFuncs, outputFormat, *ptrPlan)
+	case CleanupModeApply:
+		results, discoveryErrs, err = runCiCleanupApply(ctx, services, robjsFuncs, plan)
+	default:
+		results, discoveryErrs, err = runCiChecks(ctx, services, robjsFuncs, cleanupMode == CleanupModeNow)
+	}
 	if err != nil {
 		return err
 	}
 
-	// Sort the objects by their priority.
-	robjsCluster = BubbleSort(robjsCluster)
-	for _, robj := range robjsCluster {
-		robjObjectName, _ = robj.ObjectName()
-		log.Debugf("Sorted %s %+v", robjObjectName, robj)
+	report = NewReport("check-ci", results, discoveryErrs)
+	report.Cancelled = ctx.Err() != nil
+
+	err = renderReport(os.Stdout, outputFormat, report)
+	if err != nil {
+		return err
 	}
-	fmt.Fprintf(os.Stderr, "Sorted the objects.\n")
+
+	return report.ExitError()
+}
+
+// runCiChecks queries all of the CI objects and returns their status, cleaning them up
+// when shouldClean is true.
+func runCiChecks(ctx context.Context, services *Services, robjsFuncs []NewRunnableObjectsEntry, shouldClean bool) ([]*ObjectResult, []error, error) {
+	var (
+		robjsCluster  []RunnableObject
+		results       []*ObjectResult
+		discoveryErrs []error
+		err           error
+	)
+
+	robjsCluster, discoveryErrs, err = initializeRunnableObjects(ctx, services, robjsFuncs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results = checkCiObjects(ctx, services, robjsCluster, discoveryErrs, shouldClean)
+
+	return results, discoveryErrs, nil
+}
+
+// checkCiObjects queries the status of the CI objects which are already initialized.
+func checkCiObjects(ctx context.Context, services *Services, robjs []RunnableObject, discoveryErrs []error, shouldClean bool) []*ObjectResult {
+	var (
+		results []*ObjectResult
+	)
 
 	// Query the status of the objects.
-	for _, robj := range robjsCluster {
-		robj.CiStatus(shouldClean)
+	results = checkRunnableObjects(ctx, robjs, discoveryErrs, func(ctx context.Context, robj RunnableObject) *ObjectResult {
+		return robj.CiStatus(ctx, shouldClean)
+	})
+
+	hits, misses := services.GetCache().Stats()
+	log.Debugf("checkCiObjects: %d calls were cached, %d were not", hits, misses)
+
+	return results
+}
+
+// runCiCleanupPlan prints what -shouldClean true would delete, without deleting anything,
+// and saves it into planFile for -shouldClean apply.
+func runCiCleanupPlan(ctx context.Context, services *Services, robjsFuncs []NewRunnableObjectsEntry, outputFormat OutputFormat, planFile string) error {
+	var (
+		robjsCluster  []RunnableObject
+		results       []*ObjectResult
+		discoveryErrs []error
+		plan          *CleanupPlan
+		report        *Report
+		err           error
+	)
+
+	robjsCluster, discoveryErrs, err = initializeRunnableObjects(ctx, services, robjsFuncs)
+	if err != nil {
+		return err
+	}
+
+	results = checkCiObjects(ctx, services, robjsCluster, discoveryErrs, false)
+
+	plan, err = planCleanup(ctx, services.GetMetadata(), robjsCluster)
+	if err != nil {
+		return err
+	}
+
+	if planFile != "" {
+		err = saveCleanupPlan(planFile, plan)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "The cleanup plan was saved into %s\n", planFile)
+	}
+
+	err = renderCleanupPlan(os.Stdout, outputFormat, plan)
+	if err != nil {
+		return err
+	}
+
+	report = NewReport("check-ci", results, discoveryErrs)
+	report.Cancelled = ctx.Err() != nil
+
+	return report.ExitError()
+}
+
+// runCiCleanupApply makes the deletions of a saved plan, and nothing else, and then
+// queries the status of the CI objects.
+func runCiCleanupApply(ctx context.Context, services *Services, robjsFuncs []NewRunnableObjectsEntry, plan *CleanupPlan) ([]*ObjectResult, []error, error) {
+	var (
+		robjsCluster  []RunnableObject
+		applied       []*ObjectResult
+		results       []*ObjectResult
+		discoveryErrs []error
+		err           error
+	)
+
+	robjsCluster, discoveryErrs, err = initializeRunnableObjects(ctx, services, robjsFuncs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = checkCleanupPlanObjects(plan, robjsCluster)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return nil
+	applied = applyCleanupPlan(ctx, plan, robjsCluster)
+
+	results = checkCiObjects(ctx, services, robjsCluster, discoveryErrs, false)
+
+	return mergeCleanupResults(applied, results), discoveryErrs, nil
 }
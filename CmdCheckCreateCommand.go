@@ -18,6 +18,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -27,31 +28,57 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func checkCreateCommand(checkCreateFlags *flag.FlagSet, args []string) error {
+func checkCreateCommand(ctx context.Context, checkCreateFlags *flag.FlagSet, args []string) error {
 	var (
-		out            io.Writer
-		ptrApiKey      *string
-		ptrShouldDebug *string
-		ptrMetadata    *string
-		metadata       *Metadata
-		services       *Services
-		robjsFuncs     = []NewRunnableObjectsEntry{
-			{NewVpc, "Virtual Private Cloud"},
-			{NewCloudObjectStorage, "Cloud Object Storage"},
-			{NewTransitGateway, "Transit Gateway"},
-			{NewLoadBalancer, "Load Balancer"},
-			{NewServiceInstance, "Power Service Instance"},
-			{NewVpcInstance, "Cloud VM"},
-			{NewDNS, "Domain Name Service"},
-		}
-		robjsCluster   []RunnableObject
-		robjObjectName string
-		err            error
+		out                 io.Writer
+		ptrApiKey           *string
+		ptrApiKeyFile       *string
+		ptrShouldDebug      *string
+		ptrMetadata         *string
+		ptrInstallDir       *string
+		ptrKubeconfig       *string
+		ptrServiceEndpoints *string
+		ptrRegionCatalog    *string
+		ptrExpectations     *string
+		ptrDiagnosticsDir   *string
+		ptrOutput           *string
+		ptrWorkers          *int
+		ptrOnly             *string
+		ptrSkip             *string
+		ptrFixtures         *string
+		ptrRecord           *string
+		ptrReplay           *string
+		ptrTrace            *string
+		ptrTraceFile        *string
+		robjsFuncs          []NewRunnableObjectsEntry
+		outputFormat        OutputFormat
+		metadata            *Metadata
+		services            *Services
+		results             []*ObjectResult
+		discoveryErrs       []error
+		report              *Report
+		err                 error
 	)
 
 	ptrApiKey = checkCreateFlags.String("apiKey", "", "Your IBM Cloud API key")
+	ptrApiKeyFile = checkCreateFlags.String("apiKeyFile", "", "A file with your IBM Cloud API key")
 	ptrShouldDebug = checkCreateFlags.String("shouldDebug", "false", "Should output debug output")
-	ptrMetadata = checkCreateFlags.String("metadata", "", "The location of the metadata.json file")
+	ptrMetadata = checkCreateFlags.String("metadata", "", "The location of the metadata.json or CI metadata file")
+	ptrInstallDir = checkCreateFlags.String("installDir", "", "The installation directory of openshift-install, instead of -metadata")
+	ptrKubeconfig = checkCreateFlags.String("kubeconfig", "", "The KUBECONFIG file of a running cluster to read the metadata from, instead of -metadata")
+	ptrServiceEndpoints = checkCreateFlags.String("serviceEndpoints", "", "Override service endpoints (comma separated NAME=URL, for example IAM=https://private.iam.cloud.ibm.com)")
+	ptrRegionCatalog = checkCreateFlags.String("regionCatalog", "builtin", "Where the PowerVS regions and zones come from (builtin, api)")
+	ptrExpectations = checkCreateFlags.String("expectations", "", "A YAML profile of what to expect of the cluster, instead of the defaults of its topology")
+	ptrDiagnosticsDir = checkCreateFlags.String("diagnosticsDir", "", "Save only the state and the system reference codes of the instances which are not ACTIVE into this directory, no console or boot log is collected")
+	ptrOutput = checkCreateFlags.String("output", "text", "The output format (text, json, yaml, junit)")
+	ptrOnly = checkCreateFlags.String("only", "", "Only check these objects (comma separated)")
+	ptrSkip = checkCreateFlags.String("skip", "", "Do not check these objects (comma separated)")
+	ptrFixtures = checkCreateFlags.String("fixtures", "", "Use the fake cloud loaded from this fixtures file instead of IBM Cloud")
+	ptrRecord = checkCreateFlags.String("record", "", "Record the IBM Cloud API traffic into this directory")
+	ptrReplay = checkCreateFlags.String("replay", "", "Replay the IBM Cloud API traffic recorded in this directory")
+	ptrTrace = checkCreateFlags.String("trace", "false", "Should print a summary of the IBM Cloud API calls at exit")
+	ptrTraceFile = checkCreateFlags.String("traceFile", "", "Save the IBM Cloud API calls into this OpenTelemetry (OTLP JSON) trace file")
+	ptrWorkers = checkCreateFlags.Int("workers", defaultWorkers, "The number of objects to query at the same time")
 
 	checkCreateFlags.Parse(args)
 
@@ -61,7 +88,7 @@ func checkCreateCommand(checkCreateFlags *flag.FlagSet, args []string) error {
 	case "false":
 		shouldDebug = false
 	default:
-		return fmt.Errorf("Error: shouldDebug is not true/false (%s)\n", *ptrShouldDebug)
+		return usageErrorf("Error: shouldDebug is not true/false (%s)\n", *ptrShouldDebug)
 	}
 
 	if shouldDebug {
@@ -75,51 +102,132 @@ func checkCreateCommand(checkCreateFlags *flag.FlagSet, args []string) error {
 		Level:     logrus.DebugLevel,
 	}
 
-	if *ptrApiKey == "" {
-		return fmt.Errorf("Error: No API key set, use -apiKey")
+	outputFormat, err = ParseOutputFormat(*ptrOutput)
+	if err != nil {
+		return usageError(err)
 	}
 
-	if *ptrMetadata == "" {
-		return fmt.Errorf("Error: No metadata file location iset, use -metadata")
+	if *ptrWorkers < 1 {
+		return usageErrorf("Error: workers must be at least 1 (%d)", *ptrWorkers)
+	}
+	numWorkers = *ptrWorkers
+
+	diagnosticsDir = *ptrDiagnosticsDir
+
+	robjsFuncs, err = selectRunnableObjects(RunModeCreate, *ptrOnly, *ptrSkip)
+	if err != nil {
+		return usageError(err)
+	}
+
+	*ptrApiKey, err = resolveApiKey(*ptrApiKey, *ptrApiKeyFile)
+	if err != nil {
+		return usageError(err)
+	}
+
+	if *ptrFixtures != "" && (*ptrRecord != "" || *ptrReplay != "") {
+		return usageErrorf("Error: -fixtures cannot be used with -record or -replay")
+	}
+
+	cassette, err = NewCassette(*ptrRecord, *ptrReplay, *ptrApiKey)
+	if err != nil {
+		return usageError(err)
+	}
+	if cassette.IsReplaying() && *ptrApiKey == "" {
+		// The recorded API key was redacted, so any key will do.
+		*ptrApiKey = redactedValue
+	}
+
+	tracer, err = NewTracer("check-create", *ptrTrace, *ptrTraceFile)
+	if err != nil {
+		return usageError(err)
+	}
+
+	if *ptrApiKey == "" && *ptrFixtures == "" {
+		return usageErrorf("Error: No API key set, use -apiKey, -apiKeyFile or IBMCLOUD_API_KEY")
+	}
+
+	if *ptrMetadata == "" && *ptrInstallDir == "" && *ptrKubeconfig == "" {
+		return usageErrorf("Error: No metadata file location set, use -metadata, -installDir or -kubeconfig")
 	}
 
 	fmt.Fprintf(os.Stderr, "Program version is %v, release = %v\n", version, release)
 
-	// Before we do a lot of work, validate the apikey!
-	_, err = InitBXService(*ptrApiKey)
+	err = setupRegionCatalog(ctx, *ptrRegionCatalog, *ptrServiceEndpoints, *ptrFixtures)
 	if err != nil {
-		return err
+		return usageError(err)
 	}
 
-	metadata, err = NewMetadataFromCCMetadata(*ptrMetadata)
+	metadata, err = loadClusterMetadata(ctx, *ptrMetadata, *ptrInstallDir, *ptrKubeconfig)
 	if err != nil {
-		return fmt.Errorf("Error: Could not read metadata from %s\n", *ptrMetadata)
+		return usageError(err)
 	}
 	log.Debugf("metadata = %+v", metadata)
 	log.Debugf("metadata.Region = %s", metadata.GetRegion())
 
-	services, err = NewServices(metadata, *ptrApiKey)
+	err = metadata.SetServiceEndpoints(*ptrServiceEndpoints)
+	if err != nil {
+		return usageError(err)
+	}
+
+	err = metadata.SetExpectations(*ptrExpectations)
+	if err != nil {
+		return usageError(err)
+	}
+
+	// Before we do a lot of work, validate the apikey!
+	if *ptrFixtures == "" {
+		_, err = InitBXService(*ptrApiKey, metadata.GetServiceEndpoint("IAM", defaultIAMEndpoint))
+		if err != nil {
+			return usageError(err)
+		}
+	}
+
+	if *ptrFixtures != "" {
+		services, err = NewFakeServices(ctx, metadata, *ptrFixtures)
+	} else {
+		services, err = NewServices(ctx, metadata, *ptrApiKey)
+	}
 	if err != nil {
 		return fmt.Errorf("Error: Could not create a Services object (%s)!\n", err)
 	}
 
-	robjsCluster, err = initializeRunnableObjects(services, robjsFuncs)
+	results, discoveryErrs, err = runClusterChecks(ctx, services, robjsFuncs)
+	if err != nil {
+		return err
+	}
+
+	report = NewReport("check-create", results, discoveryErrs)
+	report.Cancelled = ctx.Err() != nil
+
+	err = renderReport(os.Stdout, outputFormat, report)
 	if err != nil {
 		return err
 	}
 
-	// Sort the objects by their priority.
-	robjsCluster = BubbleSort(robjsCluster)
-	for _, robj := range robjsCluster {
-		robjObjectName, _ = robj.ObjectName()
-		log.Debugf("Sorted %s %+v", robjObjectName, robj)
+	return report.ExitError()
+}
+
+// runClusterChecks queries all of the objects of an installed cluster and returns their status.
+func runClusterChecks(ctx context.Context, services *Services, robjsFuncs []NewRunnableObjectsEntry) ([]*ObjectResult, []error, error) {
+	var (
+		robjsCluster  []RunnableObject
+		results       []*ObjectResult
+		discoveryErrs []error
+		err           error
+	)
+
+	robjsCluster, discoveryErrs, err = initializeRunnableObjects(ctx, services, robjsFuncs)
+	if err != nil {
+		return nil, nil, err
 	}
-	fmt.Fprintf(os.Stderr, "Sorted the objects.\n")
 
 	// Query the status of the objects.
-	for _, robj := range robjsCluster {
-		robj.ClusterStatus()
-	}
+	results = checkRunnableObjects(ctx, robjsCluster, discoveryErrs, func(ctx context.Context, robj RunnableObject) *ObjectResult {
+		return robj.ClusterStatus(ctx)
+	})
+
+	hits, misses := services.GetCache().Stats()
+	log.Debugf("runClusterChecks: %d calls were cached, %d were not", hits, misses)
 
-	return nil
+	return results, discoveryErrs, nil
 }
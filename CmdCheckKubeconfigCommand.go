@@ -18,6 +18,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -27,11 +28,12 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func checkKubeconfigCommand(checkKubeconfigFlags *flag.FlagSet, args []string) error {
+func checkKubeconfigCommand(ctx context.Context, checkKubeconfigFlags *flag.FlagSet, args []string) error {
 	var (
 		out            io.Writer
 		ptrShouldDebug *string
 		ptrKubeconfig  *string
+		ptrInstallDir  *string
 		cmds           = []string{
 			"oc --request-timeout=5s get clusterversion",
 			"oc --request-timeout=5s get co",
@@ -63,6 +65,7 @@ func checkKubeconfigCommand(checkKubeconfigFlags *flag.FlagSet, args []string) e
 
 	ptrShouldDebug = checkKubeconfigFlags.String("shouldDebug", "false", "Should output debug output")
 	ptrKubeconfig = checkKubeconfigFlags.String("kubeconfig", "", "The KUBECONFIG file")
+	ptrInstallDir = checkKubeconfigFlags.String("installDir", "", "The installation directory of openshift-install, instead of -kubeconfig")
 
 	checkKubeconfigFlags.Parse(args)
 
@@ -72,7 +75,7 @@ func checkKubeconfigCommand(checkKubeconfigFlags *flag.FlagSet, args []string) e
 	case "false":
 		shouldDebug = false
 	default:
-		return fmt.Errorf("Error: shouldDebug is not true/false (%s)\n", *ptrShouldDebug)
+		return usageErrorf("Error: shouldDebug is not true/false (%s)\n", *ptrShouldDebug)
 	}
 
 	if shouldDebug {
@@ -86,21 +89,30 @@ func checkKubeconfigCommand(checkKubeconfigFlags *flag.FlagSet, args []string) e
 		Level:     logrus.DebugLevel,
 	}
 
-	if *ptrKubeconfig == "" {
-		return fmt.Errorf("Error: No KUBECONFIG key set, use -kubeconfig")
+	*ptrKubeconfig, err = resolveKubeconfig(*ptrKubeconfig, *ptrInstallDir, false)
+	if err != nil {
+		return usageError(err)
 	}
 
 	fmt.Fprintf(os.Stderr, "Program version is %v, release = %v\n", version, release)
 
 	for _, cmd := range cmds {
-		err = runCommand(*ptrKubeconfig, cmd)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err = runCommand(ctx, *ptrKubeconfig, cmd)
 		if err != nil {
 			fmt.Printf("Error: could not run command: %v\n", err)
 		}
 	}
 
 	for _, twoCmds := range pipeCmds {
-		err = runTwoCommands(*ptrKubeconfig, twoCmds[0], twoCmds[1])
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		err = runTwoCommands(ctx, *ptrKubeconfig, twoCmds[0], twoCmds[1])
 		if err != nil {
 			fmt.Printf("Error: could not run command: %v\n", err)
 		}
@@ -18,6 +18,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -30,39 +31,48 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func createJumpboxCommand(createJumpboxFlags *flag.FlagSet, args []string) error {
+func createJumpboxCommand(ctx context.Context, createJumpboxFlags *flag.FlagSet, args []string) error {
 	var (
-		out            io.Writer
-		ptrApiKey      *string
-		ptrShouldDebug *string
-		ptrMetadata    *string
-		ptrImageName   *string
-		ptrKeyName     *string
-		metadata       *Metadata
-		services       *Services
-		robjsFuncs     = []NewRunnableObjectsEntry{
-			{NewVpc, "Virtual Private Cloud"},
-			{NewServiceInstance, "Power Service Instance"},
-		}
-		robjsCluster    []RunnableObject
-		vpc             *Vpc
-		si              *ServiceInstance
-		name            string
-		resourceGroupID string
-		imageID         string
-		instanceProfile = "bx2d-2x8"
-		zone            string
-		subnetID        string
-		keyID           string
-		vpcID           string
-		err             error
+		out                 io.Writer
+		ptrApiKey           *string
+		ptrApiKeyFile       *string
+		ptrShouldDebug      *string
+		ptrMetadata         *string
+		ptrInstallDir       *string
+		ptrServiceEndpoints *string
+		ptrRegionCatalog    *string
+		ptrImageName        *string
+		ptrKeyName          *string
+		ptrTrace            *string
+		ptrTraceFile        *string
+		metadata            *Metadata
+		services            *Services
+		robjsFuncs          []NewRunnableObjectsEntry
+		robjsCluster        []RunnableObject
+		vpc                 *Vpc
+		si                  *ServiceInstance
+		name                string
+		resourceGroupID     string
+		imageID             string
+		instanceProfile     = "bx2d-2x8"
+		zone                string
+		subnetID            string
+		keyID               string
+		vpcID               string
+		err                 error
 	)
 
 	ptrApiKey = createJumpboxFlags.String("apiKey", "", "Your IBM Cloud API key")
+	ptrApiKeyFile = createJumpboxFlags.String("apiKeyFile", "", "A file with your IBM Cloud API key")
 	ptrShouldDebug = createJumpboxFlags.String("shouldDebug", "false", "Should output debug output")
-	ptrMetadata = createJumpboxFlags.String("metadata", "", "The location of the metadata.json file")
+	ptrMetadata = createJumpboxFlags.String("metadata", "", "The location of the metadata.json or CI metadata file")
+	ptrInstallDir = createJumpboxFlags.String("installDir", "", "The installation directory of openshift-install, instead of -metadata")
+	ptrServiceEndpoints = createJumpboxFlags.String("serviceEndpoints", "", "Override service endpoints (comma separated NAME=URL, for example IAM=https://private.iam.cloud.ibm.com)")
+	ptrRegionCatalog = createJumpboxFlags.String("regionCatalog", "builtin", "Where the PowerVS regions and zones come from (builtin, api)")
 	ptrImageName = createJumpboxFlags.String("imageName", "", "The name of the image to use")
 	ptrKeyName = createJumpboxFlags.String("keyName", "", "The name of the ssh key to use")
+	ptrTrace = createJumpboxFlags.String("trace", "false", "Should print a summary of the IBM Cloud API calls at exit")
+	ptrTraceFile = createJumpboxFlags.String("traceFile", "", "Save the IBM Cloud API calls into this OpenTelemetry (OTLP JSON) trace file")
 
 	createJumpboxFlags.Parse(args)
 
@@ -72,7 +82,7 @@ func createJumpboxCommand(createJumpboxFlags *flag.FlagSet, args []string) error
 	case "false":
 		shouldDebug = false
 	default:
-		return fmt.Errorf("Error: shouldDebug is not true/false (%s)\n", *ptrShouldDebug)
+		return usageErrorf("Error: shouldDebug is not true/false (%s)\n", *ptrShouldDebug)
 	}
 
 	if shouldDebug {
@@ -86,34 +96,59 @@ func createJumpboxCommand(createJumpboxFlags *flag.FlagSet, args []string) error
 		Level:     logrus.DebugLevel,
 	}
 
+	*ptrApiKey, err = resolveApiKey(*ptrApiKey, *ptrApiKeyFile)
+	if err != nil {
+		return usageError(err)
+	}
+
 	if *ptrApiKey == "" {
-		return fmt.Errorf("Error: No API key set, use -apiKey")
+		return usageErrorf("Error: No API key set, use -apiKey, -apiKeyFile or IBMCLOUD_API_KEY")
+	}
+
+	tracer, err = NewTracer("create-jumpbox", *ptrTrace, *ptrTraceFile)
+	if err != nil {
+		return usageError(err)
 	}
 
-	if *ptrMetadata == "" {
-		return fmt.Errorf("Error: No metadata file location iset, use -metadata")
+	if *ptrMetadata == "" && *ptrInstallDir == "" {
+		return usageErrorf("Error: No metadata file location set, use -metadata or -installDir")
 	}
 
 	fmt.Fprintf(os.Stderr, "Program version is %v, release = %v\n", version, release)
 
-	// Before we do a lot of work, validate the apikey!
-	_, err = InitBXService(*ptrApiKey)
+	err = setupRegionCatalog(ctx, *ptrRegionCatalog, *ptrServiceEndpoints, "")
 	if err != nil {
-		return err
+		return usageError(err)
 	}
 
-	metadata, err = NewMetadataFromCCMetadata(*ptrMetadata)
+	metadata, err = loadMetadata(*ptrMetadata, *ptrInstallDir)
 	if err != nil {
-		return fmt.Errorf("Error: Could not read metadata from %s\n", *ptrMetadata)
+		return usageError(err)
 	}
 	log.Debugf("metadata = %+v", metadata)
 
-	services, err = NewServices(metadata, *ptrApiKey)
+	err = metadata.SetServiceEndpoints(*ptrServiceEndpoints)
+	if err != nil {
+		return usageError(err)
+	}
+
+	// Before we do a lot of work, validate the apikey!
+	_, err = InitBXService(*ptrApiKey, metadata.GetServiceEndpoint("IAM", defaultIAMEndpoint))
+	if err != nil {
+		return usageError(err)
+	}
+
+	services, err = NewServices(ctx, metadata, *ptrApiKey)
 	if err != nil {
 		return fmt.Errorf("Error: Could not create a Services object (%s)!\n", err)
 	}
 
-	robjsCluster, err = initializeRunnableObjects(services, robjsFuncs)
+	robjsFuncs, err = selectRunnableObjects(RunModeJumpbox, "", "")
+	if err != nil {
+		return err
+	}
+
+	robjsCluster, _, err = initializeRunnableObjects(ctx, services, robjsFuncs)
 	if err != nil {
 		return err
 	}
@@ -141,7 +176,7 @@ func createJumpboxCommand(createJumpboxFlags *flag.FlagSet, args []string) error
 	}
 
 	if *ptrImageName == "" {
-		images, err := vpc.ListImages()
+		images, err := vpc.ListImages(ctx)
 		if err != nil {
 			return err
 		}
@@ -152,7 +187,7 @@ func createJumpboxCommand(createJumpboxFlags *flag.FlagSet, args []string) error
 		}
 		os.Exit(0)
 	} else {
-		images, err := vpc.ListImages()
+		images, err := vpc.ListImages(ctx)
 		if err != nil {
 			return err
 		}
@@ -176,14 +211,14 @@ func createJumpboxCommand(createJumpboxFlags *flag.FlagSet, args []string) error
 		return err
 	}
 
-	zones, err := vpc.GetRegionZones()
+	zones, err := vpc.GetRegionZones(ctx)
 	if err != nil {
 		return err
 	}
 	log.Debugf("zones = %+v", zones)
 	zone = zones[0]
 
-	subnets, err := vpc.ListSubnets()
+	subnets, err := vpc.ListSubnets(ctx)
 	if err != nil {
 		return err
 	}
@@ -194,7 +229,7 @@ func createJumpboxCommand(createJumpboxFlags *flag.FlagSet, args []string) error
 	}
 
 	if *ptrKeyName == "" {
-		keys, err := vpc.ListSshKeys()
+		keys, err := vpc.ListSshKeys(ctx)
 		if err != nil {
 			return err
 		}
@@ -205,7 +240,7 @@ func createJumpboxCommand(createJumpboxFlags *flag.FlagSet, args []string) error
 		}
 		os.Exit(0)
 	} else {
-		keys, err := vpc.ListSshKeys()
+		keys, err := vpc.ListSshKeys(ctx)
 		if err != nil {
 			return err
 		}
@@ -235,7 +270,7 @@ func createJumpboxCommand(createJumpboxFlags *flag.FlagSet, args []string) error
 	log.Debugf("keyID           = %s", keyID)
 	log.Debugf("vpcID           = %s", vpcID)
 
-	instance, err := vpc.FindInstance(name)
+	instance, err := vpc.FindInstance(ctx, name)
 	if err != nil {
 		return err
 	}
@@ -274,7 +309,7 @@ func createJumpboxCommand(createJumpboxFlags *flag.FlagSet, args []string) error
 			},
 		}
 
-		instance, err = vpc.CreateInstance(instancePrototype)
+		instance, err = vpc.CreateInstance(ctx, instancePrototype)
 		log.Debugf("instance = %+v", instance)
 		log.Debugf("err = %+v", err)
 		if err != nil {
@@ -284,7 +319,7 @@ func createJumpboxCommand(createJumpboxFlags *flag.FlagSet, args []string) error
 		fmt.Printf("Found instance %s\n", name)
 	}
 
-	err = vpc.CreateFIP(instance)
+	err = vpc.CreateFIP(ctx, instance)
 	if err != nil {
 		return err
 	}
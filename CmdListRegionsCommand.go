@@ -0,0 +1,130 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+)
+
+// listRegionsCommand prints the PowerVS regions and zones, which the metadata is
+// validated against, with the VPC and COS regions which go with them.
+func listRegionsCommand(ctx context.Context, listRegionsFlags *flag.FlagSet, args []string) error {
+	var (
+		out                 io.Writer
+		ptrShouldDebug      *string
+		ptrRegionCatalog    *string
+		ptrServiceEndpoints *string
+		ptrFixtures         *string
+		ptrOutput           *string
+		outputFormat        OutputFormat
+		listings            []RegionListing
+		err                 error
+	)
+
+	ptrShouldDebug = listRegionsFlags.String("shouldDebug", "false", "Should output debug output")
+	ptrRegionCatalog = listRegionsFlags.String("regionCatalog", "api", "Where the PowerVS regions and zones come from (builtin, api)")
+	ptrServiceEndpoints = listRegionsFlags.String("serviceEndpoints", "", "Override service endpoints (comma separated NAME=URL, for example Power=https://private.us-south.power-iaas.cloud.ibm.com)")
+	ptrFixtures = listRegionsFlags.String("fixtures", "", "Use the fake cloud loaded from this fixtures file instead of IBM Cloud")
+	ptrOutput = listRegionsFlags.String("output", "text", "The output format (text, json, yaml)")
+
+	listRegionsFlags.Parse(args)
+
+	switch strings.ToLower(*ptrShouldDebug) {
+	case "true":
+		shouldDebug = true
+	case "false":
+		shouldDebug = false
+	default:
+		return usageErrorf("Error: shouldDebug is not true/false (%s)\n", *ptrShouldDebug)
+	}
+
+	if shouldDebug {
+		out = os.Stderr
+	} else {
+		out = io.Discard
+	}
+	log = &logrus.Logger{
+		Out:       out,
+		Formatter: new(logrus.TextFormatter),
+		Level:     logrus.DebugLevel,
+	}
+
+	outputFormat, err = ParseOutputFormat(*ptrOutput)
+	if err != nil {
+		return usageError(err)
+	}
+	if outputFormat == OutputFormatJUnit {
+		return usageErrorf("Error: output is not text/json/yaml (%s)", *ptrOutput)
+	}
+
+	err = setupRegionCatalog(ctx, *ptrRegionCatalog, *ptrServiceEndpoints, *ptrFixtures)
+	if err != nil {
+		return usageError(err)
+	}
+
+	fmt.Fprintf(os.Stderr, "The regions are from %s\n", regionCatalog.Source)
+
+	listings = regionCatalog.Listings()
+
+	switch outputFormat {
+	case OutputFormatJSON:
+		return renderJSON(os.Stdout, listings)
+	case OutputFormatYAML:
+		return renderYAML(os.Stdout, listings)
+	}
+
+	renderRegionsText(os.Stdout, listings)
+
+	return nil
+}
+
+func renderRegionsText(w io.Writer, listings []RegionListing) {
+	var (
+		tw *tabwriter.Writer
+	)
+
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "REGION\tDESCRIPTION\tZONE\tVPC REGION\tCOS REGION\tSYSTYPES\tCAPABILITIES\tSTATUS\n")
+	for _, listing := range listings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			listing.Region,
+			listing.Description,
+			listing.Zone,
+			orDash(listing.VPCRegion),
+			orDash(listing.COSRegion),
+			orDash(strings.Join(listing.SysTypes, ",")),
+			orDash(strings.Join(listing.Capabilities, ",")),
+			orDash(listing.Status))
+	}
+
+	tw.Flush()
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
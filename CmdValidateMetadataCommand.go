@@ -0,0 +1,133 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// validateMetadataCommand reports all of the problems with the metadata at once, without
+// needing an API key.
+func validateMetadataCommand(ctx context.Context, validateMetadataFlags *flag.FlagSet, args []string) error {
+	var (
+		out                 io.Writer
+		ptrShouldDebug      *string
+		ptrMetadata         *string
+		ptrInstallDir       *string
+		ptrKubeconfig       *string
+		ptrServiceEndpoints *string
+		ptrRegionCatalog    *string
+		ptrExpectations     *string
+		metadata            *Metadata
+		metadataErr         *MetadataError
+		vpcRegion           string
+		err                 error
+	)
+
+	ptrShouldDebug = validateMetadataFlags.String("shouldDebug", "false", "Should output debug output")
+	ptrMetadata = validateMetadataFlags.String("metadata", "", "The location of the metadata.json or CI metadata file")
+	ptrInstallDir = validateMetadataFlags.String("installDir", "", "The installation directory of openshift-install, instead of -metadata")
+	ptrKubeconfig = validateMetadataFlags.String("kubeconfig", "", "The KUBECONFIG file of a running cluster to read the metadata from, instead of -metadata")
+	ptrServiceEndpoints = validateMetadataFlags.String("serviceEndpoints", "", "Override service endpoints (comma separated NAME=URL, for example IAM=https://private.iam.cloud.ibm.com)")
+	ptrRegionCatalog = validateMetadataFlags.String("regionCatalog", "builtin", "Where the PowerVS regions and zones come from (builtin, api)")
+	ptrExpectations = validateMetadataFlags.String("expectations", "", "A YAML profile of what to expect of the cluster, instead of the defaults of its topology")
+
+	validateMetadataFlags.Parse(args)
+
+	switch strings.ToLower(*ptrShouldDebug) {
+	case "true":
+		shouldDebug = true
+	case "false":
+		shouldDebug = false
+	default:
+		return usageErrorf("Error: shouldDebug is not true/false (%s)\n", *ptrShouldDebug)
+	}
+
+	if shouldDebug {
+		out = os.Stderr
+	} else {
+		out = io.Discard
+	}
+	log = &logrus.Logger{
+		Out:       out,
+		Formatter: new(logrus.TextFormatter),
+		Level:     logrus.DebugLevel,
+	}
+
+	err = setupRegionCatalog(ctx, *ptrRegionCatalog, *ptrServiceEndpoints, "")
+	if err != nil {
+		return usageError(err)
+	}
+
+	metadata, err = readClusterMetadataLocation(ctx, *ptrMetadata, *ptrInstallDir, *ptrKubeconfig)
+	if err != nil {
+		return usageError(err)
+	}
+	log.Debugf("metadata = %+v", metadata)
+
+	err = metadata.SetServiceEndpoints(*ptrServiceEndpoints)
+	if err != nil {
+		return usageError(err)
+	}
+
+	if !metadata.ciMode {
+		err = metadata.SetExpectations(*ptrExpectations)
+		if err != nil {
+			return usageError(err)
+		}
+	}
+
+	err = metadata.Validate()
+	if errors.As(err, &metadataErr) {
+		fmt.Printf("The metadata %s has %d problem(s):\n", metadataErr.Location, len(metadataErr.Problems))
+		for _, problem := range metadataErr.Problems {
+			fmt.Printf("    %s\n", problem)
+		}
+		return &ExitError{Code: exitCodeUsage}
+	}
+	if err != nil {
+		return usageError(err)
+	}
+
+	// Validate made sure that the region is known.
+	vpcRegion, _ = metadata.GetVPCRegion()
+
+	fmt.Printf("The metadata %s is valid:\n", metadata.location)
+	if metadata.ciMode {
+		fmt.Printf("    mode:            CI\n")
+	} else {
+		fmt.Printf("    mode:            cluster\n")
+		fmt.Printf("    cluster name:    %s\n", metadata.GetClusterName())
+		fmt.Printf("    infrastructure:  %s\n", metadata.GetInfraID())
+		fmt.Printf("    topology:        %s\n", metadata.GetExpectations().Topology)
+	}
+	fmt.Printf("    region:          %s\n", metadata.GetRegion())
+	fmt.Printf("    zone:            %s\n", metadata.GetZone())
+	fmt.Printf("    VPC region:      %s\n", vpcRegion)
+	fmt.Printf("    resource group:  %s\n", metadata.GetResourceGroup())
+	for _, serviceEndpoint := range metadata.createMetadata.PowerVS.ServiceEndpoints {
+		fmt.Printf("    endpoint:        %s=%s\n", serviceEndpoint.Name, serviceEndpoint.URL)
+	}
+
+	return nil
+}
@@ -19,6 +19,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -40,7 +41,7 @@ const (
 )
 
 var (
-	aOpenshiftPhases = []func(installDir string, apiKey string) error {
+	aOpenshiftPhases = []func(ctx context.Context, installDir string, apiKey string) error {
 		updateOpenshiftPhase1,
 		updateOpenshiftPhase2,
 		updateOpenshiftPhase3,
@@ -52,18 +53,44 @@ var (
 	}
 )
 
-func watchCreateCommand(watchCreateClusterFlags *flag.FlagSet, args []string) error {
+func watchCreateCommand(ctx context.Context, watchCreateClusterFlags *flag.FlagSet, args []string) error {
 	var (
-		out            io.Writer
-		ptrApiKey      *string
-		ptrShouldDebug *string
-		ptrInstallDir  *string
-		err            error
+		out                 io.Writer
+		ptrApiKey           *string
+		ptrApiKeyFile       *string
+		ptrShouldDebug      *string
+		ptrInstallDir       *string
+		ptrOutput           *string
+		ptrWorkers          *int
+		ptrOnly             *string
+		ptrSkip             *string
+		ptrTrace            *string
+		ptrTraceFile        *string
+		ptrExpectations     *string
+		ptrServiceEndpoints *string
+		robjsFuncs          []NewRunnableObjectsEntry
+		outputFormat        OutputFormat
+		metadata            *Metadata
+		expectations        *Expectations
+		services            *Services
+		results             []*ObjectResult
+		discoveryErrs       []error
+		report              *Report
+		err                 error
 	)
 
 	ptrApiKey = watchCreateClusterFlags.String("apiKey", "", "Your IBM Cloud API key")
+	ptrApiKeyFile = watchCreateClusterFlags.String("apiKeyFile", "", "A file with your IBM Cloud API key")
 	ptrShouldDebug = watchCreateClusterFlags.String("shouldDebug", "false", "Should output debug output")
-	ptrInstallDir = watchCreateClusterFlags.String("installDir", "", "The KUBECONFIG file")
+	ptrInstallDir = watchCreateClusterFlags.String("installDir", "", "The installation directory of openshift-install")
+	ptrOutput = watchCreateClusterFlags.String("output", "", "Check the cluster when done and output the results (text, json, yaml, junit)")
+	ptrOnly = watchCreateClusterFlags.String("only", "", "Only check these objects (comma separated)")
+	ptrSkip = watchCreateClusterFlags.String("skip", "", "Do not check these objects (comma separated)")
+	ptrTrace = watchCreateClusterFlags.String("trace", "false", "Should print a summary of the IBM Cloud API calls at exit")
+	ptrTraceFile = watchCreateClusterFlags.String("traceFile", "", "Save the IBM Cloud API calls into this OpenTelemetry (OTLP JSON) trace file")
+	ptrWorkers = watchCreateClusterFlags.Int("workers", defaultWorkers, "The number of objects to query at the same time")
+	ptrExpectations = watchCreateClusterFlags.String("expectations", "", "A YAML profile of what to expect of the cluster, instead of the defaults of its topology")
+	ptrServiceEndpoints = watchCreateClusterFlags.String("serviceEndpoints", "", "Override service endpoints (comma separated NAME=URL, for example IAM=https://private.iam.cloud.ibm.com)")
 
 	watchCreateClusterFlags.Parse(args)
 
@@ -73,7 +100,7 @@ func watchCreateCommand(watchCreateClusterFlags *flag.FlagSet, args []string) er
 	case "false":
 		shouldDebug = false
 	default:
-		return fmt.Errorf("Error: shouldDebug is not true/false (%s)\n", *ptrShouldDebug)
+		return usageErrorf("Error: shouldDebug is not true/false (%s)\n", *ptrShouldDebug)
 	}
 
 	if shouldDebug {
@@ -87,50 +114,138 @@ func watchCreateCommand(watchCreateClusterFlags *flag.FlagSet, args []string) er
 		Level:     logrus.DebugLevel,
 	}
 
+	if *ptrOutput != "" {
+		outputFormat, err = ParseOutputFormat(*ptrOutput)
+		if err != nil {
+			return usageError(err)
+		}
+	}
+
+	// Keep stdout for the report, so that it can be parsed.
+	if outputFormat != "" && outputFormat != OutputFormatText {
+		progressOut = os.Stderr
+	}
+
+	if *ptrWorkers < 1 {
+		return usageErrorf("Error: workers must be at least 1 (%d)", *ptrWorkers)
+	}
+	numWorkers = *ptrWorkers
+
+	robjsFuncs, err = selectRunnableObjects(RunModeCreate, *ptrOnly, *ptrSkip)
+	if err != nil {
+		return usageError(err)
+	}
+
+	*ptrApiKey, err = resolveApiKey(*ptrApiKey, *ptrApiKeyFile)
+	if err != nil {
+		return usageError(err)
+	}
+
 	if *ptrApiKey == "" {
-		return fmt.Errorf("Error: No API key set, use -apiKey")
+		return usageErrorf("Error: No API key set, use -apiKey, -apiKeyFile or IBMCLOUD_API_KEY")
 	}
 
-	// Before we do a lot of work, validate the apikey!
-	_, err = InitBXService(*ptrApiKey)
+	tracer, err = NewTracer("watch-create", *ptrTrace, *ptrTraceFile)
 	if err != nil {
-		return err
+		return usageError(err)
 	}
 
 	if *ptrInstallDir == "" {
-		return fmt.Errorf("Error: No installation directory set, use -installDir")
+		return usageErrorf("Error: No installation directory set, use -installDir")
+	}
+
+	watchServiceEndpoints = *ptrServiceEndpoints
+
+	metadata, err = watchCreateMetadata(*ptrInstallDir)
+	if err != nil {
+		return usageError(err)
+	}
+
+	// Before we do a lot of work, validate the apikey!
+	_, err = InitBXService(*ptrApiKey, metadata.GetServiceEndpoint("IAM", defaultIAMEndpoint))
+	if err != nil {
+		return usageError(err)
 	}
 
 	fmt.Fprintf(os.Stderr, "Program version is %v, release = %v\n", version, release)
 
-	kubeconfigCapi := filepath.Join(*ptrInstallDir, ".clusterapi_output/envtest.kubeconfig")
+	expectations, err = installDirExpectations(*ptrInstallDir, *ptrExpectations)
+	if err != nil {
+		return usageError(err)
+	}
+	log.Debugf("expectations = %+v", expectations)
+
+	kubeconfigCapi := installDirKubeconfig(*ptrInstallDir, true)
 
 	if _, err = os.Stat(kubeconfigCapi); errors.Is(err, os.ErrNotExist) && !useSavedJson {
 		return err
 	}
 
-	err = watchCAPIPhases(kubeconfigCapi)
+	err = watchCAPIPhases(ctx, kubeconfigCapi, expectations)
 	if err != nil {
 		return err
 	}
 
-	err = watchOpenshiftPhases(*ptrInstallDir, *ptrApiKey)
+	err = watchOpenshiftPhases(ctx, *ptrInstallDir, *ptrApiKey)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if outputFormat == "" {
+		return nil
+	}
+
+	// Read it again, the cluster has been created since.
+	metadata, err = watchCreateMetadata(*ptrInstallDir)
+	if err != nil {
+		return err
+	}
+
+	err = metadata.SetExpectations(*ptrExpectations)
+	if err != nil {
+		return err
+	}
+
+	services, err = NewServices(ctx, metadata, *ptrApiKey)
+	if err != nil {
+		return fmt.Errorf("Error: Could not create a Services object (%s)!\n", err)
+	}
+
+	results, discoveryErrs, err = runClusterChecks(ctx, services, robjsFuncs)
+	if err != nil {
+		return err
+	}
+
+	report = NewReport("watch-create", results, discoveryErrs)
+	report.Cancelled = ctx.Err() != nil
+
+	err = renderReport(os.Stdout, outputFormat, report)
+	if err != nil {
+		return err
+	}
+
+	return report.ExitError()
 }
 
 func updateWindow(capiWindows map[string]*tview.TextView, element string, text string) {
 	if useTview {
 		capiWindows[element].SetText(text)
 	} else {
-		fmt.Println(text)
+		fmt.Fprintln(progressOut, text)
 	}
 }
 
-func updateCAPIPhase1(kubeconfig string, app *tview.Application, capiWindows map[string]*tview.TextView, chanResult chan<- error) {
+// sleepContext waits for the duration unless ctx is cancelled first.
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(duration):
+		return nil
+	}
+}
+
+func updateCAPIPhase1(ctx context.Context, kubeconfig string, expectations *Expectations, app *tview.Application, capiWindows map[string]*tview.TextView, chanResult chan<- error) {
 	var (
 		cmdOcGetPVSCluster = []string{
 			"oc", "get", "ibmpowervscluster", "-n", "openshift-cluster-api-guests", "-o", "json",
@@ -147,7 +262,7 @@ func updateCAPIPhase1(kubeconfig string, app *tview.Application, capiWindows map
 		if useSavedJson {
 			jsonPVSCluster, err = parseJsonFile("ibmpowervscluster2.json")
 		} else {
-			jsonPVSCluster, err = runSplitCommandJson(kubeconfig, cmdOcGetPVSCluster)
+			jsonPVSCluster, err = runSplitCommandJson(ctx, kubeconfig, cmdOcGetPVSCluster)
 		}
 		if err != nil {
 			err = fmt.Errorf("Error: could not run command: %v", err)
@@ -168,7 +283,7 @@ func updateCAPIPhase1(kubeconfig string, app *tview.Application, capiWindows map
 		}
 
 		if !useTview {
-			fmt.Println("Querying the IBMPowerVSCluster: 8<--------8<--------")
+			fmt.Fprintln(progressOut, "Querying the IBMPowerVSCluster: 8<--------8<--------")
 		}
 
 		conditionsReady = true
@@ -192,12 +307,12 @@ func updateCAPIPhase1(kubeconfig string, app *tview.Application, capiWindows map
 			log.Debugf("updateCAPIPhase1: conditionsReady = %v, len(aconditions) = %d", conditionsReady, len(aconditions))
 			log.Debugf("updateCAPIPhase1: clusterReady = %v", clusterReady)
 			if clusterReady {
-				fmt.Println("Cluster is READY")
+				fmt.Fprintln(progressOut, "Cluster is READY")
 			} else {
-				fmt.Println("Cluster is NOT READY")
+				fmt.Fprintln(progressOut, "Cluster is NOT READY")
 			}
 
-			if len(aconditions) == 8 && clusterReady {
+			if len(aconditions) == expectations.CAPIClusterConditions && clusterReady {
 				err = nil
 				break
 			}
@@ -205,7 +320,10 @@ func updateCAPIPhase1(kubeconfig string, app *tview.Application, capiWindows map
 			log.Debugf("updateCAPIPhase1: conditionsReady = %v", conditionsReady)
 		}
 
-		time.Sleep(10 * time.Second)
+		err = sleepContext(ctx, 10*time.Second)
+		if err != nil {
+			break
+		}
 	}
 
 	if useTview {
@@ -217,7 +335,7 @@ func updateCAPIPhase1(kubeconfig string, app *tview.Application, capiWindows map
 	log.Debugf("updateCAPIPhase1: DONE!")
 }
 
-func updateCAPIPhase2(kubeconfig string, app *tview.Application, capiWindows map[string]*tview.TextView, chanResult chan<- error) {
+func updateCAPIPhase2(ctx context.Context, kubeconfig string, expectations *Expectations, app *tview.Application, capiWindows map[string]*tview.TextView, chanResult chan<- error) {
 	var (
 		cmdOcGetPVSImage = []string{
 			"oc", "get", "ibmpowervsimage", "-n", "openshift-cluster-api-guests", "-o", "json",
@@ -232,7 +350,7 @@ func updateCAPIPhase2(kubeconfig string, app *tview.Application, capiWindows map
 		if useSavedJson {
 			jsonPVSImage, err = parseJsonFile("ibmpowervsimage1.json")
 		} else {
-			jsonPVSImage, err = runSplitCommandJson(kubeconfig, cmdOcGetPVSImage)
+			jsonPVSImage, err = runSplitCommandJson(ctx, kubeconfig, cmdOcGetPVSImage)
 		}
 		if err != nil {
 			err = fmt.Errorf("Error: could not run command: %v", err)
@@ -253,7 +371,7 @@ func updateCAPIPhase2(kubeconfig string, app *tview.Application, capiWindows map
 		}
 
 		if !useTview {
-			fmt.Println("Querying the IBMPowerVSImage: 8<--------8<--------")
+			fmt.Fprintln(progressOut, "Querying the IBMPowerVSImage: 8<--------8<--------")
 		}
 
 		conditionsReady = true
@@ -269,7 +387,7 @@ func updateCAPIPhase2(kubeconfig string, app *tview.Application, capiWindows map
 
 		if conditionsReady {
 			log.Debugf("updateCAPIPhase2: conditionsReady = %v, len(aconditions) = %d", conditionsReady, len(aconditions))
-			if len(aconditions) == 2 {
+			if len(aconditions) == expectations.CAPIImageConditions {
 				err = nil
 				break
 			}
@@ -277,7 +395,10 @@ func updateCAPIPhase2(kubeconfig string, app *tview.Application, capiWindows map
 			log.Debugf("updateCAPIPhase2: conditionsReady = %v", conditionsReady)
 		}
 
-		time.Sleep(10 * time.Second)
+		err = sleepContext(ctx, 10*time.Second)
+		if err != nil {
+			break
+		}
 	}
 
 	if useTview {
@@ -289,7 +410,7 @@ func updateCAPIPhase2(kubeconfig string, app *tview.Application, capiWindows map
 	log.Debugf("updateCAPIPhase2: DONE!")
 }
 
-func updateCAPIPhase3(kubeconfig string, app *tview.Application, capiWindows map[string]*tview.TextView, chanResult chan<- error) {
+func updateCAPIPhase3(ctx context.Context, kubeconfig string, expectations *Expectations, app *tview.Application, capiWindows map[string]*tview.TextView, chanResult chan<- error) {
 	var (
 		cmdOcGetPVSMachines = []string{
 			"oc", "get", "ibmpowervsmachines", "-n", "openshift-cluster-api-guests", "-o", "json",
@@ -304,7 +425,7 @@ func updateCAPIPhase3(kubeconfig string, app *tview.Application, capiWindows map
 		if useSavedJson {
 			jsonPVSMachines, err = parseJsonFile("ibmpowervsmachines3.json")
 		} else {
-			jsonPVSMachines, err = runSplitCommandJson(kubeconfig, cmdOcGetPVSMachines)
+			jsonPVSMachines, err = runSplitCommandJson(ctx, kubeconfig, cmdOcGetPVSMachines)
 		}
 		if err != nil {
 			err = fmt.Errorf("Error: could not run command: %v", err)
@@ -325,7 +446,7 @@ func updateCAPIPhase3(kubeconfig string, app *tview.Application, capiWindows map
 		}
 
 		if !useTview {
-			fmt.Println("Querying the IBMPowerVSMachines: 8<--------8<--------")
+			fmt.Fprintln(progressOut, "Querying the IBMPowerVSMachines: 8<--------8<--------")
 		}
 
 		conditionsReady = true
@@ -351,12 +472,12 @@ func updateCAPIPhase3(kubeconfig string, app *tview.Application, capiWindows map
 				fmt.Fprintf(&buf, ", address is %s", condition.Address)
 			}
 
-			fmt.Println(buf.String())
+			fmt.Fprintln(progressOut, buf.String())
 		}
 
 		if conditionsReady {
 			log.Debugf("updateCAPIPhase3: conditionsReady = %v, len(aconditions) = %d", conditionsReady, len(aconditions))
-			if len(aconditions) == 4 {
+			if len(aconditions) == expectations.CAPIMachines {
 				err = nil
 				break
 			}
@@ -364,7 +485,10 @@ func updateCAPIPhase3(kubeconfig string, app *tview.Application, capiWindows map
 			log.Debugf("updateCAPIPhase3: conditionsReady = %v", conditionsReady)
 		}
 
-		time.Sleep(10 * time.Second)
+		err = sleepContext(ctx, 10*time.Second)
+		if err != nil {
+			break
+		}
 	}
 
 	if useTview {
@@ -376,7 +500,7 @@ func updateCAPIPhase3(kubeconfig string, app *tview.Application, capiWindows map
 	log.Debugf("updateCAPIPhase3: DONE!")
 }
 
-func watchCAPIPhases(kubeconfig string) error {
+func watchCAPIPhases(ctx context.Context, kubeconfig string, expectations *Expectations) error {
 	var (
 		app         *tview.Application
 		grid        *tview.Grid
@@ -416,7 +540,7 @@ func watchCAPIPhases(kubeconfig string) error {
 	chanResult = make(chan error)
 
 	if useTview {
-		go updateCAPIPhase1(kubeconfig, app, capiWindows, chanResult)
+		go updateCAPIPhase1(ctx, kubeconfig, expectations, app, capiWindows, chanResult)
 
 //		time.Sleep(15*time.Second)
 
@@ -424,14 +548,26 @@ func watchCAPIPhases(kubeconfig string) error {
 			return err
 		}
 	} else {
-		go updateCAPIPhase1(kubeconfig, app, capiWindows, chanResult)
-		log.Debugf("watchCAPIPhase: updateCAPIPhase1: chan = %+v", <-chanResult)
+		go updateCAPIPhase1(ctx, kubeconfig, expectations, app, capiWindows, chanResult)
+		err = <-chanResult
+		log.Debugf("watchCAPIPhase: updateCAPIPhase1: chan = %+v", err)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 
-		go updateCAPIPhase2(kubeconfig, app, capiWindows, chanResult)
-		log.Debugf("watchCAPIPhase: updateCAPIPhase2: chan = %+v", <-chanResult)
+		go updateCAPIPhase2(ctx, kubeconfig, expectations, app, capiWindows, chanResult)
+		err = <-chanResult
+		log.Debugf("watchCAPIPhase: updateCAPIPhase2: chan = %+v", err)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 
-		go updateCAPIPhase3(kubeconfig, app, capiWindows, chanResult)
-		log.Debugf("watchCAPIPhase: updateCAPIPhase3: chan = %+v", <-chanResult)
+		go updateCAPIPhase3(ctx, kubeconfig, expectations, app, capiWindows, chanResult)
+		err = <-chanResult
+		log.Debugf("watchCAPIPhase: updateCAPIPhase3: chan = %+v", err)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 	}
 
 	return nil
@@ -440,28 +576,28 @@ func watchCAPIPhases(kubeconfig string) error {
 func printStatus(status string, label string, printSpace bool) {
 	switch status {
 	case "True":
-		fmt.Printf("%s", label)
+		fmt.Fprintf(progressOut, "%s", label)
 	case "False":
-		fmt.Printf("NOT %s", label)
+		fmt.Fprintf(progressOut, "NOT %s", label)
 	case "":
-		fmt.Printf("(EMPTY) %s", label)
+		fmt.Fprintf(progressOut, "(EMPTY) %s", label)
 	default:
-		fmt.Printf("(ERROR %s) %s", status, label)
+		fmt.Fprintf(progressOut, "(ERROR %s) %s", status, label)
 	}
 	if printSpace {
-		fmt.Printf(", ")
+		fmt.Fprintf(progressOut, ", ")
 	}
 }
 
-func watchOpenshiftPhases(installDir string, apiKey string) error {
+func watchOpenshiftPhases(ctx context.Context, installDir string, apiKey string) error {
 	var (
 		metadataLocation    string
 		kubeconfigOpenshift string
 		err                 error
 	)
 
-	metadataLocation = filepath.Join(installDir, "metadata.json")
-	kubeconfigOpenshift = filepath.Join(installDir, "auth/kubeconfig")
+	metadataLocation = filepath.Join(installDir, installMetadataFile)
+	kubeconfigOpenshift = installDirKubeconfig(installDir, false)
 
 	if _, err = os.Stat(metadataLocation); errors.Is(err, os.ErrNotExist) {
 		return err
@@ -471,7 +607,7 @@ func watchOpenshiftPhases(installDir string, apiKey string) error {
 	}
 
 	for _, phase := range aOpenshiftPhases {
-		err = phase(installDir, apiKey)
+		err = phase(ctx, installDir, apiKey)
 		if err != nil {
 			return err
 		}
@@ -480,7 +616,28 @@ func watchOpenshiftPhases(installDir string, apiKey string) error {
 	return nil
 }
 
-func updateOpenshiftPhase1(installDir string, apiKey string) error {
+// watchCreateMetadata reads the metadata of the installation directory and applies the
+// -serviceEndpoints to it.
+func watchCreateMetadata(installDir string) (*Metadata, error) {
+	var (
+		metadata *Metadata
+		err      error
+	)
+
+	metadata, err = NewMetadataFromInstallDir(installDir)
+	if err != nil {
+		return nil, err
+	}
+
+	err = metadata.SetServiceEndpoints(watchServiceEndpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+func updateOpenshiftPhase1(ctx context.Context, installDir string, apiKey string) error {
 	var (
 		metadata       *Metadata
 		services       *Services
@@ -491,19 +648,17 @@ func updateOpenshiftPhase1(installDir string, apiKey string) error {
 		err            error
 	)
 
-	metadataLocation := filepath.Join(installDir, "metadata.json")
-
-	metadata, err = NewMetadataFromCCMetadata(metadataLocation)
+	metadata, err = watchCreateMetadata(installDir)
 	if err != nil {
 		return err
 	}
 
-	services, err = NewServices(metadata, apiKey)
+	services, err = NewServices(ctx, metadata, apiKey)
 	if err != nil {
 		return fmt.Errorf("Error: Could not create a Services object (%s)!\n", err)
 	}
 
-	aloadBalancers, errs = NewLoadBalancerAlt(services)
+	aloadBalancers, errs = NewLoadBalancerAlt(ctx, services)
 	log.Debugf("aloadBalancers = %+v", aloadBalancers)
 	log.Debugf("errs = %+v", errs)
 
@@ -529,34 +684,41 @@ func updateOpenshiftPhase1(installDir string, apiKey string) error {
 	for true {
 		allReady := true
 
-		fmt.Println("Querying the Load Balancer: 8<--------8<--------")
+		fmt.Fprintln(progressOut, "Querying the Load Balancer: 8<--------8<--------")
 
 		if intLb != nil {
-			if !intLb.CheckLoadBalancerPool([]string{"machine-config-server", "additional-pool-22623"}, "machine config server") {
+			result := NewObjectResult(intLb, intLb.name)
+			if !intLb.CheckLoadBalancerPool(ctx, result, []string{"machine-config-server", "additional-pool-22623"}, "machine config server") {
 				allReady = false
 			}
-			if !intLb.CheckLoadBalancerPool([]string{"pool-6443", "pool 6443"}, "kubernetes port 6443") {
+			if !intLb.CheckLoadBalancerPool(ctx, result, []string{"pool-6443", "pool 6443"}, "kubernetes port 6443") {
 				allReady = false
 			}
+			renderChecksText(progressOut, result)
 		}
 
 		if extLb != nil {
-			if !extLb.CheckLoadBalancerPool([]string{"pool-6443", "pool 6443"}, "kubernetes port 6443") {
+			result := NewObjectResult(extLb, extLb.name)
+			if !extLb.CheckLoadBalancerPool(ctx, result, []string{"pool-6443", "pool 6443"}, "kubernetes port 6443") {
 				allReady = false
 			}
+			renderChecksText(progressOut, result)
 		}
 
 		if allReady {
 			break
 		}
 
-		time.Sleep(10 * time.Second)
+		err = sleepContext(ctx, 10*time.Second)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func updateOpenshiftPhase2(installDir string, apiKey string) error {
+func updateOpenshiftPhase2(ctx context.Context, installDir string, apiKey string) error {
 	var (
 		cmdOcGetSecrets = []string{
 			"oc", "--request-timeout=5s", "get", "secrets", "-A", "-o", "json",
@@ -576,21 +738,24 @@ func updateOpenshiftPhase2(installDir string, apiKey string) error {
 		err             error
 	)
 
-	kubeconfigOpenshift := filepath.Join(installDir, "auth/kubeconfig")
+	kubeconfigOpenshift := installDirKubeconfig(installDir, false)
 
 	for true {
-		fmt.Println("Querying the Secrets: 8<--------8<--------")
+		fmt.Fprintln(progressOut, "Querying the Secrets: 8<--------8<--------")
 
 		if useSavedJson {
 			jsonSecrets, err = parseJsonFile("ocgetsecrets1.json")
 		} else {
-			jsonSecrets, err = runSplitCommandJson(kubeconfigOpenshift, cmdOcGetSecrets)
+			jsonSecrets, err = runSplitCommandJson(ctx, kubeconfigOpenshift, cmdOcGetSecrets)
 		}
 		if err != nil {
 			if exitError, ok := err.(*exec.ExitError); ok {
 				exitCode := exitError.ExitCode()
-				fmt.Printf("[ %s ] returned %d\n", strings.Join(cmdOcGetSecrets, " "), exitCode)
-				time.Sleep(10 * time.Second)
+				fmt.Fprintf(progressOut, "[ %s ] returned %d\n", strings.Join(cmdOcGetSecrets, " "), exitCode)
+				err = sleepContext(ctx, 10*time.Second)
+				if err != nil {
+					return err
+				}
 				continue
 			} else {
 				return fmt.Errorf("Error: could not run command: %v", err)
@@ -628,19 +793,22 @@ func updateOpenshiftPhase2(installDir string, apiKey string) error {
 		}
 
 		if allFound {
-			fmt.Println("Found every secret")
+			fmt.Fprintln(progressOut, "Found every secret")
 			break
 		} else {
-			fmt.Printf("Found only (%d/%d) secrets\n", numFound, len(asecretsWanted))
+			fmt.Fprintf(progressOut, "Found only (%d/%d) secrets\n", numFound, len(asecretsWanted))
 		}
 
-		time.Sleep(10 * time.Second)
+		err = sleepContext(ctx, 10*time.Second)
+		if err != nil {
+			return err
+		}
 	}
 
 	return err
 }
 
-func updateOpenshiftPhase3(installDir string, apiKey string) error {
+func updateOpenshiftPhase3(ctx context.Context, installDir string, apiKey string) error {
 	var (
 		cmdOcGetPods = []string{
 			"oc", "--request-timeout=5s", "get", "pods", "-n", "openshift-machine-config-operator", "-o", "json",
@@ -648,6 +816,7 @@ func updateOpenshiftPhase3(installDir string, apiKey string) error {
 	)
 
 	return updateOpenshiftGetPods(
+		ctx,
 		installDir,
 		cmdOcGetPods,
 		"ocgetpodsmco1.json",
@@ -655,7 +824,7 @@ func updateOpenshiftPhase3(installDir string, apiKey string) error {
 	)
 }
 
-func updateOpenshiftPhase4(installDir string, apiKey string) error {
+func updateOpenshiftPhase4(ctx context.Context, installDir string, apiKey string) error {
 	var (
 		cmdOcGetDeployment = []string{
 			"oc", "--request-timeout=5s", "get", "deployment/powervs-cloud-controller-manager", "-n", "openshift-cloud-controller-manager", "-o", "json",
@@ -665,21 +834,24 @@ func updateOpenshiftPhase4(installDir string, apiKey string) error {
 		err                error
 	)
 
-	kubeconfigOpenshift := filepath.Join(installDir, "auth/kubeconfig")
+	kubeconfigOpenshift := installDirKubeconfig(installDir, false)
 
 	for true {
-		fmt.Println("Querying the deployment of powervs-cloud-controller-manager: 8<--------8<--------")
+		fmt.Fprintln(progressOut, "Querying the deployment of powervs-cloud-controller-manager: 8<--------8<--------")
 
 		if useSavedJson {
 			jsonOGD, err = parseJsonFile("ocgetdeploymentpccm1.json")
 		} else {
-			jsonOGD, err = runSplitCommandJson(kubeconfigOpenshift, cmdOcGetDeployment)
+			jsonOGD, err = runSplitCommandJson(ctx, kubeconfigOpenshift, cmdOcGetDeployment)
 		}
 		if err != nil {
 			if exitError, ok := err.(*exec.ExitError); ok {
 				exitCode := exitError.ExitCode()
-				fmt.Printf("[ %s ] returned %d\n", strings.Join(cmdOcGetDeployment, " "), exitCode)
-				time.Sleep(10 * time.Second)
+				fmt.Fprintf(progressOut, "[ %s ] returned %d\n", strings.Join(cmdOcGetDeployment, " "), exitCode)
+				err = sleepContext(ctx, 10*time.Second)
+				if err != nil {
+					return err
+				}
 				continue
 			} else {
 				return fmt.Errorf("Error: could not run command: %v", err)
@@ -699,29 +871,32 @@ func updateOpenshiftPhase4(installDir string, apiKey string) error {
 			log.Debugf("updateOpenshiftPhase4: cc = %+v", cc)
 		}
 
-		fmt.Printf("The deployment of powervs-cloud-controller-manager is: ")
+		fmt.Fprintf(progressOut, "The deployment of powervs-cloud-controller-manager is: ")
 		printStatus(cc.Available, "AVAILABLE", false)
-		fmt.Printf("\n")
+		fmt.Fprintf(progressOut, "\n")
 
 		if cc.Available == "True" {
 			break
 		}
 
-		time.Sleep(10 * time.Second)
+		err = sleepContext(ctx, 10*time.Second)
+		if err != nil {
+			return err
+		}
 	}
 
 	return err
 }
 
-func updateOpenshiftPhase5(installDir string, apiKey string) error {
-	return updateOpenshiftPhaseClusterOperator(installDir, apiKey, "cloud-controller-manager")
+func updateOpenshiftPhase5(ctx context.Context, installDir string, apiKey string) error {
+	return updateOpenshiftPhaseClusterOperator(ctx, installDir, apiKey, "cloud-controller-manager")
 }
 
-func updateOpenshiftPhase6(installDir string, apiKey string) error {
-	return updateOpenshiftPhaseClusterOperator(installDir, apiKey, "network")
+func updateOpenshiftPhase6(ctx context.Context, installDir string, apiKey string) error {
+	return updateOpenshiftPhaseClusterOperator(ctx, installDir, apiKey, "network")
 }
 
-func updateOpenshiftPhase7(installDir string, apiKey string) error {
+func updateOpenshiftPhase7(ctx context.Context, installDir string, apiKey string) error {
 	var (
 		cmdOcGetPods = []string{
 			"oc", "--request-timeout=5s", "get", "pods", "-n", "openshift-machine-api", "-o", "json",
@@ -729,6 +904,7 @@ func updateOpenshiftPhase7(installDir string, apiKey string) error {
 	)
 
 	return updateOpenshiftGetPods(
+		ctx,
 		installDir,
 		cmdOcGetPods,
 		"ocgetpodsmachineapi1.json",
@@ -736,11 +912,11 @@ func updateOpenshiftPhase7(installDir string, apiKey string) error {
 	)
 }
 
-func updateOpenshiftPhase99(installDir string, apiKey string) error {
-	return updateOpenshiftPhaseClusterOperator(installDir, apiKey, "authentication")
+func updateOpenshiftPhase99(ctx context.Context, installDir string, apiKey string) error {
+	return updateOpenshiftPhaseClusterOperator(ctx, installDir, apiKey, "authentication")
 }
 
-func updateOpenshiftPhaseClusterOperator(installDir string, apiKey string, operator string) error {
+func updateOpenshiftPhaseClusterOperator(ctx context.Context, installDir string, apiKey string, operator string) error {
 	var (
 		cmdOcGetCo = []string{
 			"oc", "--request-timeout=5s", "get", "co", "-o", "json",
@@ -750,21 +926,24 @@ func updateOpenshiftPhaseClusterOperator(installDir string, apiKey string, opera
 		err        error
 	)
 
-	kubeconfigOpenshift := filepath.Join(installDir, "auth/kubeconfig")
+	kubeconfigOpenshift := installDirKubeconfig(installDir, false)
 
 	for true {
-		fmt.Printf("Querying the status of the cluster operator %s: 8<--------8<--------\n", operator)
+		fmt.Fprintf(progressOut, "Querying the status of the cluster operator %s: 8<--------8<--------\n", operator)
 
 		if useSavedJson {
 			jsonCo, err = parseJsonFile("ocgetco1.json")
 		} else {
-			jsonCo, err = runSplitCommandJson(kubeconfigOpenshift, cmdOcGetCo)
+			jsonCo, err = runSplitCommandJson(ctx, kubeconfigOpenshift, cmdOcGetCo)
 		}
 		if err != nil {
 			if exitError, ok := err.(*exec.ExitError); ok {
 				exitCode := exitError.ExitCode()
-				fmt.Printf("[ %s ] returned %d\n", strings.Join(cmdOcGetCo, " "), exitCode)
-				time.Sleep(10 * time.Second)
+				fmt.Fprintf(progressOut, "[ %s ] returned %d\n", strings.Join(cmdOcGetCo, " "), exitCode)
+				err = sleepContext(ctx, 10*time.Second)
+				if err != nil {
+					return err
+				}
 				continue
 			} else {
 				return fmt.Errorf("Error: could not run command: %v", err)
@@ -783,33 +962,36 @@ func updateOpenshiftPhaseClusterOperator(installDir string, apiKey string, opera
 			log.Debugf("updateOpenshiftPhaseClusterOperator: cc = %+v", cc)
 		}
 
-		fmt.Printf("The %s cluster operator is: ", operator)
+		fmt.Fprintf(progressOut, "The %s cluster operator is: ", operator)
 		printStatus(cc.Available, "AVAILABLE", true)
 		printStatus(cc.Degraded, "DEGRADED", true)
 		printStatus(cc.Progressing, "PROGRESSING", true)
 		printStatus(cc.Upgradeable, "UPGRADEABLE", false)
-		fmt.Printf("\n")
+		fmt.Fprintf(progressOut, "\n")
 		if cc.Available == "False" && cc.AvailableMessage != "" {
 			message := cc.AvailableMessage
 			if len(message) > 80 {
 				message = message[0:79] + "..."
 			}
 
-			fmt.Printf("    %s\n", message)
-			fmt.Println()
+			fmt.Fprintf(progressOut, "    %s\n", message)
+			fmt.Fprintln(progressOut)
 		}
 
 		if cc.Available == "True" {
 			break
 		}
 
-		time.Sleep(10 * time.Second)
+		err = sleepContext(ctx, 10*time.Second)
+		if err != nil {
+			return err
+		}
 	}
 
 	return err
 }
 
-func updateOpenshiftGetPods(installDir string, cmd []string, savedJsonFile string, namespace string) error {
+func updateOpenshiftGetPods(ctx context.Context, installDir string, cmd []string, savedJsonFile string, namespace string) error {
 	var (
 		jsonOGP    map[string]interface{}
 		apods      []podInfo
@@ -817,21 +999,24 @@ func updateOpenshiftGetPods(installDir string, cmd []string, savedJsonFile strin
 		err        error
 	)
 
-	kubeconfigOpenshift := filepath.Join(installDir, "auth/kubeconfig")
+	kubeconfigOpenshift := installDirKubeconfig(installDir, false)
 
 	for true {
-		fmt.Printf("Querying the pods of %s: 8<--------8<--------\n", namespace)
+		fmt.Fprintf(progressOut, "Querying the pods of %s: 8<--------8<--------\n", namespace)
 
 		if useSavedJson {
 			jsonOGP, err = parseJsonFile(savedJsonFile)
 		} else {
-			jsonOGP, err = runSplitCommandJson(kubeconfigOpenshift, cmd)
+			jsonOGP, err = runSplitCommandJson(ctx, kubeconfigOpenshift, cmd)
 		}
 		if err != nil {
 			if exitError, ok := err.(*exec.ExitError); ok {
 				exitCode := exitError.ExitCode()
-				fmt.Printf("[ %s ] returned %d\n", strings.Join(cmd, " "), exitCode)
-				time.Sleep(10 * time.Second)
+				fmt.Fprintf(progressOut, "[ %s ] returned %d\n", strings.Join(cmd, " "), exitCode)
+				err = sleepContext(ctx, 10*time.Second)
+				if err != nil {
+					return err
+				}
 				continue
 			} else {
 				return fmt.Errorf("Error: could not run command: %v", err)
@@ -851,11 +1036,11 @@ func updateOpenshiftGetPods(installDir string, cmd []string, savedJsonFile strin
 			log.Debugf("updateOpenshiftGetPods: apods = %+v", apods)
 		}
 
-		fmt.Printf("The pods of %s are:\n", namespace)
+		fmt.Fprintf(progressOut, "The pods of %s are:\n", namespace)
 
 		allRunning = true
 		for _, pod := range apods {
-			fmt.Printf("Pod: %s -n %s (%s)\n", pod.Name, pod.Namespace, pod.Phase)
+			fmt.Fprintf(progressOut, "Pod: %s -n %s (%s)\n", pod.Name, pod.Namespace, pod.Phase)
 
 			if pod.Phase != "Running" {
 				allRunning = false
@@ -863,21 +1048,24 @@ func updateOpenshiftGetPods(installDir string, cmd []string, savedJsonFile strin
 
 			for _, container := range pod.Containers {
 				if container.HasRestartCount {
-					fmt.Printf("     Container: %s %s (%v)\n", container.Name, container.State, container.RestartCount)
+					fmt.Fprintf(progressOut, "     Container: %s %s (%v)\n", container.Name, container.State, container.RestartCount)
 				} else {
-					fmt.Printf("     Container: %s %s\n", container.Name, container.State)
+					fmt.Fprintf(progressOut, "     Container: %s %s\n", container.Name, container.State)
 				}
 			}
 		}
 		if len(apods) == 0 {
-			fmt.Println("There are no pods found yet")
+			fmt.Fprintln(progressOut, "There are no pods found yet")
 		}
 
 		if allRunning && len(apods) > 0 {
 			break
 		}
 
-		time.Sleep(10 * time.Second)
+		err = sleepContext(ctx, 10*time.Second)
+		if err != nil {
+			return err
+		}
 	}
 
 	return err
@@ -0,0 +1,222 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+
+	bxsession "github.com/IBM-Cloud/bluemix-go/session"
+)
+
+var (
+	// The environment variables which the ibmcloud CLI and the terraform provider read the
+	// API key from, in the order they are checked.
+	apiKeyEnvironmentVariables = []string{
+		"IBMCLOUD_API_KEY",
+		"IC_API_KEY",
+	}
+
+	// Where the credentials file of the ibmcloud CLI is looked for, relative to
+	// IBMCLOUD_HOME or else to the home directory, like the CLI does.
+	cliApiKeyFile = filepath.Join(".bluemix", "apikey.json")
+)
+
+// resolveApiKey returns the API key from the first of these which is set:
+//   - the -apiKey flag
+//   - the IBMCLOUD_API_KEY or IC_API_KEY environment variables
+//   - the credentials file of the ibmcloud CLI, ~/.bluemix/apikey.json
+//   - the file named by the -apiKeyFile flag
+//
+// Since -apiKeyFile names a file explicitly, it is an error to give it together with
+// the -apiKey flag or one of the environment variables, instead of ignoring it, and the
+// credentials file of the CLI is not looked for then.
+//
+// The login of the ibmcloud CLI is not used: its ~/.bluemix/config.json only has the
+// IAM tokens of the login, which expire, and not the API key.
+//
+// An empty key, without an error, is returned when none of them are set.
+func resolveApiKey(apiKey string, apiKeyFile string) (string, error) {
+	var (
+		value    string
+		fileName string
+		err      error
+	)
+
+	if apiKeyFile != "" {
+		if apiKey != "" {
+			return "", fmt.Errorf("Error: Both -apiKey and -apiKeyFile are set, use only one of them")
+		}
+		for _, name := range apiKeyEnvironmentVariables {
+			if strings.TrimSpace(os.Getenv(name)) != "" {
+				return "", fmt.Errorf("Error: Both -apiKeyFile and the %s environment variable are set, use only one of them", name)
+			}
+		}
+
+		log.Debugf("resolveApiKey: using the -apiKeyFile flag (%s)", apiKeyFile)
+		return readApiKeyFile(apiKeyFile)
+	}
+
+	if apiKey != "" {
+		log.Debugf("resolveApiKey: using the -apiKey flag")
+		return apiKey, nil
+	}
+
+	for _, name := range apiKeyEnvironmentVariables {
+		value = strings.TrimSpace(os.Getenv(name))
+		if value != "" {
+			log.Debugf("resolveApiKey: using the %s environment variable", name)
+			return value, nil
+		}
+	}
+
+	fileName, err = cliApiKeyFileName()
+	if err != nil {
+		log.Debugf("resolveApiKey: no credentials file of the ibmcloud CLI (%v)", err)
+		return "", nil
+	}
+
+	value, err = readApiKeyFile(fileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Debugf("resolveApiKey: no credentials file of the ibmcloud CLI (%s)", fileName)
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	log.Debugf("resolveApiKey: using the credentials file of the ibmcloud CLI (%s)", fileName)
+
+	return value, nil
+}
+
+// cliApiKeyFileName returns the name of the credentials file of the ibmcloud CLI.
+func cliApiKeyFileName() (string, error) {
+	var (
+		homeDir string
+		err     error
+	)
+
+	homeDir = os.Getenv("IBMCLOUD_HOME")
+	if homeDir == "" {
+		homeDir, err = os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return filepath.Join(homeDir, cliApiKeyFile), nil
+}
+
+// readApiKeyFile reads an API key from a file.  The file is either the JSON which
+// "ibmcloud iam api-key-create NAME --file FILE" saves, or just the key.
+func readApiKeyFile(fileName string) (string, error) {
+	var (
+		content []byte
+		keyFile struct {
+			ApiKey string `json:"apikey"`
+		}
+		value string
+		err   error
+	)
+
+	content, err = os.ReadFile(fileName)
+	if err != nil {
+		return "", fmt.Errorf("Error: Could not read the API key file %s (%w)", fileName, err)
+	}
+
+	value = strings.TrimSpace(string(content))
+	if strings.HasPrefix(value, "{") {
+		err = json.Unmarshal(content, &keyFile)
+		if err != nil {
+			return "", fmt.Errorf("Error: Could not parse the API key file %s (%v)", fileName, err)
+		}
+		value = strings.TrimSpace(keyFile.ApiKey)
+	}
+
+	if value == "" {
+		return "", fmt.Errorf("Error: The API key file %s has no API key", fileName)
+	}
+
+	return value, nil
+}
+
+// newAuthenticator returns the IAM authenticator which all of the IBM Cloud clients
+// share, so that they share the IAM token as well.  iamEndpoint is the IAM server.
+func newAuthenticator(apiKey string, iamEndpoint string) (core.Authenticator, error) {
+	var (
+		authenticator core.Authenticator
+		err           error
+	)
+
+	authenticator = &core.IamAuthenticator{
+		ApiKey: apiKey,
+		URL:    iamEndpoint,
+		Client: cloudHTTPClient("IAM"),
+	}
+
+	err = authenticator.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("Error: The API key is not valid (%v)", err)
+	}
+
+	return authenticator, nil
+}
+
+// CloudCredentials is the IAM login of an API key, which the services of several
+// clusters can share, see NewServicesWithCredentials.
+type CloudCredentials struct {
+	apiKey        string
+	bxSession     *bxsession.Session
+	user          *User
+	authenticator core.Authenticator
+}
+
+// NewCloudCredentials logs the API key in to the IAM server iamEndpoint, and creates the
+// IAM authenticator of the IBM Cloud clients.
+func NewCloudCredentials(apiKey string, iamEndpoint string) (*CloudCredentials, error) {
+	var (
+		credentials *CloudCredentials
+		err         error
+	)
+
+	credentials = &CloudCredentials{
+		apiKey: apiKey,
+	}
+
+	credentials.bxSession, err = InitBXService(apiKey, iamEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("NewCloudCredentials: bxSession = %+v", credentials.bxSession)
+
+	credentials.user, err = fetchUserDetails(credentials.bxSession, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials.authenticator, err = newAuthenticator(apiKey, iamEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveApiKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiKey     string
+		apiKeyFile string
+		env        map[string]string
+		cliFile    string
+		want       string
+		wantErr    string
+	}{
+		{"nothing set", "", "", nil, "", "", ""},
+		{"flag", "flag-key", "", nil, "", "flag-key", ""},
+		{"flag before the environment", "flag-key", "", map[string]string{"IBMCLOUD_API_KEY": "env-key"}, "", "flag-key", ""},
+		{"IBMCLOUD_API_KEY", "", "", map[string]string{"IBMCLOUD_API_KEY": "ibmcloud-key"}, "", "ibmcloud-key", ""},
+		{"IC_API_KEY", "", "", map[string]string{"IC_API_KEY": "ic-key"}, "", "ic-key", ""},
+		{"IBMCLOUD_API_KEY before IC_API_KEY", "", "", map[string]string{"IBMCLOUD_API_KEY": "ibmcloud-key", "IC_API_KEY": "ic-key"}, "", "ibmcloud-key", ""},
+		{"environment before the CLI file", "", "", map[string]string{"IC_API_KEY": "ic-key"}, `{"apikey": "cli-key"}`, "ic-key", ""},
+		{"CLI file", "", "", nil, `{"name": "test", "apikey": "cli-key"}`, "cli-key", ""},
+		{"CLI file with just the key", "", "", nil, "cli-key\n", "cli-key", ""},
+		{"CLI file without a key", "", "", nil, `{"name": "test"}`, "", "has no API key"},
+		{"key file", "", "key-file", nil, "", "file-key", ""},
+		{"key file instead of the CLI file", "", "key-file", nil, `{"apikey": "cli-key"}`, "file-key", ""},
+		{"key file not found", "", "missing-file", nil, "", "", "Could not read the API key file"},
+		{"key file and flag", "flag-key", "key-file", nil, "", "", "Both -apiKey and -apiKeyFile"},
+		{"key file and IBMCLOUD_API_KEY", "", "key-file", map[string]string{"IBMCLOUD_API_KEY": "env-key"}, "", "", "IBMCLOUD_API_KEY environment variable"},
+		{"key file and IC_API_KEY", "", "key-file", map[string]string{"IC_API_KEY": "env-key"}, "", "", "IC_API_KEY environment variable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("IBMCLOUD_HOME", home)
+			for _, name := range apiKeyEnvironmentVariables {
+				t.Setenv(name, tt.env[name])
+			}
+
+			if tt.cliFile != "" {
+				fileName := filepath.Join(home, cliApiKeyFile)
+				if err := os.MkdirAll(filepath.Dir(fileName), 0700); err != nil {
+					t.Fatalf("MkdirAll: %v", err)
+				}
+				if err := os.WriteFile(fileName, []byte(tt.cliFile), 0600); err != nil {
+					t.Fatalf("WriteFile: %v", err)
+				}
+			}
+
+			apiKeyFile := tt.apiKeyFile
+			if apiKeyFile != "" {
+				apiKeyFile = filepath.Join(t.TempDir(), tt.apiKeyFile)
+				if tt.apiKeyFile == "key-file" {
+					if err := os.WriteFile(apiKeyFile, []byte(`{"apikey": "file-key"}`), 0600); err != nil {
+						t.Fatalf("WriteFile: %v", err)
+					}
+				}
+			}
+
+			got, err := resolveApiKey(tt.apiKey, apiKeyFile)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveApiKey() = %q, %v, want the error %q", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("resolveApiKey() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -39,21 +39,39 @@ type DNS struct {
 	dnsSvc *dnssvcsv1.DnsSvcsV1
 
 	//
-	dnsRecordsSvc *dnsrecordsv1.DnsRecordsV1
+	dnsRecordsSvc DnsRecordsClient
 }
 
 const (
 	dnsObjectName = "Domain Name Service"
 )
 
-func NewDNS(services *Services) ([]RunnableObject, []error) {
+func init() {
+	RegisterRunnableObject(RunnableObjectRegistration{
+		Name:         dnsObjectName,
+		Key:          "dns",
+		New:          NewDNS,
+		Modes:        []RunMode{RunModeCreate},
+		Dependencies: []string{lbObjectName},
+		Order:        70,
+	})
+}
+
+func NewDNS(ctx context.Context, services *Services) ([]RunnableObject, []error) {
 	var (
 		dnsSvc        *dnssvcsv1.DnsSvcsV1
 		dnsRecordsSvc *dnsrecordsv1.DnsRecordsV1
 		err           error
 	)
 
-	dnsSvc, dnsRecordsSvc, err = initDNSService(services)
+	if services.GetFakeCloud() != nil {
+		return []RunnableObject{&DNS{
+			services:      services,
+			dnsRecordsSvc: services.GetFakeCloud(),
+		}}, []error{nil}
+	}
+
+	dnsSvc, dnsRecordsSvc, err = initDNSService(ctx, services)
 	if err != nil {
 		return []RunnableObject{}, []error{err}
 	}
@@ -65,12 +83,12 @@ func NewDNS(services *Services) ([]RunnableObject, []error) {
 	}}, []error{nil}
 }
 
-func initDNSService(services *Services) (*dnssvcsv1.DnsSvcsV1, *dnsrecordsv1.DnsRecordsV1, error) {
+func initDNSService(ctx context.Context, services *Services) (*dnssvcsv1.DnsSvcsV1, *dnsrecordsv1.DnsRecordsV1, error) {
 	var (
 		authenticator       core.Authenticator
 		dnsService          *dnssvcsv1.DnsSvcsV1
 		globalOptions       *dnsrecordsv1.DnsRecordsV1Options
-		controllerSvc       *resourcecontrollerv2.ResourceControllerV2
+		controllerSvc       ResourceControllerClient
 		metadata            *Metadata
 		listResourceOptions *resourcecontrollerv2.ListResourceInstancesOptions
 		dnsRecordService    *dnsrecordsv1.DnsRecordsV1
@@ -81,36 +99,24 @@ func initDNSService(services *Services) (*dnssvcsv1.DnsSvcsV1, *dnsrecordsv1.Dns
 		err                 error
 	)
 
-	authenticator = &core.IamAuthenticator{
-		ApiKey: services.GetApiKey(),
-	}
-	err = authenticator.Validate()
-	if err != nil {
-		return nil, nil, err
-	}
+	authenticator = services.GetAuthenticator()
+	metadata = services.GetMetadata()
 
 	dnsService, err = dnssvcsv1.NewDnsSvcsV1(&dnssvcsv1.DnsSvcsV1Options{
 		Authenticator: authenticator,
+		URL:           metadata.GetServiceEndpoint("DNSServices", dnssvcsv1.DefaultServiceURL),
 	})
 	if err != nil {
 		return nil, nil, err
 	}
-
-	authenticator = &core.IamAuthenticator{
-		ApiKey: services.GetApiKey(),
-	}
-	err = authenticator.Validate()
-	if err != nil {
-		return nil, nil, err
-	}
+	useCloudHTTPClient("DNSServices", dnsService.Service)
 
 	controllerSvc = services.GetControllerSvc()
-	metadata = services.GetMetadata()
 
-	listResourceOptions = controllerSvc.NewListResourceInstancesOptions()
+	listResourceOptions = &resourcecontrollerv2.ListResourceInstancesOptions{}
 	listResourceOptions.SetResourceID("75874a60-cb12-11e7-948e-37ac098eb1b9") // CIS service ID
 
-	listResourceInstancesResponse, _, err := controllerSvc.ListResourceInstances(listResourceOptions)
+	listResourceInstancesResponse, _, err := controllerSvc.ListResourceInstancesWithContext(ctx, listResourceOptions)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -118,21 +124,15 @@ func initDNSService(services *Services) (*dnssvcsv1.DnsSvcsV1, *dnsrecordsv1.Dns
 	for _, instance := range listResourceInstancesResponse.Resources {
 		log.Debugf("initDNSService: instance.CRN = %s", *instance.CRN)
 
-		authenticator = &core.IamAuthenticator{
-			ApiKey: services.GetApiKey(),
-		}
-		err = authenticator.Validate()
-		if err != nil {
-			return nil, nil, err
-		}
-
 		zonesService, err = zonesv1.NewZonesV1(&zonesv1.ZonesV1Options{
 			Authenticator: authenticator,
+			URL:           metadata.GetServiceEndpoint("CIS", zonesv1.DefaultServiceURL),
 			Crn:           instance.CRN,
 		})
 		if err != nil {
 			return nil, nil, err
 		}
+		useCloudHTTPClient("CIS", zonesService.Service)
 		log.Debugf("initDNSService: zonesService = %+v", zonesService)
 
 		listZonesOptions = zonesService.NewListZonesOptions()
@@ -153,32 +153,27 @@ func initDNSService(services *Services) (*dnssvcsv1.DnsSvcsV1, *dnsrecordsv1.Dns
 	}
 	log.Debugf("initDNSService: zoneID = %s", zoneID)
 
-	authenticator = &core.IamAuthenticator{
-		ApiKey: services.GetApiKey(),
-	}
-	err = authenticator.Validate()
-	if err != nil {
-		return nil, nil, err
-	}
-
 	CRN := metadata.GetCISInstanceCRN()
 
 	globalOptions = &dnsrecordsv1.DnsRecordsV1Options{
 		Authenticator:  authenticator,
+		URL:            metadata.GetServiceEndpoint("CIS", dnsrecordsv1.DefaultServiceURL),
 		Crn:            &CRN,
 		ZoneIdentifier: &zoneID,
 	}
 	dnsRecordService, err = dnsrecordsv1.NewDnsRecordsV1(globalOptions)
+	if err == nil {
+		useCloudHTTPClient("CIS", dnsRecordService.Service)
+	}
 	log.Debugf("initDNSService: dnsRecordService = %+v", dnsRecordService)
 
 	return dnsService, dnsRecordService, err
 }
 
 // listDNSRecords lists DNS records for the cluster.
-func (dns *DNS) listDNSRecords() ([]string, error) {
+func (dns *DNS) listDNSRecords(ctx context.Context) ([]string, error) {
 	var (
 		metadata *Metadata
-		ctx      context.Context
 		cancel   context.CancelFunc
 		result   []string
 	)
@@ -187,7 +182,7 @@ func (dns *DNS) listDNSRecords() ([]string, error) {
 
 	metadata = dns.services.GetMetadata()
 
-	ctx, cancel = dns.services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	select {
@@ -204,7 +199,7 @@ func (dns *DNS) listDNSRecords() ([]string, error) {
 		moreData       = true
 	)
 
-	dnsRecordsOptions := dns.dnsRecordsSvc.NewListAllDnsRecordsOptions()
+	dnsRecordsOptions := &dnsrecordsv1.ListAllDnsRecordsOptions{}
 	dnsRecordsOptions.PerPage = &perPage
 	dnsRecordsOptions.Page = &page
 
@@ -283,36 +278,42 @@ func (dns *DNS) ObjectName() (string, error) {
 	return dnsObjectName, nil
 }
 
-func (dns *DNS) Run() error {
+func (dns *DNS) Run(ctx context.Context) error {
 	// Nothing to do!
 	return nil
 }
 
-func (dns *DNS) CiStatus(shouldClean bool) {
+func (dns *DNS) CiStatus(ctx context.Context, shouldClean bool) *ObjectResult {
+	return NewObjectResult(dns, "")
 }
 
-func (dns *DNS) ClusterStatus() {
+func (dns *DNS) ClusterStatus(ctx context.Context) *ObjectResult {
 	var (
+		result   *ObjectResult
 		metadata *Metadata
 		records  []string
-		patterns = []string{"api-int", "api", "*.apps"}
+		patterns []string
 		name     string
 		found    bool
 		err      error
 	)
 
+	result = NewObjectResult(dns, "")
+
 	metadata = dns.services.GetMetadata()
+	patterns = metadata.GetExpectations().DNSRecords
 
-	records, err = dns.listDNSRecords()
+	records, err = dns.listDNSRecords(ctx)
 	if err != nil {
-		fmt.Printf("%s is NOTOK. Could not list DNS records: %v\n", dnsObjectName, err)
-		return
+		result.AddError("dns.records", "Could not list DNS records: %v", err)
+		return result
 	}
 	log.Debugf("Valid: records = %+v", records)
 
-	if len(records) != 3 {
-		fmt.Printf("%s is NOTOK. Expecting 3 DNS records, found %d (%+v)\n", dnsObjectName, len(records), records)
-		return
+	if len(records) != len(patterns) {
+		result.AddNotOK("dns.records", "Expecting %d DNS records, found %d (%+v)", len(patterns), len(records), records).
+			WithEvidence("count", fmt.Sprintf("%d", len(records)))
+		return result
 	}
 
 	for _, pattern := range patterns {
@@ -326,17 +327,16 @@ func (dns *DNS) ClusterStatus() {
 			}
 		}
 		if !found {
-			fmt.Printf("%s is NOTOK. Expecting DNS record %s to exist\n", dnsObjectName, name)
-			return
+			result.AddNotOK("dns.record", "Expecting DNS record %s to exist", name).
+				WithEvidence("record", name)
+			return result
 		}
 
+		result.AddOK("dns.record", "found DNS record %s", name).
+			WithEvidence("record", name)
+
 		// @TODO maybe do a DNS lookup on the name?
 	}
 
-	fmt.Printf("%s is OK.\n", dnsObjectName)
-	return
-}
-
-func (dns *DNS) Priority() (int, error) {
-	return 10, nil
+	return result
 }
@@ -0,0 +1,70 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDNSClusterStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		clusterName string
+		wantStatus  CheckStatus
+		wantChecks  []string
+	}{
+		{"all records", "good", CheckStatusOK, []string{"dns.record"}},
+		{"missing records", "bad", CheckStatusNotOK, []string{"dns.records"}},
+		{"wrong record", "broken", CheckStatusNotOK, []string{"dns.record"}},
+		{"no records", "lost", CheckStatusNotOK, []string{"dns.records"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestClusterServices(t, tt.clusterName, "g-good", "good-vpc")
+
+			dnses, _ := NewDNS(context.Background(), services)
+			if len(dnses) != 1 {
+				t.Fatalf("NewDNS returned %d DNS, want 1", len(dnses))
+			}
+
+			result := dnses[0].ClusterStatus(context.Background())
+			if result.Status != tt.wantStatus {
+				t.Errorf("ClusterStatus().Status = %s, want %s%s", result.Status, tt.wantStatus, dumpChecks(result))
+			}
+			for _, id := range tt.wantChecks {
+				if !hasCheck(result, id, tt.wantStatus) {
+					t.Errorf("ClusterStatus() has no %s check %s%s", tt.wantStatus, id, dumpChecks(result))
+				}
+			}
+		})
+	}
+}
+
+func TestDNSCiStatus(t *testing.T) {
+	services := newTestClusterServices(t, "bad", "g-good", "good-vpc")
+
+	dnses, _ := NewDNS(context.Background(), services)
+	if len(dnses) != 1 {
+		t.Fatalf("NewDNS returned %d DNS, want 1", len(dnses))
+	}
+
+	// check-ci does not look at the DNS records, so even missing ones report nothing.
+	result := dnses[0].CiStatus(context.Background(), false)
+	if result.Status != CheckStatusOK || len(result.Checks) != 0 {
+		t.Errorf("CiStatus() = %s with %d checks, want %s with none%s", result.Status, len(result.Checks), CheckStatusOK, dumpChecks(result))
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/IBM-Cloud/power-go-client/power/models"
+
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// How many of the last system reference codes the check message shows.
+	diagnosticsSummarySRCs = 3
+)
+
+// InstanceDiagnostics is what PowerVS tells about an instance which is not ACTIVE.
+//
+// The PowerVS API does not return the text of the console of an instance, it only
+// opens an interactive console session, which a read-only check must not do.  So the
+// diagnostics are the state of the instance and its system reference codes (SRCs),
+// which are the progress and error codes of the firmware and of the boot, and which
+// are what the console shows while an instance does not boot.
+type InstanceDiagnostics struct {
+	ServerName    string
+	PvmInstanceID string
+	Status        string
+	Health        string
+	TaskState     string
+	Progress      float64
+	Fault         string
+	SRCs          []*models.SRC
+	CollectedAt   time.Time
+}
+
+// NewInstanceDiagnostics gathers the diagnostics of an instance.
+func NewInstanceDiagnostics(pvmInstance *models.PVMInstance) *InstanceDiagnostics {
+	var (
+		diagnostics *InstanceDiagnostics
+	)
+
+	diagnostics = &InstanceDiagnostics{
+		ServerName:    ptr.Deref(pvmInstance.ServerName, ""),
+		PvmInstanceID: ptr.Deref(pvmInstance.PvmInstanceID, ""),
+		Status:        ptr.Deref(pvmInstance.Status, ""),
+		TaskState:     pvmInstance.TaskState,
+		Progress:      pvmInstance.Progress,
+		CollectedAt:   time.Now().UTC(),
+	}
+
+	if pvmInstance.Health != nil {
+		diagnostics.Health = pvmInstance.Health.Status
+		if pvmInstance.Health.Reason != "" {
+			diagnostics.Health += fmt.Sprintf(" (%s)", pvmInstance.Health.Reason)
+		}
+	}
+
+	if pvmInstance.Fault != nil {
+		diagnostics.Fault = fmt.Sprintf("%g %s", pvmInstance.Fault.Code, pvmInstance.Fault.Message)
+	}
+
+	// The SRCs come in groups, oldest first.
+	for _, srcs := range pvmInstance.Srcs {
+		for _, src := range srcs {
+			if src != nil && src.Src != "" {
+				diagnostics.SRCs = append(diagnostics.SRCs, src)
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+// LastSRCs returns the last count system reference codes, as "SRC (timestamp)".
+func (diagnostics *InstanceDiagnostics) LastSRCs(count int) []string {
+	var (
+		last = make([]string, 0, count)
+	)
+
+	for _, src := range diagnostics.SRCs[max(0, len(diagnostics.SRCs)-count):] {
+		if src.Timestamp != "" {
+			last = append(last, fmt.Sprintf("%s (%s)", src.Src, src.Timestamp))
+		} else {
+			last = append(last, src.Src)
+		}
+	}
+
+	return last
+}
+
+// Save writes the diagnostics into <dir>/<server name>.txt, which only the user can read,
+// and returns the file name.
+func (diagnostics *InstanceDiagnostics) Save(dir string) (string, error) {
+	var (
+		filename string
+		builder  strings.Builder
+		err      error
+	)
+
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return "", fmt.Errorf("Error: Could not create the diagnostics directory (%v)", err)
+	}
+
+	filename = filepath.Join(dir, filepath.Base(diagnostics.ServerName)+".txt")
+
+	fmt.Fprintf(&builder, "Server name:  %s\n", diagnostics.ServerName)
+	fmt.Fprintf(&builder, "Instance ID:  %s\n", diagnostics.PvmInstanceID)
+	fmt.Fprintf(&builder, "Status:       %s\n", diagnostics.Status)
+	fmt.Fprintf(&builder, "Health:       %s\n", orDash(diagnostics.Health))
+	fmt.Fprintf(&builder, "Task state:   %s\n", orDash(diagnostics.TaskState))
+	fmt.Fprintf(&builder, "Progress:     %g\n", diagnostics.Progress)
+	fmt.Fprintf(&builder, "Fault:        %s\n", orDash(diagnostics.Fault))
+	fmt.Fprintf(&builder, "Collected at: %s\n", diagnostics.CollectedAt.Format(time.RFC3339))
+	fmt.Fprintf(&builder, "\n")
+	fmt.Fprintf(&builder, "The PowerVS API does not return the text of the console, open the console of the\n")
+	fmt.Fprintf(&builder, "instance in the IBM Cloud console to see it.\n")
+	fmt.Fprintf(&builder, "\n")
+
+	if len(diagnostics.SRCs) == 0 {
+		fmt.Fprintf(&builder, "There are no system reference codes.\n")
+	} else {
+		fmt.Fprintf(&builder, "System reference codes, oldest first:\n")
+		for _, src := range diagnostics.SRCs {
+			fmt.Fprintf(&builder, "%s  %s\n", orDash(src.Timestamp), src.Src)
+		}
+	}
+
+	// Only the user may read it, since it describes the instances of the cluster.
+	err = os.WriteFile(filename, []byte(builder.String()), 0600)
+	if err != nil {
+		return "", fmt.Errorf("Error: Could not save the diagnostics of %s (%v)", diagnostics.ServerName, err)
+	}
+
+	return filename, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+)
+
+const (
+	// All of the checks passed.
+	exitCodeOK = 0
+
+	// Something unexpected went wrong.
+	exitCodeError = 1
+
+	// The command line, the metadata, or the API key was bad.
+	exitCodeUsage = 2
+
+	// At least one check was NOTOK.
+	exitCodeChecksFailed = 3
+
+	// At least one object could not be queried.
+	exitCodeDiscoveryErrors = 4
+
+	// The command was interrupted by SIGINT or SIGTERM.
+	exitCodeInterrupted = 130
+)
+
+// ExitError carries the exit code the program should exit with.
+// Err may be nil when there is nothing left to print, for example when
+// the results have already been output.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit code %d", e.Code)
+	}
+
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// usageError marks err as a usage or credential error.
+func usageError(err error) error {
+	return &ExitError{
+		Code: exitCodeUsage,
+		Err:  err,
+	}
+}
+
+// usageErrorf formats a usage or credential error.
+func usageErrorf(format string, a ...any) error {
+	return usageError(fmt.Errorf(format, a...))
+}
+
+// ExitError returns the error the command should return based on the report.
+// An interruption and then discovery errors take precedence over failed checks since
+// the results are incomplete.
+func (report *Report) ExitError() error {
+	if report.Cancelled {
+		return &ExitError{Code: exitCodeInterrupted}
+	}
+
+	if len(report.Errors) > 0 {
+		return &ExitError{Code: exitCodeDiscoveryErrors}
+	}
+
+	if report.Status != CheckStatusOK {
+		return &ExitError{Code: exitCodeChecksFailed}
+	}
+
+	return nil
+}
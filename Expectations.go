@@ -0,0 +1,334 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Topology is the shape of a cluster, which decides what the checks expect of it.
+type Topology string
+
+const (
+	// Three control plane nodes and separate compute nodes.
+	TopologyHA Topology = "ha"
+
+	// Three control plane nodes which are also the compute nodes.
+	TopologyCompact Topology = "compact"
+
+	// Single node OpenShift.
+	TopologySNO Topology = "sno"
+
+	// Published Internal, so there is no public load balancer.
+	TopologyPrivate Topology = "private"
+)
+
+// Expectations is what the checks expect of a cluster.
+type Expectations struct {
+	Topology Topology `json:"topology"`
+	Release  string   `json:"release,omitempty"`
+
+	ControlPlaneReplicas int `json:"controlPlaneReplicas"`
+
+	// -1 when the number of compute nodes is not known.
+	ComputeReplicas int `json:"computeReplicas"`
+
+	// The least number of subnets of the VPC.
+	VPCSubnets int `json:"vpcSubnets"`
+
+	// The DNS records of the cluster, without .<cluster name>.<base domain>.
+	DNSRecords []string `json:"dnsRecords"`
+
+	ExternalLoadBalancer bool `json:"externalLoadBalancer"`
+
+	// What watch-create waits for: the conditions of the IBMPowerVSCluster and of the
+	// IBMPowerVSImage, and the number of IBMPowerVSMachines.
+	CAPIClusterConditions int `json:"capiClusterConditions"`
+	CAPIImageConditions   int `json:"capiImageConditions"`
+	CAPIMachines          int `json:"capiMachines"`
+}
+
+// ExpectationsProfile is the YAML file of the -expectations flag.  Every field is
+// optional and overrides the defaults of the topology.  The overrides under releases
+// apply when release is that release or one of its patch releases, for example 4.19
+// applies to 4.19.3.
+type ExpectationsProfile struct {
+	Topology Topology `json:"topology,omitempty"`
+	Release  string   `json:"release,omitempty"`
+
+	expectationsOverrides `json:",inline"`
+
+	Releases map[string]expectationsOverrides `json:"releases,omitempty"`
+}
+
+type expectationsOverrides struct {
+	ControlPlaneReplicas  *int     `json:"controlPlaneReplicas,omitempty"`
+	ComputeReplicas       *int     `json:"computeReplicas,omitempty"`
+	VPCSubnets            *int     `json:"vpcSubnets,omitempty"`
+	DNSRecords            []string `json:"dnsRecords,omitempty"`
+	ExternalLoadBalancer  *bool    `json:"externalLoadBalancer,omitempty"`
+	CAPIClusterConditions *int     `json:"capiClusterConditions,omitempty"`
+	CAPIImageConditions   *int     `json:"capiImageConditions,omitempty"`
+	CAPIMachines          *int     `json:"capiMachines,omitempty"`
+}
+
+var (
+	topologyDefaults = map[Topology]Expectations{
+		TopologyHA: {
+			ControlPlaneReplicas:  3,
+			ComputeReplicas:       -1,
+			VPCSubnets:            3,
+			DNSRecords:            []string{"api-int", "api", "*.apps"},
+			ExternalLoadBalancer:  true,
+			CAPIClusterConditions: 8,
+			CAPIImageConditions:   2,
+		},
+		TopologyCompact: {
+			ControlPlaneReplicas:  3,
+			ComputeReplicas:       0,
+			VPCSubnets:            3,
+			DNSRecords:            []string{"api-int", "api", "*.apps"},
+			ExternalLoadBalancer:  true,
+			CAPIClusterConditions: 8,
+			CAPIImageConditions:   2,
+		},
+		TopologySNO: {
+			ControlPlaneReplicas:  1,
+			ComputeReplicas:       0,
+			VPCSubnets:            3,
+			DNSRecords:            []string{"api-int", "api", "*.apps"},
+			ExternalLoadBalancer:  true,
+			CAPIClusterConditions: 8,
+			CAPIImageConditions:   2,
+		},
+		TopologyPrivate: {
+			ControlPlaneReplicas:  3,
+			ComputeReplicas:       -1,
+			VPCSubnets:            3,
+			DNSRecords:            []string{"api-int", "api", "*.apps"},
+			ExternalLoadBalancer:  false,
+			CAPIClusterConditions: 8,
+			CAPIImageConditions:   2,
+		},
+	}
+)
+
+// ParseTopology parses the name of a topology.
+func ParseTopology(topology string) (Topology, error) {
+	switch Topology(strings.ToLower(topology)) {
+	case TopologyHA:
+		return TopologyHA, nil
+	case TopologyCompact:
+		return TopologyCompact, nil
+	case TopologySNO:
+		return TopologySNO, nil
+	case TopologyPrivate:
+		return TopologyPrivate, nil
+	}
+
+	return "", fmt.Errorf("Error: topology is not ha/compact/sno/private (%s)", topology)
+}
+
+// readExpectationsProfile reads the -expectations file.
+func readExpectationsProfile(filename string) (*ExpectationsProfile, error) {
+	var (
+		content  []byte
+		profile  ExpectationsProfile
+		topology Topology
+		err      error
+	)
+
+	content, err = os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not read the expectations (%v)", err)
+	}
+
+	err = yaml.UnmarshalStrict(content, &profile)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not parse the expectations %s (%v)", filename, err)
+	}
+
+	if profile.Topology != "" {
+		topology, err = ParseTopology(string(profile.Topology))
+		if err != nil {
+			return nil, fmt.Errorf("Error: The topology of the expectations %s is not ha/compact/sno/private (%s)", filename, profile.Topology)
+		}
+		profile.Topology = topology
+	}
+
+	return &profile, nil
+}
+
+// topologyOfInstallConfig derives the topology from install-config.yaml.
+func topologyOfInstallConfig(installConfig *InstallConfig) Topology {
+	var (
+		computeReplicas int64
+	)
+
+	if installConfig.Publish == "Internal" {
+		return TopologyPrivate
+	}
+
+	if installConfig.ControlPlane != nil && installConfig.ControlPlane.Replicas != nil && *installConfig.ControlPlane.Replicas == 1 {
+		return TopologySNO
+	}
+
+	for _, pool := range installConfig.Compute {
+		if pool.Replicas != nil {
+			computeReplicas += *pool.Replicas
+		}
+	}
+	if len(installConfig.Compute) > 0 && computeReplicas == 0 {
+		return TopologyCompact
+	}
+
+	return TopologyHA
+}
+
+// NewExpectations returns what the checks expect of the cluster.  The defaults of the
+// topology are overridden by the number of nodes of install-config.yaml, then by the
+// profile and then by the overrides of the release in the profile.  The topology is the
+// one of the profile, or the one derived from the cluster, or ha.  profile and
+// installConfig may be nil.
+func NewExpectations(profile *ExpectationsProfile, installConfig *InstallConfig, derived Topology) *Expectations {
+	var (
+		topology     Topology
+		expectations Expectations
+		releases     []string
+	)
+
+	switch {
+	case profile != nil && profile.Topology != "":
+		topology = profile.Topology
+	case installConfig != nil:
+		topology = topologyOfInstallConfig(installConfig)
+	case derived != "":
+		topology = derived
+	default:
+		topology = TopologyHA
+	}
+
+	expectations = topologyDefaults[topology]
+	expectations.Topology = topology
+	expectations.DNSRecords = append([]string(nil), expectations.DNSRecords...)
+
+	if installConfig != nil {
+		if installConfig.ControlPlane != nil && installConfig.ControlPlane.Replicas != nil {
+			expectations.ControlPlaneReplicas = int(*installConfig.ControlPlane.Replicas)
+		}
+
+		if len(installConfig.Compute) > 0 {
+			expectations.ComputeReplicas = 0
+			for _, pool := range installConfig.Compute {
+				if pool.Replicas != nil {
+					expectations.ComputeReplicas += int(*pool.Replicas)
+				}
+			}
+		}
+	}
+
+	if profile != nil {
+		expectations.Release = profile.Release
+		expectations.apply(profile.expectationsOverrides)
+
+		// The most specific release is applied last.
+		for release := range profile.Releases {
+			if profile.Release == release || strings.HasPrefix(profile.Release, release+".") {
+				releases = append(releases, release)
+			}
+		}
+		sort.Slice(releases, func(i, j int) bool { return len(releases[i]) < len(releases[j]) })
+		for _, release := range releases {
+			expectations.apply(profile.Releases[release])
+		}
+	}
+
+	// The installer creates a bootstrap machine besides the control plane machines.
+	if expectations.CAPIMachines == 0 {
+		expectations.CAPIMachines = expectations.ControlPlaneReplicas + 1
+	}
+
+	return &expectations
+}
+
+func (expectations *Expectations) apply(overrides expectationsOverrides) {
+	if overrides.ControlPlaneReplicas != nil {
+		expectations.ControlPlaneReplicas = *overrides.ControlPlaneReplicas
+	}
+	if overrides.ComputeReplicas != nil {
+		expectations.ComputeReplicas = *overrides.ComputeReplicas
+	}
+	if overrides.VPCSubnets != nil {
+		expectations.VPCSubnets = *overrides.VPCSubnets
+	}
+	if overrides.DNSRecords != nil {
+		expectations.DNSRecords = overrides.DNSRecords
+	}
+	if overrides.ExternalLoadBalancer != nil {
+		expectations.ExternalLoadBalancer = *overrides.ExternalLoadBalancer
+	}
+	if overrides.CAPIClusterConditions != nil {
+		expectations.CAPIClusterConditions = *overrides.CAPIClusterConditions
+	}
+	if overrides.CAPIImageConditions != nil {
+		expectations.CAPIImageConditions = *overrides.CAPIImageConditions
+	}
+	if overrides.CAPIMachines != nil {
+		expectations.CAPIMachines = *overrides.CAPIMachines
+	}
+}
+
+// ControlPlaneNames returns the names of the control plane nodes, master-0 and so on.
+func (expectations *Expectations) ControlPlaneNames() []string {
+	var (
+		names = make([]string, expectations.ControlPlaneReplicas)
+	)
+
+	for i := range names {
+		names[i] = fmt.Sprintf("master-%d", i)
+	}
+
+	return names
+}
+
+// installDirExpectations is what watch-create expects of the cluster which is being
+// installed, for when the metadata cannot be read yet.
+func installDirExpectations(installDir string, profileFile string) (*Expectations, error) {
+	var (
+		installConfig *InstallConfig
+		profile       *ExpectationsProfile
+		err           error
+	)
+
+	installConfig, err = readInstallConfig(filepath.Join(installDir, installConfigFile))
+	if err != nil {
+		return nil, err
+	}
+
+	if profileFile != "" {
+		profile, err = readExpectationsProfile(profileFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewExpectations(profile, installConfig, ""), nil
+}
@@ -0,0 +1,1319 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	gohttp "net/http"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+
+	"github.com/IBM/ibm-cos-sdk-go/aws"
+	"github.com/IBM/ibm-cos-sdk-go/aws/awserr"
+	"github.com/IBM/ibm-cos-sdk-go/aws/request"
+	"github.com/IBM/ibm-cos-sdk-go/service/s3"
+
+	"github.com/IBM/networking-go-sdk/dnsrecordsv1"
+	"github.com/IBM/networking-go-sdk/transitgatewayapisv1"
+
+	"github.com/IBM/platform-services-go-sdk/resourcecontrollerv2"
+	"github.com/IBM/platform-services-go-sdk/resourcemanagerv2"
+
+	"github.com/IBM/vpc-go-sdk/vpcv1"
+
+	"github.com/IBM-Cloud/power-go-client/power/models"
+
+	"k8s.io/utils/ptr"
+)
+
+// FakeCloud is an in-memory IBM Cloud backend which is seeded from a fixtures file.
+// It implements the client interfaces so that the objects can be checked without an
+// IBM Cloud account.  Deleting a resource removes it from the backend, creating
+// resources is not supported.
+//
+// The fixtures file is a JSON object.  The IBM Cloud resources use the same JSON as
+// the IBM Cloud APIs return, so the output of a real call can be pasted in:
+//
+//	{
+//	  "account": "",
+//	  "resourceGroups": [],
+//	  "resourceInstances": [],
+//	  "vpcs": [],
+//	  "subnets": [],
+//	  "instances": [],
+//	  "loadBalancers": [],
+//	  "loadBalancerPools": { "<load balancer ID>": [] },
+//	  "loadBalancerPoolMembers": { "<pool ID>": [] },
+//	  "transitGateways": [],
+//	  "transitConnections": [],
+//	  "dnsRecords": [],
+//	  "buckets": { "<bucket name>": [ { "key": "", "size": 0 } ] },
+//	  "powerVS": {
+//	    "<service instance GUID>": {
+//	      "pvmInstances": [],
+//	      "dhcpServers": [],
+//	      "images": [],
+//	      "stockImages": [],
+//	      "networks": [],
+//	      "networkPorts": { "<network ID>": [] },
+//	      "networkInterfaces": { "<network ID>": [] },
+//	      "sshKeys": [],
+//	      "volumes": [],
+//	      "jobs": []
+//	    }
+//	  },
+//	  "datacenters": []
+//	}
+type FakeCloud struct {
+	mutex sync.Mutex
+
+	account string
+
+	resourceGroups []resourcemanagerv2.ResourceGroup
+
+	resourceInstances []resourcecontrollerv2.ResourceInstance
+
+	vpcs []vpcv1.VPC
+
+	subnets []vpcv1.Subnet
+
+	instances []vpcv1.Instance
+
+	loadBalancers []vpcv1.LoadBalancer
+
+	// Keyed by the load balancer ID.
+	loadBalancerPools map[string][]vpcv1.LoadBalancerPool
+
+	// Keyed by the pool ID.
+	loadBalancerPoolMembers map[string][]vpcv1.LoadBalancerPoolMember
+
+	transitGateways []transitgatewayapisv1.TransitGateway
+
+	transitConnections []transitgatewayapisv1.TransitConnection
+
+	dnsRecords []dnsrecordsv1.DnsrecordDetails
+
+	// Keyed by the bucket name.
+	buckets map[string][]fakeBucketObject
+
+	// Keyed by the service instance GUID.
+	powerVS map[string]*fakePowerVS
+
+	// Keyed by a call of a PowerVS client, for example "PIJobClient.GetAll", the errors
+	// which its next calls return instead of doing anything.
+	powerVSFailures map[string][]error
+
+	datacenters []*models.Datacenter
+}
+
+type fakeBucketObject struct {
+	Key  string `json:"key"`
+	Size int64  `json:"size"`
+}
+
+// fakePowerVS holds the resources of one PowerVS service instance.  They use the JSON
+// of the PowerVS API.
+type fakePowerVS struct {
+	PvmInstances      []*models.PVMInstance                 `json:"pvmInstances"`
+	DhcpServers       []*models.DHCPServerDetail            `json:"dhcpServers"`
+	Images            []*models.ImageReference              `json:"images"`
+	StockImages       []*models.ImageReference              `json:"stockImages"`
+	Networks          []*models.Network                     `json:"networks"`
+	NetworkPorts      map[string][]*models.NetworkPort      `json:"networkPorts"`
+	NetworkInterfaces map[string][]*models.NetworkInterface `json:"networkInterfaces"`
+	SshKeys           []*models.SSHKey                      `json:"sshKeys"`
+	Volumes           []*models.VolumeReference             `json:"volumes"`
+	Jobs              []*models.Job                         `json:"jobs"`
+}
+
+const (
+	fakeCloudURL = "https://fake.cloud.ibm.com"
+)
+
+// Make sure the fake keeps satisfying the interfaces.
+var (
+	_ VpcClient                = (*FakeCloud)(nil)
+	_ ResourceControllerClient = (*FakeCloud)(nil)
+	_ ResourceManagerClient    = (*FakeCloud)(nil)
+	_ TransitGatewayClient     = (*FakeCloud)(nil)
+	_ DnsRecordsClient         = (*FakeCloud)(nil)
+	_ ObjectStorageClient      = (*FakeCloud)(nil)
+	_ PINetworkClient          = fakePINetworkClient{}
+	_ PIKeyClient              = fakePIKeyClient{}
+	_ PIImageClient            = fakePIImageClient{}
+	_ PIDhcpClient             = fakePIDhcpClient{}
+	_ PIInstanceClient         = fakePIInstanceClient{}
+	_ PIVolumeClient           = fakePIVolumeClient{}
+	_ PIJobClient              = fakePIJobClient{}
+	_ PIDatacentersClient      = fakePIDatacentersClient{}
+)
+
+// NewFakeCloud reads the fixtures file and returns a backend seeded with it.
+func NewFakeCloud(fixtures string) (*FakeCloud, error) {
+	var (
+		content     []byte
+		rawFixtures map[string]json.RawMessage
+		fake        *FakeCloud
+		err         error
+	)
+
+	content, err = os.ReadFile(fixtures)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not read the fixtures file %s (%v)", fixtures, err)
+	}
+
+	err = json.Unmarshal(content, &rawFixtures)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not parse the fixtures file %s (%v)", fixtures, err)
+	}
+
+	fake = &FakeCloud{
+		loadBalancerPools:       make(map[string][]vpcv1.LoadBalancerPool),
+		loadBalancerPoolMembers: make(map[string][]vpcv1.LoadBalancerPoolMember),
+		buckets:                 make(map[string][]fakeBucketObject),
+		powerVS:                 make(map[string]*fakePowerVS),
+		powerVSFailures:         make(map[string][]error),
+	}
+
+	for _, load := range []struct {
+		key string
+		fn  func() error
+	}{
+		{"account", func() error { return core.UnmarshalPrimitive(rawFixtures, "account", &fake.account) }},
+		{"resourceGroups", func() error {
+			return core.UnmarshalModel(rawFixtures, "resourceGroups", &fake.resourceGroups, resourcemanagerv2.UnmarshalResourceGroup)
+		}},
+		{"resourceInstances", func() error {
+			return core.UnmarshalModel(rawFixtures, "resourceInstances", &fake.resourceInstances, resourcecontrollerv2.UnmarshalResourceInstance)
+		}},
+		{"vpcs", func() error { return core.UnmarshalModel(rawFixtures, "vpcs", &fake.vpcs, vpcv1.UnmarshalVPC) }},
+		{"subnets", func() error { return core.UnmarshalModel(rawFixtures, "subnets", &fake.subnets, vpcv1.UnmarshalSubnet) }},
+		{"instances", func() error {
+			return core.UnmarshalModel(rawFixtures, "instances", &fake.instances, vpcv1.UnmarshalInstance)
+		}},
+		{"loadBalancers", func() error {
+			return core.UnmarshalModel(rawFixtures, "loadBalancers", &fake.loadBalancers, vpcv1.UnmarshalLoadBalancer)
+		}},
+		{"loadBalancerPools", func() error {
+			return unmarshalFakeModelMap(rawFixtures, "loadBalancerPools", fake.loadBalancerPools, vpcv1.UnmarshalLoadBalancerPool)
+		}},
+		{"loadBalancerPoolMembers", func() error {
+			return unmarshalFakeModelMap(rawFixtures, "loadBalancerPoolMembers", fake.loadBalancerPoolMembers, vpcv1.UnmarshalLoadBalancerPoolMember)
+		}},
+		{"transitGateways", func() error {
+			return core.UnmarshalModel(rawFixtures, "transitGateways", &fake.transitGateways, transitgatewayapisv1.UnmarshalTransitGateway)
+		}},
+		{"transitConnections", func() error {
+			return core.UnmarshalModel(rawFixtures, "transitConnections", &fake.transitConnections, transitgatewayapisv1.UnmarshalTransitConnection)
+		}},
+		{"dnsRecords", func() error {
+			return core.UnmarshalModel(rawFixtures, "dnsRecords", &fake.dnsRecords, dnsrecordsv1.UnmarshalDnsrecordDetails)
+		}},
+		{"buckets", func() error { return unmarshalFakeJSON(rawFixtures, "buckets", &fake.buckets) }},
+		{"powerVS", func() error { return unmarshalFakeJSON(rawFixtures, "powerVS", &fake.powerVS) }},
+		{"datacenters", func() error { return unmarshalFakeJSON(rawFixtures, "datacenters", &fake.datacenters) }},
+	} {
+		err = load.fn()
+		if err != nil {
+			return nil, fmt.Errorf("Error: Could not load %s from the fixtures file %s (%v)", load.key, fixtures, err)
+		}
+	}
+
+	log.Debugf("NewFakeCloud: len(vpcs) = %d, len(resourceInstances) = %d, len(powerVS) = %d", len(fake.vpcs), len(fake.resourceInstances), len(fake.powerVS))
+
+	return fake, nil
+}
+
+// unmarshalFakeModelMap loads an object of arrays, keyed by an ID, of IBM Cloud models.
+func unmarshalFakeModelMap[T any](rawFixtures map[string]json.RawMessage, key string, result map[string][]T, unmarshaller core.ModelUnmarshaller) error {
+	var (
+		rawMap map[string]json.RawMessage
+		err    error
+	)
+
+	err = unmarshalFakeJSON(rawFixtures, key, &rawMap)
+	if err != nil {
+		return err
+	}
+
+	for id := range rawMap {
+		var (
+			items []T
+		)
+
+		err = core.UnmarshalModel(rawMap, id, &items, unmarshaller)
+		if err != nil {
+			return err
+		}
+		result[id] = items
+	}
+
+	return nil
+}
+
+// unmarshalFakeJSON loads a key which does not use the IBM Cloud models.
+func unmarshalFakeJSON(rawFixtures map[string]json.RawMessage, key string, result any) error {
+	if rawFixtures[key] == nil {
+		return nil
+	}
+
+	return json.Unmarshal(rawFixtures[key], result)
+}
+
+// fakePage returns the items of the page which begins at start and the start of the
+// next page.  The start is "" on the last page.
+func fakePage[T any](items []T, start *string, limit *int64) ([]T, string) {
+	var (
+		offset int
+		end    = len(items)
+		err    error
+	)
+
+	if start != nil && *start != "" {
+		offset, err = strconv.Atoi(*start)
+		if err != nil || offset < 0 {
+			offset = 0
+		}
+	}
+	if offset > len(items) {
+		offset = len(items)
+	}
+
+	if limit != nil && *limit > 0 && offset+int(*limit) < end {
+		end = offset + int(*limit)
+	}
+
+	if end < len(items) {
+		return items[offset:end], strconv.Itoa(end)
+	}
+
+	return items[offset:end], ""
+}
+
+// fakeHref returns the URL of a page of a collection.
+func fakeHref(path string, start string) *string {
+	if start == "" {
+		return ptr.To(fmt.Sprintf("%s/%s", fakeCloudURL, path))
+	}
+
+	return ptr.To(fmt.Sprintf("%s/%s?start=%s", fakeCloudURL, path, start))
+}
+
+// fakePageLink returns the VPC link to the next page, or nil on the last page.
+func fakePageLink(path string, start string) *vpcv1.PageLink {
+	if start == "" {
+		return nil
+	}
+
+	return &vpcv1.PageLink{Href: fakeHref(path, start)}
+}
+
+// fakeFilter returns the items for which keep is true.
+func fakeFilter[T any](items []T, keep func(T) bool) []T {
+	var (
+		result = make([]T, 0, len(items))
+	)
+
+	for _, item := range items {
+		if keep(item) {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// fakeMatches returns true if the filter is not set or equals the value.
+func fakeMatches(filter *string, value *string) bool {
+	return filter == nil || ptr.Deref(value, "") == *filter
+}
+
+func fakeResponse() *core.DetailedResponse {
+	return &core.DetailedResponse{StatusCode: gohttp.StatusOK}
+}
+
+func fakeNotFound(kind string, id string) (*core.DetailedResponse, error) {
+	return &core.DetailedResponse{StatusCode: gohttp.StatusNotFound}, fmt.Errorf("Error: %s %s not found", kind, id)
+}
+
+func fakeNotSupported(operation string) (*core.DetailedResponse, error) {
+	return &core.DetailedResponse{StatusCode: gohttp.StatusNotImplemented}, fmt.Errorf("Error: %s is not supported by the fake backend", operation)
+}
+
+// lock waits for the backend unless ctx is already cancelled.  The caller must unlock
+// the mutex when lock returns nil.
+func (fake *FakeCloud) lock(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	fake.mutex.Lock()
+
+	return nil
+}
+
+func (fake *FakeCloud) GetVPCWithContext(ctx context.Context, options *vpcv1.GetVPCOptions) (*vpcv1.VPC, *core.DetailedResponse, error) {
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	for _, vpc := range fake.vpcs {
+		if ptr.Deref(vpc.ID, "") == *options.ID {
+			return &vpc, fakeResponse(), nil
+		}
+	}
+
+	response, err := fakeNotFound("VPC", *options.ID)
+	return nil, response, err
+}
+
+func (fake *FakeCloud) ListVpcsWithContext(ctx context.Context, options *vpcv1.ListVpcsOptions) (*vpcv1.VPCCollection, *core.DetailedResponse, error) {
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	vpcs := fakeFilter(fake.vpcs, func(vpc vpcv1.VPC) bool {
+		return options.ResourceGroupID == nil || (vpc.ResourceGroup != nil && fakeMatches(options.ResourceGroupID, vpc.ResourceGroup.ID))
+	})
+	page, next := fakePage(vpcs, options.Start, options.Limit)
+
+	return &vpcv1.VPCCollection{
+		First:      &vpcv1.PageLink{Href: fakeHref("v1/vpcs", "")},
+		Limit:      options.Limit,
+		Next:       fakePageLink("v1/vpcs", next),
+		TotalCount: ptr.To(int64(len(vpcs))),
+		Vpcs:       page,
+	}, fakeResponse(), nil
+}
+
+func (fake *FakeCloud) ListSubnetsWithContext(ctx context.Context, options *vpcv1.ListSubnetsOptions) (*vpcv1.SubnetCollection, *core.DetailedResponse, error) {
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	subnets := fakeFilter(fake.subnets, func(subnet vpcv1.Subnet) bool {
+		return options.ResourceGroupID == nil || (subnet.ResourceGroup != nil && fakeMatches(options.ResourceGroupID, subnet.ResourceGroup.ID))
+	})
+	page, next := fakePage(subnets, options.Start, options.Limit)
+
+	return &vpcv1.SubnetCollection{
+		First:      &vpcv1.PageLink{Href: fakeHref("v1/subnets", "")},
+		Limit:      options.Limit,
+		Next:       fakePageLink("v1/subnets", next),
+		Subnets:    page,
+		TotalCount: ptr.To(int64(len(subnets))),
+	}, fakeResponse(), nil
+}
+
+func (fake *FakeCloud) GetVPCDefaultSecurityGroupWithContext(ctx context.Context, options *vpcv1.GetVPCDefaultSecurityGroupOptions) (*vpcv1.DefaultSecurityGroup, *core.DetailedResponse, error) {
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	// The security group rules are not part of the fixtures.
+	for _, vpc := range fake.vpcs {
+		if ptr.Deref(vpc.ID, "") == *options.ID {
+			return &vpcv1.DefaultSecurityGroup{
+				VPC: &vpcv1.VPCReference{
+					CRN:  vpc.CRN,
+					ID:   vpc.ID,
+					Name: vpc.Name,
+				},
+			}, fakeResponse(), nil
+		}
+	}
+
+	response, err := fakeNotFound("VPC", *options.ID)
+	return nil, response, err
+}
+
+func (fake *FakeCloud) ListInstancesWithContext(ctx context.Context, options *vpcv1.ListInstancesOptions) (*vpcv1.InstanceCollection, *core.DetailedResponse, error) {
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	instances := fakeFilter(fake.instances, func(instance vpcv1.Instance) bool {
+		if options.ResourceGroupID != nil && (instance.ResourceGroup == nil || !fakeMatches(options.ResourceGroupID, instance.ResourceGroup.ID)) {
+			return false
+		}
+		if options.VPCID != nil && (instance.VPC == nil || !fakeMatches(options.VPCID, instance.VPC.ID)) {
+			return false
+		}
+		return true
+	})
+	page, next := fakePage(instances, options.Start, options.Limit)
+
+	return &vpcv1.InstanceCollection{
+		First:      &vpcv1.PageLink{Href: fakeHref("v1/instances", "")},
+		Instances:  page,
+		Limit:      options.Limit,
+		Next:       fakePageLink("v1/instances", next),
+		TotalCount: ptr.To(int64(len(instances))),
+	}, fakeResponse(), nil
+}
+
+func (fake *FakeCloud) GetInstanceWithContext(ctx context.Context, options *vpcv1.GetInstanceOptions) (*vpcv1.Instance, *core.DetailedResponse, error) {
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	for _, instance := range fake.instances {
+		if ptr.Deref(instance.ID, "") == *options.ID {
+			return &instance, fakeResponse(), nil
+		}
+	}
+
+	response, err := fakeNotFound("instance", *options.ID)
+	return nil, response, err
+}
+
+func (fake *FakeCloud) CreateInstanceWithContext(ctx context.Context, options *vpcv1.CreateInstanceOptions) (*vpcv1.Instance, *core.DetailedResponse, error) {
+	response, err := fakeNotSupported("CreateInstance")
+	return nil, response, err
+}
+
+func (fake *FakeCloud) ListRegionZonesWithContext(ctx context.Context, options *vpcv1.ListRegionZonesOptions) (*vpcv1.ZoneCollection, *core.DetailedResponse, error) {
+	response, err := fakeNotSupported("ListRegionZones")
+	return nil, response, err
+}
+
+func (fake *FakeCloud) ListImagesWithContext(ctx context.Context, options *vpcv1.ListImagesOptions) (*vpcv1.ImageCollection, *core.DetailedResponse, error) {
+	response, err := fakeNotSupported("ListImages")
+	return nil, response, err
+}
+
+func (fake *FakeCloud) ListKeysWithContext(ctx context.Context, options *vpcv1.ListKeysOptions) (*vpcv1.KeyCollection, *core.DetailedResponse, error) {
+	response, err := fakeNotSupported("ListKeys")
+	return nil, response, err
+}
+
+func (fake *FakeCloud) ListFloatingIpsWithContext(ctx context.Context, options *vpcv1.ListFloatingIpsOptions) (*vpcv1.FloatingIPCollection, *core.DetailedResponse, error) {
+	response, err := fakeNotSupported("ListFloatingIps")
+	return nil, response, err
+}
+
+func (fake *FakeCloud) CreateFloatingIPWithContext(ctx context.Context, options *vpcv1.CreateFloatingIPOptions) (*vpcv1.FloatingIP, *core.DetailedResponse, error) {
+	response, err := fakeNotSupported("CreateFloatingIP")
+	return nil, response, err
+}
+
+func (fake *FakeCloud) ListInstanceNetworkInterfaceFloatingIpsWithContext(ctx context.Context, options *vpcv1.ListInstanceNetworkInterfaceFloatingIpsOptions) (*vpcv1.FloatingIPUnpaginatedCollection, *core.DetailedResponse, error) {
+	response, err := fakeNotSupported("ListInstanceNetworkInterfaceFloatingIps")
+	return nil, response, err
+}
+
+func (fake *FakeCloud) AddInstanceNetworkInterfaceFloatingIPWithContext(ctx context.Context, options *vpcv1.AddInstanceNetworkInterfaceFloatingIPOptions) (*vpcv1.FloatingIP, *core.DetailedResponse, error) {
+	response, err := fakeNotSupported("AddInstanceNetworkInterfaceFloatingIP")
+	return nil, response, err
+}
+
+func (fake *FakeCloud) GetLoadBalancerWithContext(ctx context.Context, options *vpcv1.GetLoadBalancerOptions) (*vpcv1.LoadBalancer, *core.DetailedResponse, error) {
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	for _, lb := range fake.loadBalancers {
+		if ptr.Deref(lb.ID, "") == *options.ID {
+			return &lb, fakeResponse(), nil
+		}
+	}
+
+	response, err := fakeNotFound("load balancer", *options.ID)
+	return nil, response, err
+}
+
+func (fake *FakeCloud) ListLoadBalancersWithContext(ctx context.Context, options *vpcv1.ListLoadBalancersOptions) (*vpcv1.LoadBalancerCollection, *core.DetailedResponse, error) {
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	page, next := fakePage(fake.loadBalancers, options.Start, options.Limit)
+
+	return &vpcv1.LoadBalancerCollection{
+		First:         &vpcv1.PageLink{Href: fakeHref("v1/load_balancers", "")},
+		Limit:         options.Limit,
+		LoadBalancers: page,
+		Next:          fakePageLink("v1/load_balancers", next),
+		TotalCount:    ptr.To(int64(len(fake.loadBalancers))),
+	}, fakeResponse(), nil
+}
+
+// hasLoadBalancer returns true if the load balancer is in the fixtures.  The caller
+// must hold the mutex.
+func (fake *FakeCloud) hasLoadBalancer(id string) bool {
+	return slices.ContainsFunc(fake.loadBalancers, func(lb vpcv1.LoadBalancer) bool {
+		return ptr.Deref(lb.ID, "") == id
+	})
+}
+
+func (fake *FakeCloud) ListLoadBalancerPoolsWithContext(ctx context.Context, options *vpcv1.ListLoadBalancerPoolsOptions) (*vpcv1.LoadBalancerPoolCollection, *core.DetailedResponse, error) {
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	if !fake.hasLoadBalancer(*options.LoadBalancerID) {
+		response, err := fakeNotFound("load balancer", *options.LoadBalancerID)
+		return nil, response, err
+	}
+
+	return &vpcv1.LoadBalancerPoolCollection{
+		Pools: fake.loadBalancerPools[*options.LoadBalancerID],
+	}, fakeResponse(), nil
+}
+
+func (fake *FakeCloud) GetLoadBalancerPoolWithContext(ctx context.Context, options *vpcv1.GetLoadBalancerPoolOptions) (*vpcv1.LoadBalancerPool, *core.DetailedResponse, error) {
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	for _, pool := range fake.loadBalancerPools[*options.LoadBalancerID] {
+		if ptr.Deref(pool.ID, "") == *options.ID {
+			return &pool, fakeResponse(), nil
+		}
+	}
+
+	response, err := fakeNotFound("load balancer pool", *options.ID)
+	return nil, response, err
+}
+
+func (fake *FakeCloud) ListLoadBalancerPoolMembersWithContext(ctx context.Context, options *vpcv1.ListLoadBalancerPoolMembersOptions) (*vpcv1.LoadBalancerPoolMemberCollection, *core.DetailedResponse, error) {
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	if !fake.hasLoadBalancer(*options.LoadBalancerID) {
+		response, err := fakeNotFound("load balancer", *options.LoadBalancerID)
+		return nil, response, err
+	}
+
+	return &vpcv1.LoadBalancerPoolMemberCollection{
+		Members: fake.loadBalancerPoolMembers[*options.PoolID],
+	}, fakeResponse(), nil
+}
+
+func (fake *FakeCloud) GetResourceInstanceWithContext(ctx context.Context, options *resourcecontrollerv2.GetResourceInstanceOptions) (*resourcecontrollerv2.ResourceInstance, *core.DetailedResponse, error) {
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	// Like the real API, either the ID or the GUID can be used.
+	for _, resourceInstance := range fake.resourceInstances {
+		if ptr.Deref(resourceInstance.ID, "") == *options.ID || ptr.Deref(resourceInstance.GUID, "") == *options.ID {
+			return &resourceInstance, fakeResponse(), nil
+		}
+	}
+
+	response, err := fakeNotFound("resource instance", *options.ID)
+	return nil, response, err
+}
+
+func (fake *FakeCloud) ListResourceInstancesWithContext(ctx context.Context, options *resourcecontrollerv2.ListResourceInstancesOptions) (*resourcecontrollerv2.ResourceInstancesList, *core.DetailedResponse, error) {
+	var (
+		nextURL *string
+	)
+
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	resourceInstances := fakeFilter(fake.resourceInstances, func(resourceInstance resourcecontrollerv2.ResourceInstance) bool {
+		return fakeMatches(options.ResourceGroupID, resourceInstance.ResourceGroupID) &&
+			fakeMatches(options.ResourceID, resourceInstance.ResourceID) &&
+			fakeMatches(options.Type, resourceInstance.Type) &&
+			fakeMatches(options.Name, resourceInstance.Name)
+	})
+	page, next := fakePage(resourceInstances, options.Start, options.Limit)
+	if next != "" {
+		nextURL = ptr.To(fmt.Sprintf("/v2/resource_instances?start=%s", next))
+	}
+
+	return &resourcecontrollerv2.ResourceInstancesList{
+		RowsCount: ptr.To(int64(len(page))),
+		NextURL:   nextURL,
+		Resources: page,
+	}, fakeResponse(), nil
+}
+
+func (fake *FakeCloud) ListResourceGroupsWithContext(ctx context.Context, options *resourcemanagerv2.ListResourceGroupsOptions) (*resourcemanagerv2.ResourceGroupList, *core.DetailedResponse, error) {
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	return &resourcemanagerv2.ResourceGroupList{
+		Resources: fakeFilter(fake.resourceGroups, func(resourceGroup resourcemanagerv2.ResourceGroup) bool {
+			return resourceGroup.AccountID == nil || fakeMatches(options.AccountID, resourceGroup.AccountID)
+		}),
+	}, fakeResponse(), nil
+}
+
+func (fake *FakeCloud) GetTransitGatewayWithContext(ctx context.Context, options *transitgatewayapisv1.GetTransitGatewayOptions) (*transitgatewayapisv1.TransitGateway, *core.DetailedResponse, error) {
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	for _, tg := range fake.transitGateways {
+		if ptr.Deref(tg.ID, "") == *options.ID {
+			return &tg, fakeResponse(), nil
+		}
+	}
+
+	response, err := fakeNotFound("transit gateway", *options.ID)
+	return nil, response, err
+}
+
+func (fake *FakeCloud) ListTransitGatewaysWithContext(ctx context.Context, options *transitgatewayapisv1.ListTransitGatewaysOptions) (*transitgatewayapisv1.TransitGatewayCollection, *core.DetailedResponse, error) {
+	var (
+		nextPage *transitgatewayapisv1.PaginationNextTG
+	)
+
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	page, next := fakePage(fake.transitGateways, options.Start, options.Limit)
+	if next != "" {
+		nextPage = &transitgatewayapisv1.PaginationNextTG{
+			Href:  fakeHref("transit_gateways", next),
+			Start: ptr.To(next),
+		}
+	}
+
+	return &transitgatewayapisv1.TransitGatewayCollection{
+		First:           &transitgatewayapisv1.PaginationFirstTG{Href: fakeHref("transit_gateways", "")},
+		Limit:           options.Limit,
+		Next:            nextPage,
+		TransitGateways: page,
+	}, fakeResponse(), nil
+}
+
+func (fake *FakeCloud) ListConnectionsWithContext(ctx context.Context, options *transitgatewayapisv1.ListConnectionsOptions) (*transitgatewayapisv1.TransitConnectionCollection, *core.DetailedResponse, error) {
+	var (
+		nextPage *transitgatewayapisv1.PaginationNextConnection
+	)
+
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	page, next := fakePage(fake.transitConnections, options.Start, options.Limit)
+	if next != "" {
+		nextPage = &transitgatewayapisv1.PaginationNextConnection{
+			Href:  fakeHref("connections", next),
+			Start: ptr.To(next),
+		}
+	}
+
+	return &transitgatewayapisv1.TransitConnectionCollection{
+		Connections: page,
+		First:       &transitgatewayapisv1.PaginationFirstConnection{Href: fakeHref("connections", "")},
+		Limit:       options.Limit,
+		Next:        nextPage,
+	}, fakeResponse(), nil
+}
+
+func (fake *FakeCloud) ListAllDnsRecordsWithContext(ctx context.Context, options *dnsrecordsv1.ListAllDnsRecordsOptions) (*dnsrecordsv1.ListDnsrecordsResp, *core.DetailedResponse, error) {
+	var (
+		perPage int64 = 20
+		page    int64 = 1
+		offset  int64
+		end     int64
+	)
+
+	if err := fake.lock(ctx); err != nil {
+		return nil, nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	if options.PerPage != nil {
+		perPage = *options.PerPage
+	}
+	if options.Page != nil {
+		page = *options.Page
+	}
+
+	// The DNS records are paged by page number, starting at 1.
+	offset = min((page-1)*perPage, int64(len(fake.dnsRecords)))
+	end = min(offset+perPage, int64(len(fake.dnsRecords)))
+
+	return &dnsrecordsv1.ListDnsrecordsResp{
+		Success:  ptr.To(true),
+		Errors:   [][]string{},
+		Messages: [][]string{},
+		Result:   fake.dnsRecords[offset:end],
+		ResultInfo: &dnsrecordsv1.ResultInfo{
+			Page:       ptr.To(page),
+			PerPage:    ptr.To(perPage),
+			Count:      ptr.To(end - offset),
+			TotalCount: ptr.To(int64(len(fake.dnsRecords))),
+		},
+	}, fakeResponse(), nil
+}
+
+func (fake *FakeCloud) HeadBucketWithContext(ctx aws.Context, input *s3.HeadBucketInput, opts ...request.Option) (*s3.HeadBucketOutput, error) {
+	if err := fake.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	if _, ok := fake.buckets[*input.Bucket]; !ok {
+		return nil, awserr.New("NotFound", fmt.Sprintf("bucket %s not found", *input.Bucket), nil)
+	}
+
+	return &s3.HeadBucketOutput{}, nil
+}
+
+func (fake *FakeCloud) ListObjectsWithContext(ctx aws.Context, input *s3.ListObjectsInput, opts ...request.Option) (*s3.ListObjectsOutput, error) {
+	var (
+		contents []*s3.Object
+	)
+
+	if err := fake.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer fake.mutex.Unlock()
+
+	objects, ok := fake.buckets[*input.Bucket]
+	if !ok {
+		return nil, awserr.New(s3.ErrCodeNoSuchBucket, fmt.Sprintf("bucket %s not found", *input.Bucket), nil)
+	}
+
+	contents = make([]*s3.Object, 0, len(objects))
+	for _, object := range objects {
+		contents = append(contents, &s3.Object{
+			Key:  aws.String(object.Key),
+			Size: aws.Int64(object.Size),
+		})
+	}
+
+	return &s3.ListObjectsOutput{
+		Name:     input.Bucket,
+		Contents: contents,
+	}, nil
+}
+
+// setServiceInstanceClients points the PowerVS clients of the service instance at the
+// fake backend.
+func (fake *FakeCloud) setServiceInstanceClients(ctx context.Context, si *ServiceInstance) {
+	var (
+		client = fakePowerVSClient{
+			fake: fake,
+			ctx:  ctx,
+			guid: *si.innerSi.GUID,
+		}
+	)
+
+	si.networkClient = fakePINetworkClient(client)
+	si.keyClient = fakePIKeyClient(client)
+	si.imageClient = fakePIImageClient(client)
+	si.dhcpClient = fakePIDhcpClient(client)
+	si.instanceClient = fakePIInstanceClient(client)
+	si.volumeClient = fakePIVolumeClient(client)
+	si.jobClient = fakePIJobClient(client)
+}
+
+// fakePowerVSClient is a client for one PowerVS service instance of the fake backend.
+// The PowerVS clients do not take a context, like the real ones they make all of their
+// calls with the one they were created with.
+type fakePowerVSClient struct {
+	fake *FakeCloud
+	ctx  context.Context
+	guid string
+}
+
+type fakePINetworkClient fakePowerVSClient
+type fakePIKeyClient fakePowerVSClient
+type fakePIImageClient fakePowerVSClient
+type fakePIDhcpClient fakePowerVSClient
+type fakePIInstanceClient fakePowerVSClient
+type fakePIVolumeClient fakePowerVSClient
+type fakePIJobClient fakePowerVSClient
+
+// lockPowerVS returns the resources of the service instance with the mutex held.  The
+// caller must unlock the mutex when lockPowerVS returns no error.  The call, for example
+// "PIJobClient.GetAll", fails with the next error of powerVSFailures which is left for it.
+func (fake *FakeCloud) lockPowerVS(ctx context.Context, guid string, call string) (*fakePowerVS, error) {
+	var (
+		pvs *fakePowerVS
+		ok  bool
+		err error
+	)
+
+	if err = fake.lock(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(fake.powerVSFailures[call]) > 0 {
+		err = fake.powerVSFailures[call][0]
+		fake.powerVSFailures[call] = fake.powerVSFailures[call][1:]
+		fake.mutex.Unlock()
+		return nil, err
+	}
+
+	pvs, ok = fake.powerVS[guid]
+	if !ok || pvs == nil {
+		fake.mutex.Unlock()
+		return nil, fmt.Errorf("Error: PowerVS service instance %s not found", guid)
+	}
+
+	return pvs, nil
+}
+
+// convertFakeModel copies the fields of a PowerVS model into another model with the
+// same JSON, for example a PVMInstance into a PVMInstanceReference.
+func convertFakeModel(from any, to any) error {
+	var (
+		content []byte
+		err     error
+	)
+
+	content, err = json.Marshal(from)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, to)
+}
+
+func (client fakePINetworkClient) Get(id string) (*models.Network, error) {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PINetworkClient.Get")
+	if err != nil {
+		return nil, err
+	}
+	defer client.fake.mutex.Unlock()
+
+	for _, network := range pvs.Networks {
+		if ptr.Deref(network.NetworkID, "") == id {
+			return network, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Error: network %s not found", id)
+}
+
+func (client fakePINetworkClient) GetAll() (*models.Networks, error) {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PINetworkClient.GetAll")
+	if err != nil {
+		return nil, err
+	}
+	defer client.fake.mutex.Unlock()
+
+	networks := &models.Networks{
+		Networks: make([]*models.NetworkReference, 0, len(pvs.Networks)),
+	}
+	for _, network := range pvs.Networks {
+		networkRef := new(models.NetworkReference)
+
+		err = convertFakeModel(network, networkRef)
+		if err != nil {
+			return nil, err
+		}
+		networks.Networks = append(networks.Networks, networkRef)
+	}
+
+	return networks, nil
+}
+
+func (client fakePINetworkClient) GetAllPorts(id string) (*models.NetworkPorts, error) {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PINetworkClient.GetAllPorts")
+	if err != nil {
+		return nil, err
+	}
+	defer client.fake.mutex.Unlock()
+
+	return &models.NetworkPorts{
+		Ports: pvs.NetworkPorts[id],
+	}, nil
+}
+
+func (client fakePINetworkClient) GetAllNetworkInterfaces(id string) (*models.NetworkInterfaces, error) {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PINetworkClient.GetAllNetworkInterfaces")
+	if err != nil {
+		return nil, err
+	}
+	defer client.fake.mutex.Unlock()
+
+	return &models.NetworkInterfaces{
+		Interfaces: pvs.NetworkInterfaces[id],
+	}, nil
+}
+
+func (client fakePINetworkClient) Delete(id string) error {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PINetworkClient.Delete")
+	if err != nil {
+		return err
+	}
+	defer client.fake.mutex.Unlock()
+
+	// Like PowerVS, a network which still has ports cannot be deleted.
+	if len(pvs.NetworkPorts[id]) > 0 {
+		return fmt.Errorf("Error: network %s still has %d network ports", id, len(pvs.NetworkPorts[id]))
+	}
+
+	count := len(pvs.Networks)
+	pvs.Networks = slices.DeleteFunc(pvs.Networks, func(network *models.Network) bool {
+		return ptr.Deref(network.NetworkID, "") == id
+	})
+	if len(pvs.Networks) == count {
+		return fmt.Errorf("Error: network %s not found", id)
+	}
+	delete(pvs.NetworkPorts, id)
+	delete(pvs.NetworkInterfaces, id)
+
+	return nil
+}
+
+func (client fakePINetworkClient) DeletePort(id string, networkPortID string) error {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PINetworkClient.DeletePort")
+	if err != nil {
+		return err
+	}
+	defer client.fake.mutex.Unlock()
+
+	count := len(pvs.NetworkPorts[id])
+	if count == 0 {
+		return fmt.Errorf("Error: network port %s of network %s not found", networkPortID, id)
+	}
+	pvs.NetworkPorts[id] = slices.DeleteFunc(pvs.NetworkPorts[id], func(networkPort *models.NetworkPort) bool {
+		return ptr.Deref(networkPort.PortID, "") == networkPortID
+	})
+	if len(pvs.NetworkPorts[id]) == count {
+		return fmt.Errorf("Error: network port %s of network %s not found", networkPortID, id)
+	}
+
+	return nil
+}
+
+func (client fakePIKeyClient) Get(id string) (*models.SSHKey, error) {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PIKeyClient.Get")
+	if err != nil {
+		return nil, err
+	}
+	defer client.fake.mutex.Unlock()
+
+	for _, key := range pvs.SshKeys {
+		if ptr.Deref(key.Name, "") == id {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Error: ssh key %s not found", id)
+}
+
+func (client fakePIKeyClient) GetAll() (*models.SSHKeys, error) {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PIKeyClient.GetAll")
+	if err != nil {
+		return nil, err
+	}
+	defer client.fake.mutex.Unlock()
+
+	return &models.SSHKeys{
+		SSHKeys: slices.Clone(pvs.SshKeys),
+	}, nil
+}
+
+func (client fakePIImageClient) GetAll() (*models.Images, error) {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PIImageClient.GetAll")
+	if err != nil {
+		return nil, err
+	}
+	defer client.fake.mutex.Unlock()
+
+	return &models.Images{
+		Images: slices.Clone(pvs.Images),
+	}, nil
+}
+
+func (client fakePIImageClient) GetAllStockImages(includeSAP bool, includeVTL bool) (*models.Images, error) {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PIImageClient.GetAllStockImages")
+	if err != nil {
+		return nil, err
+	}
+	defer client.fake.mutex.Unlock()
+
+	return &models.Images{
+		Images: slices.Clone(pvs.StockImages),
+	}, nil
+}
+
+func (client fakePIImageClient) Delete(id string) error {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PIImageClient.Delete")
+	if err != nil {
+		return err
+	}
+	defer client.fake.mutex.Unlock()
+
+	count := len(pvs.Images)
+	pvs.Images = slices.DeleteFunc(pvs.Images, func(image *models.ImageReference) bool {
+		return ptr.Deref(image.ImageID, "") == id
+	})
+	if len(pvs.Images) == count {
+		return fmt.Errorf("Error: image %s not found", id)
+	}
+
+	return nil
+}
+
+func (client fakePIDhcpClient) Get(id string) (*models.DHCPServerDetail, error) {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PIDhcpClient.Get")
+	if err != nil {
+		return nil, err
+	}
+	defer client.fake.mutex.Unlock()
+
+	for _, dhcpServer := range pvs.DhcpServers {
+		if ptr.Deref(dhcpServer.ID, "") == id {
+			return dhcpServer, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Error: DHCP server %s not found", id)
+}
+
+func (client fakePIDhcpClient) GetAll() (models.DHCPServers, error) {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PIDhcpClient.GetAll")
+	if err != nil {
+		return nil, err
+	}
+	defer client.fake.mutex.Unlock()
+
+	dhcpServers := make(models.DHCPServers, 0, len(pvs.DhcpServers))
+	for _, dhcpServerDetail := range pvs.DhcpServers {
+		dhcpServer := new(models.DHCPServer)
+
+		err = convertFakeModel(dhcpServerDetail, dhcpServer)
+		if err != nil {
+			return nil, err
+		}
+		dhcpServers = append(dhcpServers, dhcpServer)
+	}
+
+	return dhcpServers, nil
+}
+
+func (client fakePIDhcpClient) Delete(id string) error {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PIDhcpClient.Delete")
+	if err != nil {
+		return err
+	}
+	defer client.fake.mutex.Unlock()
+
+	count := len(pvs.DhcpServers)
+	pvs.DhcpServers = slices.DeleteFunc(pvs.DhcpServers, func(dhcpServer *models.DHCPServerDetail) bool {
+		return ptr.Deref(dhcpServer.ID, "") == id
+	})
+	if len(pvs.DhcpServers) == count {
+		return fmt.Errorf("Error: DHCP server %s not found", id)
+	}
+
+	return nil
+}
+
+func (client fakePIInstanceClient) Get(id string) (*models.PVMInstance, error) {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PIInstanceClient.Get")
+	if err != nil {
+		return nil, err
+	}
+	defer client.fake.mutex.Unlock()
+
+	for _, pvmInstance := range pvs.PvmInstances {
+		if ptr.Deref(pvmInstance.PvmInstanceID, "") == id {
+			return pvmInstance, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Error: PVM instance %s not found", id)
+}
+
+func (client fakePIInstanceClient) GetAll() (*models.PVMInstances, error) {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PIInstanceClient.GetAll")
+	if err != nil {
+		return nil, err
+	}
+	defer client.fake.mutex.Unlock()
+
+	pvmInstances := &models.PVMInstances{
+		PvmInstances: make([]*models.PVMInstanceReference, 0, len(pvs.PvmInstances)),
+	}
+	for _, pvmInstance := range pvs.PvmInstances {
+		pvmInstanceRef := new(models.PVMInstanceReference)
+
+		err = convertFakeModel(pvmInstance, pvmInstanceRef)
+		if err != nil {
+			return nil, err
+		}
+		pvmInstances.PvmInstances = append(pvmInstances.PvmInstances, pvmInstanceRef)
+	}
+
+	return pvmInstances, nil
+}
+
+func (client fakePIInstanceClient) Delete(id string) error {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PIInstanceClient.Delete")
+	if err != nil {
+		return err
+	}
+	defer client.fake.mutex.Unlock()
+
+	count := len(pvs.PvmInstances)
+	pvs.PvmInstances = slices.DeleteFunc(pvs.PvmInstances, func(pvmInstance *models.PVMInstance) bool {
+		return ptr.Deref(pvmInstance.PvmInstanceID, "") == id
+	})
+	if len(pvs.PvmInstances) == count {
+		return fmt.Errorf("Error: PVM instance %s not found", id)
+	}
+
+	// The ports and the boot volume of the instance go away with it, its data volumes
+	// are only detached.
+	for networkID, networkPorts := range pvs.NetworkPorts {
+		pvs.NetworkPorts[networkID] = slices.DeleteFunc(networkPorts, func(networkPort *models.NetworkPort) bool {
+			return networkPort.PvmInstance != nil && networkPort.PvmInstance.PvmInstanceID == id
+		})
+	}
+	pvs.Volumes = slices.DeleteFunc(pvs.Volumes, func(volume *models.VolumeReference) bool {
+		return ptr.Deref(volume.BootVolume, false) && slices.Equal(volume.PvmInstanceIDs, []string{id})
+	})
+	for _, volume := range pvs.Volumes {
+		volume.PvmInstanceIDs = slices.DeleteFunc(volume.PvmInstanceIDs, func(pvmInstanceID string) bool {
+			return pvmInstanceID == id
+		})
+	}
+
+	return nil
+}
+
+func (client fakePIVolumeClient) GetAll() (*models.Volumes, error) {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PIVolumeClient.GetAll")
+	if err != nil {
+		return nil, err
+	}
+	defer client.fake.mutex.Unlock()
+
+	return &models.Volumes{
+		Volumes: slices.Clone(pvs.Volumes),
+	}, nil
+}
+
+func (client fakePIVolumeClient) GetAllInstanceVolumes(id string) (*models.Volumes, error) {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PIVolumeClient.GetAllInstanceVolumes")
+	if err != nil {
+		return nil, err
+	}
+	defer client.fake.mutex.Unlock()
+
+	volumes := &models.Volumes{
+		Volumes: make([]*models.VolumeReference, 0),
+	}
+	for _, volume := range pvs.Volumes {
+		if slices.Contains(volume.PvmInstanceIDs, id) {
+			volumes.Volumes = append(volumes.Volumes, volume)
+		}
+	}
+
+	return volumes, nil
+}
+
+func (client fakePIVolumeClient) DeleteVolume(id string) error {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PIVolumeClient.DeleteVolume")
+	if err != nil {
+		return err
+	}
+	defer client.fake.mutex.Unlock()
+
+	for _, volume := range pvs.Volumes {
+		if ptr.Deref(volume.VolumeID, "") == id && len(volume.PvmInstanceIDs) > 0 {
+			return fmt.Errorf("Error: volume %s is attached to %v", id, volume.PvmInstanceIDs)
+		}
+	}
+
+	count := len(pvs.Volumes)
+	pvs.Volumes = slices.DeleteFunc(pvs.Volumes, func(volume *models.VolumeReference) bool {
+		return ptr.Deref(volume.VolumeID, "") == id
+	})
+	if len(pvs.Volumes) == count {
+		return fmt.Errorf("Error: volume %s not found", id)
+	}
+
+	return nil
+}
+
+func (client fakePIJobClient) Get(id string) (*models.Job, error) {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PIJobClient.Get")
+	if err != nil {
+		return nil, err
+	}
+	defer client.fake.mutex.Unlock()
+
+	for _, job := range pvs.Jobs {
+		if ptr.Deref(job.ID, "") == id {
+			return job, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Error: job %s not found", id)
+}
+
+func (client fakePIJobClient) GetAll() (*models.Jobs, error) {
+	pvs, err := client.fake.lockPowerVS(client.ctx, client.guid, "PIJobClient.GetAll")
+	if err != nil {
+		return nil, err
+	}
+	defer client.fake.mutex.Unlock()
+
+	return &models.Jobs{
+		Jobs: slices.Clone(pvs.Jobs),
+	}, nil
+}
+
+// fakePIDatacentersClient lists the PowerVS datacenters of the fake backend.
+type fakePIDatacentersClient struct {
+	fake *FakeCloud
+}
+
+// DatacentersClient returns a client for the PowerVS datacenters of the fake backend.
+func (fake *FakeCloud) DatacentersClient() PIDatacentersClient {
+	return fakePIDatacentersClient{fake: fake}
+}
+
+func (client fakePIDatacentersClient) GetAll() (*models.Datacenters, error) {
+	client.fake.mutex.Lock()
+	defer client.fake.mutex.Unlock()
+
+	return &models.Datacenters{
+		Datacenters: slices.Clone(client.fake.datacenters),
+	}, nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	// The fixtures which the tests run the checks against, see testdata/fixtures.json.
+	testFixtures = "testdata/fixtures.json"
+)
+
+// newTestClusterServices returns the services of a cluster of the test fixtures, as if
+// its metadata.json was read.
+func newTestClusterServices(t *testing.T, clusterName string, guid string, vpcName string) *Services {
+	var (
+		content  string
+		metadata *Metadata
+		services *Services
+		err      error
+	)
+
+	t.Helper()
+
+	content = fmt.Sprintf(`{"clusterName": "%s", "clusterID": "%s-id", "infraID": "%s-abc", "powervs": {"BaseDomain": "example.com", "powerVSResourceGroup": "rg-test", "region": "dal", "vpcRegion": "us-south", "zone": "dal10", "serviceInstanceGUID": "%s", "vpcName": "%s"}}`,
+		clusterName, clusterName, clusterName, guid, vpcName)
+
+	metadata, err = newMetadataFromCCContent([]byte(content))
+	if err != nil {
+		t.Fatalf("newMetadataFromCCContent: %v", err)
+	}
+
+	services, err = NewFakeServices(context.Background(), metadata, testFixtures)
+	if err != nil {
+		t.Fatalf("NewFakeServices: %v", err)
+	}
+
+	return services
+}
+
+// newTestCIServices returns the services of a CI workspace of the test fixtures, as if
+// its CI metadata file was read.
+func newTestCIServices(t *testing.T, serviceInstance string, vpcName string, tgName string) *Services {
+	var (
+		content  string
+		metadata *Metadata
+		services *Services
+		err      error
+	)
+
+	t.Helper()
+
+	content = fmt.Sprintf(`{"region": "dal", "vpcRegion": "us-south", "zone": "dal10", "resourceGroup": "rg-test", "serviceInstance": "%s", "vpc": "%s", "transitGateway": "%s"}`,
+		serviceInstance, vpcName, tgName)
+
+	metadata, err = newMetadataFromCIContent([]byte(content))
+	if err != nil {
+		t.Fatalf("newMetadataFromCIContent: %v", err)
+	}
+
+	services, err = NewFakeServices(context.Background(), metadata, testFixtures)
+	if err != nil {
+		t.Fatalf("NewFakeServices: %v", err)
+	}
+
+	return services
+}
+
+// hasCheck returns true if the result has a check with the id and the status.
+func hasCheck(result *ObjectResult, id string, status CheckStatus) bool {
+	for _, check := range result.Checks {
+		if check.ID == id && check.Status == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dumpChecks returns the checks of a result, one per line, for the failure messages.
+func dumpChecks(result *ObjectResult) string {
+	var (
+		dump string
+	)
+
+	for _, check := range result.Checks {
+		dump += fmt.Sprintf("\n\t%s %s: %s", check.Status, check.ID, check.Message)
+	}
+
+	return dump
+}
+
+func TestNewFakeCloud(t *testing.T) {
+	var (
+		dir = t.TempDir()
+	)
+
+	for _, file := range []struct {
+		name    string
+		content string
+	}{
+		{"notjson.json", "not json"},
+		{"badvpcs.json", `{"vpcs": {"id": "not a list"}}`},
+	} {
+		err := os.WriteFile(filepath.Join(dir, file.name), []byte(file.content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		fixtures string
+		wantErr  bool
+	}{
+		{"seeded fixtures", testFixtures, false},
+		{"missing file", filepath.Join(dir, "missing.json"), true},
+		{"not json", filepath.Join(dir, "notjson.json"), true},
+		{"bad vpcs", filepath.Join(dir, "badvpcs.json"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, err := NewFakeCloud(tt.fixtures)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NewFakeCloud(%s) did not return an error", tt.fixtures)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewFakeCloud(%s) returned %v", tt.fixtures, err)
+			}
+			if fake.account != "acct-test" {
+				t.Errorf("account = %s, want acct-test", fake.account)
+			}
+		})
+	}
+}
+
+// failPowerVS makes the next calls of a PowerVS client, for example
+// "PIJobClient.GetAll", return the errors.
+func (fake *FakeCloud) failPowerVS(call string, errs ...error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	fake.powerVSFailures[call] = append(fake.powerVSFailures[call], errs...)
+}
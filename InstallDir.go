@@ -0,0 +1,292 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// The files which openshift-install leaves in its installation directory.
+	installMetadataFile   = "metadata.json"
+	installConfigFile     = "install-config.yaml"
+	capiOutputDirectory   = ".clusterapi_output"
+	capiKubeconfigFile    = "envtest.kubeconfig"
+	clusterKubeconfigFile = "auth/kubeconfig"
+)
+
+// InstallConfig is the part of install-config.yaml which is used.
+// https://github.com/openshift/installer/blob/main/pkg/types/installconfig.go
+type InstallConfig struct {
+	BaseDomain string `json:"baseDomain"`
+
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+
+	ControlPlane *InstallConfigMachinePool  `json:"controlPlane,omitempty"`
+	Compute      []InstallConfigMachinePool `json:"compute,omitempty"`
+
+	// External or Internal.
+	Publish string `json:"publish,omitempty"`
+
+	Platform struct {
+		PowerVS *InstallConfigPowerVS `json:"powervs,omitempty"`
+	} `json:"platform"`
+}
+
+type InstallConfigMachinePool struct {
+	Name     string `json:"name"`
+	Replicas *int64 `json:"replicas,omitempty"`
+}
+
+// https://github.com/openshift/installer/blob/main/pkg/types/powervs/platform.go
+type InstallConfigPowerVS struct {
+	Region               string                            `json:"region"`
+	Zone                 string                            `json:"zone"`
+	VPCRegion            string                            `json:"vpcRegion,omitempty"`
+	PowerVSResourceGroup string                            `json:"powervsResourceGroup"`
+	ServiceInstanceGUID  string                            `json:"serviceInstanceGUID,omitempty"`
+	VPCName              string                            `json:"vpcName,omitempty"`
+	ServiceEndpoints     []configv1.PowerVSServiceEndpoint `json:"serviceEndpoints,omitempty"`
+}
+
+// capiPowerVSCluster is the part of the IBMPowerVSCluster which the installer saves into
+// .clusterapi_output which is used.
+// https://github.com/kubernetes-sigs/cluster-api-provider-ibmcloud/blob/main/api/v1beta2/ibmpowervscluster_types.go
+type capiPowerVSCluster struct {
+	Kind string `json:"kind"`
+
+	Spec struct {
+		Zone              string `json:"zone"`
+		ServiceInstanceID string `json:"serviceInstanceID"`
+		ResourceGroup     struct {
+			Name string `json:"name"`
+		} `json:"resourceGroup"`
+		ServiceInstance struct {
+			ID string `json:"id"`
+		} `json:"serviceInstance"`
+		VPC struct {
+			Name   string `json:"name"`
+			Region string `json:"region"`
+		} `json:"vpc"`
+		TransitGateway struct {
+			Name string `json:"name"`
+		} `json:"transitGateway"`
+	} `json:"spec"`
+
+	Status struct {
+		ServiceInstance struct {
+			ID string `json:"id"`
+		} `json:"serviceInstance"`
+	} `json:"status"`
+}
+
+// NewMetadataFromInstallDir reads the metadata.json of an installation directory and fills
+// in what it does not say from install-config.yaml and from the IBMPowerVSCluster in
+// .clusterapi_output, if they are there.  install-config.yaml is consumed by the installer,
+// so it is only found when a copy was put back.
+func NewMetadataFromInstallDir(installDir string) (*Metadata, error) {
+	var (
+		metadata *Metadata
+		err      error
+	)
+
+	metadata, err = readInstallDir(installDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return validatedMetadata(metadata)
+}
+
+// readInstallDir is NewMetadataFromInstallDir without the validation, which has to wait
+// until everything has been merged.
+func readInstallDir(installDir string) (*Metadata, error) {
+	var (
+		metadata      *Metadata
+		installConfig *InstallConfig
+		capiCluster   *capiPowerVSCluster
+		err           error
+	)
+
+	metadata, err = readCCMetadata(filepath.Join(installDir, installMetadataFile))
+	if err != nil {
+		return nil, err
+	}
+	metadata.location = installDir
+	metadata.installDir = installDir
+
+	installConfig, err = readInstallConfig(filepath.Join(installDir, installConfigFile))
+	if err != nil {
+		return nil, err
+	}
+	if installConfig != nil {
+		metadata.mergeInstallConfig(installConfig)
+	}
+
+	capiCluster, err = readCAPIPowerVSCluster(filepath.Join(installDir, capiOutputDirectory))
+	if err != nil {
+		return nil, err
+	}
+	if capiCluster != nil {
+		metadata.mergeCAPIPowerVSCluster(capiCluster)
+	}
+
+	log.Debugf("readInstallDir: metadata.createMetadata.PowerVS = %+v", metadata.createMetadata.PowerVS)
+
+	return metadata, nil
+}
+
+// readInstallConfig returns nil, without an error, when the file does not exist.
+func readInstallConfig(filename string) (*InstallConfig, error) {
+	var (
+		content       []byte
+		installConfig InstallConfig
+		err           error
+	)
+
+	content, err = os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		log.Debugf("readInstallConfig: %s not found", filename)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = yaml.Unmarshal(content, &installConfig)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not parse %s (%v)", filename, err)
+	}
+
+	return &installConfig, nil
+}
+
+// readCAPIPowerVSCluster returns nil, without an error, when there is no IBMPowerVSCluster
+// in the directory.
+func readCAPIPowerVSCluster(directory string) (*capiPowerVSCluster, error) {
+	var (
+		fileNames []string
+		content   []byte
+		cluster   capiPowerVSCluster
+		err       error
+	)
+
+	// The installer names the files <kind>-<namespace>-<name>.yaml.
+	fileNames, err = filepath.Glob(filepath.Join(directory, "IBMPowerVSCluster-*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(fileNames) == 0 {
+		log.Debugf("readCAPIPowerVSCluster: no IBMPowerVSCluster in %s", directory)
+		return nil, nil
+	}
+
+	content, err = os.ReadFile(fileNames[0])
+	if err != nil {
+		return nil, err
+	}
+
+	err = yaml.Unmarshal(content, &cluster)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not parse %s (%v)", fileNames[0], err)
+	}
+	if cluster.Kind != "IBMPowerVSCluster" {
+		return nil, fmt.Errorf("Error: %s is a %s, not an IBMPowerVSCluster", fileNames[0], cluster.Kind)
+	}
+
+	return &cluster, nil
+}
+
+// fillIn sets the field if it is empty.
+func fillIn(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
+
+func (m *Metadata) mergeInstallConfig(installConfig *InstallConfig) {
+	var (
+		powerVS = m.createMetadata.PowerVS
+	)
+
+	fillIn(&m.createMetadata.ClusterName, installConfig.Metadata.Name)
+	fillIn(&powerVS.BaseDomain, installConfig.BaseDomain)
+
+	if installConfig.Platform.PowerVS != nil {
+		fillIn(&powerVS.Region, installConfig.Platform.PowerVS.Region)
+		fillIn(&powerVS.Zone, installConfig.Platform.PowerVS.Zone)
+		fillIn(&powerVS.VPCRegion, installConfig.Platform.PowerVS.VPCRegion)
+		fillIn(&powerVS.PowerVSResourceGroup, installConfig.Platform.PowerVS.PowerVSResourceGroup)
+		fillIn(&powerVS.ServiceInstanceGUID, installConfig.Platform.PowerVS.ServiceInstanceGUID)
+		fillIn(&powerVS.VPC, installConfig.Platform.PowerVS.VPCName)
+
+		for _, serviceEndpoint := range installConfig.Platform.PowerVS.ServiceEndpoints {
+			if m.GetServiceEndpoint(serviceEndpoint.Name, "") == "" {
+				m.setServiceEndpoint(serviceEndpoint.Name, serviceEndpoint.URL)
+			}
+		}
+	}
+
+	m.installConfig = installConfig
+}
+
+func (m *Metadata) mergeCAPIPowerVSCluster(cluster *capiPowerVSCluster) {
+	var (
+		powerVS = m.createMetadata.PowerVS
+	)
+
+	fillIn(&powerVS.Zone, cluster.Spec.Zone)
+	fillIn(&powerVS.PowerVSResourceGroup, cluster.Spec.ResourceGroup.Name)
+	fillIn(&powerVS.ServiceInstanceGUID, cluster.Status.ServiceInstance.ID)
+	fillIn(&powerVS.ServiceInstanceGUID, cluster.Spec.ServiceInstance.ID)
+	fillIn(&powerVS.ServiceInstanceGUID, cluster.Spec.ServiceInstanceID)
+	fillIn(&powerVS.VPC, cluster.Spec.VPC.Name)
+	fillIn(&powerVS.VPCRegion, cluster.Spec.VPC.Region)
+	fillIn(&powerVS.TransitGateway, cluster.Spec.TransitGateway.Name)
+}
+
+// installDirKubeconfig returns the kubeconfig of the cluster in an installation directory,
+// or of its CAPI control plane when capi is true.
+func installDirKubeconfig(installDir string, capi bool) string {
+	if capi {
+		return filepath.Join(installDir, capiOutputDirectory, capiKubeconfigFile)
+	}
+
+	return filepath.Join(installDir, filepath.FromSlash(clusterKubeconfigFile))
+}
+
+// resolveKubeconfig returns the -kubeconfig flag, or the kubeconfig in the -installDir.
+func resolveKubeconfig(kubeconfig string, installDir string, capi bool) (string, error) {
+	switch {
+	case kubeconfig != "" && installDir != "":
+		return "", fmt.Errorf("Error: Use either -kubeconfig or -installDir, not both")
+	case kubeconfig != "":
+		return kubeconfig, nil
+	case installDir != "":
+		return installDirKubeconfig(installDir, capi), nil
+	}
+
+	return "", fmt.Errorf("Error: No KUBECONFIG key set, use -kubeconfig or -installDir")
+}
@@ -36,14 +36,25 @@ const (
 	lbObjectName = "Load Balancer"
 )
 
-func NewLoadBalancer(services *Services) ([]RunnableObject, []error) {
+func init() {
+	RegisterRunnableObject(RunnableObjectRegistration{
+		Name:         lbObjectName,
+		Key:          "lb",
+		New:          NewLoadBalancer,
+		Modes:        []RunMode{RunModeCreate},
+		Dependencies: []string{vpcObjectName},
+		Order:        40,
+	})
+}
+
+func NewLoadBalancer(ctx context.Context, services *Services) ([]RunnableObject, []error) {
 	var (
 		lbs      []*LoadBalancer
 		errs     []error
 		ros      []RunnableObject
 	)
 
-	lbs, errs = innerNewLoadBalancer(services)
+	lbs, errs = innerNewLoadBalancer(ctx, services)
 
 	ros = make([]RunnableObject, len(lbs))
 	// Go does not support type converting the entire array.
@@ -55,15 +66,14 @@ func NewLoadBalancer(services *Services) ([]RunnableObject, []error) {
 	return ros, errs
 }
 
-func NewLoadBalancerAlt(services *Services) ([]*LoadBalancer, []error) {
-	return innerNewLoadBalancer(services)
+func NewLoadBalancerAlt(ctx context.Context, services *Services) ([]*LoadBalancer, []error) {
+	return innerNewLoadBalancer(ctx, services)
 }
 
-func innerNewLoadBalancer(services *Services) ([]*LoadBalancer, []error) {
+func innerNewLoadBalancer(ctx context.Context, services *Services) ([]*LoadBalancer, []error) {
 	var (
 		lbName   string
-		vpcSvc   *vpcv1.VpcV1
-		ctx      context.Context
+		vpcSvc   VpcClient
 		cancel   context.CancelFunc
 		lbIds    []string
 		lbs      []*LoadBalancer
@@ -83,11 +93,11 @@ func innerNewLoadBalancer(services *Services) ([]*LoadBalancer, []error) {
 
 	vpcSvc = services.GetVpcSvc()
 
-	ctx, cancel = services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	if fUseTagSearch {
-		lbIds, err = listByTag(TagTypeLoadBalancer, services)
+		lbIds, err = listByTag(ctx, TagTypeLoadBalancer, services)
 	} else {
 		lbIds, err = listLoadBalancersByName(vpcSvc, ctx, lbName)
 	}
@@ -127,20 +137,20 @@ func innerNewLoadBalancer(services *Services) ([]*LoadBalancer, []error) {
 
 		log.Debugf("NewLoadBalancer: lbId = %s", lbId)
 
-		options = vpcSvc.NewGetLoadBalancerOptions(lbId)
+		options = &vpcv1.GetLoadBalancerOptions{ID: &lbId}
 
 		innerLb, response, err = vpcSvc.GetLoadBalancerWithContext(ctx, options)
 		if err != nil {
-			log.Fatalf("NewLoadBalancer could not GetLoadBalancerWithContext(%s): %s", lbId, response)
+			log.Debugf("NewLoadBalancer could not GetLoadBalancerWithContext(%s): %s", lbId, response)
 			continue
 		} else if innerLb == nil {
-			log.Fatalf("NewLoadBalancer nil return from GetLoadBalancerWithContext(%s)", lbId)
+			log.Debugf("NewLoadBalancer nil return from GetLoadBalancerWithContext(%s)", lbId)
 			continue
 		}
 
 		switch GetLoadBalancerType(*innerLb.Name) {
 		case LoadBalancerTypeUnknown:
-			log.Fatalf("NewLoadBalancer could not GetLoadBalancerWithContext(%s): %s", lbId, response)
+			log.Debugf("NewLoadBalancer could not GetLoadBalancerWithContext(%s): %s", lbId, response)
 		case LoadBalancerTypeInternal:
 			lbs[0].name = *innerLb.Name
 			lbs[0].innerLb = innerLb
@@ -156,6 +166,12 @@ func innerNewLoadBalancer(services *Services) ([]*LoadBalancer, []error) {
 		}
 	}
 
+	// A private cluster has no external load balancer, so do not report it missing.
+	if !services.GetMetadata().GetExpectations().ExternalLoadBalancer && lbs[1].innerLb == nil {
+		lbs = append(lbs[:1], lbs[2:]...)
+		errs = append(errs[:1], errs[2:]...)
+	}
+
 	return lbs, errs
 }
 
@@ -181,7 +197,7 @@ func GetLoadBalancerType(name string) LoadBalancerType {
 }
 
 // listLoadBalancersByName list the load balancers matching by name in the IBM Cloud.
-func listLoadBalancersByName(vpcSvc *vpcv1.VpcV1, ctx context.Context, infraID string) ([]string, error) {
+func listLoadBalancersByName(vpcSvc VpcClient, ctx context.Context, infraID string) ([]string, error) {
 	var (
 		options      *vpcv1.ListLoadBalancersOptions
 		lbCollection *vpcv1.LoadBalancerCollection
@@ -201,7 +217,7 @@ func listLoadBalancersByName(vpcSvc *vpcv1.VpcV1, ctx context.Context, infraID s
 	default:
 	}
 
-	options = vpcSvc.NewListLoadBalancersOptions()
+	options = &vpcv1.ListLoadBalancersOptions{}
 	// @WHY options.SetResourceGroupID(resourceGroupID)
 
 	lbCollection, response, err = vpcSvc.ListLoadBalancersWithContext(ctx, options)
@@ -241,18 +257,17 @@ func listLoadBalancersByName(vpcSvc *vpcv1.VpcV1, ctx context.Context, infraID s
 	return result, nil
 }
 
-func (lb *LoadBalancer) listLoadBalancerPools() ([]*vpcv1.LoadBalancerPool, error) {
+func (lb *LoadBalancer) listLoadBalancerPools(ctx context.Context) ([]*vpcv1.LoadBalancerPool, error) {
 	var (
-		ctx            context.Context
 		cancel         context.CancelFunc
-		vpcSvc         *vpcv1.VpcV1
+		vpcSvc         VpcClient
 		lbPoolsOptions *vpcv1.ListLoadBalancerPoolsOptions
 		lbpc           *vpcv1.LoadBalancerPoolCollection
 		result         []*vpcv1.LoadBalancerPool
 		err            error
 	)
 
-	ctx, cancel = lb.services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	select {
@@ -264,12 +279,11 @@ func (lb *LoadBalancer) listLoadBalancerPools() ([]*vpcv1.LoadBalancerPool, erro
 
 	vpcSvc = lb.services.GetVpcSvc()
 
-	lbPoolsOptions = vpcSvc.NewListLoadBalancerPoolsOptions(*lb.innerLb.ID)
+	lbPoolsOptions = &vpcv1.ListLoadBalancerPoolsOptions{LoadBalancerID: lb.innerLb.ID}
 
 	lbpc, _, err = vpcSvc.ListLoadBalancerPoolsWithContext(ctx, lbPoolsOptions)
 	if err != nil {
-		fmt.Printf("%s %s could not get pools: %v\n", lbObjectName, lb.name, err)
-		return nil, err
+		return nil, fmt.Errorf("could not get pools: %w", err)
 	}
 
 	result = make([]*vpcv1.LoadBalancerPool, 0)
@@ -289,18 +303,17 @@ func (lb *LoadBalancer) listLoadBalancerPools() ([]*vpcv1.LoadBalancerPool, erro
 	return result, nil
 }
 
-func (lb *LoadBalancer) listLoadBalancerPoolMembers(id string) ([]*vpcv1.LoadBalancerPoolMember, error) {
+func (lb *LoadBalancer) listLoadBalancerPoolMembers(ctx context.Context, id string) ([]*vpcv1.LoadBalancerPoolMember, error) {
 	var (
-		ctx         context.Context
 		cancel      context.CancelFunc
-		vpcSvc      *vpcv1.VpcV1
+		vpcSvc      VpcClient
 		llpmOptions *vpcv1.ListLoadBalancerPoolMembersOptions
 		lbpmc       *vpcv1.LoadBalancerPoolMemberCollection
 		result      []*vpcv1.LoadBalancerPoolMember
 		err         error
 	)
 
-	ctx, cancel = lb.services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	select {
@@ -312,12 +325,11 @@ func (lb *LoadBalancer) listLoadBalancerPoolMembers(id string) ([]*vpcv1.LoadBal
 
 	vpcSvc = lb.services.GetVpcSvc()
 
-	llpmOptions = vpcSvc.NewListLoadBalancerPoolMembersOptions(*lb.innerLb.ID, id)
+	llpmOptions = &vpcv1.ListLoadBalancerPoolMembersOptions{LoadBalancerID: lb.innerLb.ID, PoolID: &id}
 
 	lbpmc, _, err = vpcSvc.ListLoadBalancerPoolMembersWithContext(ctx, llpmOptions)
 	if err != nil {
-		fmt.Printf("%s %s could not find pool members for %s: %v\n", lbObjectName, lb.name, id, err)
-		return nil, err
+		return nil, fmt.Errorf("could not find pool members for %s: %w", id, err)
 	}
 
 	result = make([]*vpcv1.LoadBalancerPoolMember, 0)
@@ -337,11 +349,10 @@ func (lb *LoadBalancer) listLoadBalancerPoolMembers(id string) ([]*vpcv1.LoadBal
 	return result, nil
 }
 
-func (lb *LoadBalancer) CheckLoadBalancerPool(poolNames []string, poolUserName string) bool {
+func (lb *LoadBalancer) CheckLoadBalancerPool(ctx context.Context, result *ObjectResult, poolNames []string, poolUserName string) bool {
 	var (
-		ctx           context.Context
 		cancel        context.CancelFunc
-		vpcSvc        *vpcv1.VpcV1
+		vpcSvc        VpcClient
 		lbps          []*vpcv1.LoadBalancerPool
 		lbpGetOptions *vpcv1.GetLoadBalancerPoolOptions
 		lbp           *vpcv1.LoadBalancerPool
@@ -350,10 +361,11 @@ func (lb *LoadBalancer) CheckLoadBalancerPool(poolNames []string, poolUserName s
 	)
 
 	if lb.innerLb == nil {
+		result.AddError("lb.pool", "Could not find a LB named %s", lb.name)
 		return false
 	}
 
-	ctx, cancel = lb.services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	select {
@@ -365,8 +377,10 @@ func (lb *LoadBalancer) CheckLoadBalancerPool(poolNames []string, poolUserName s
 
 	vpcSvc = lb.services.GetVpcSvc()
 
-	lbps, err = lb.listLoadBalancerPools()
+	lbps, err = lb.listLoadBalancerPools(ctx)
 	if err != nil {
+		result.AddError("lb.pool", "%v", err).
+			WithEvidence("pool", poolUserName)
 		return false
 	}
 	log.Debugf("CheckLoadBalancerPool: lbps = %+v", lbps)
@@ -389,12 +403,13 @@ func (lb *LoadBalancer) CheckLoadBalancerPool(poolNames []string, poolUserName s
 			continue
 		}
 
-		lbpGetOptions = vpcSvc.NewGetLoadBalancerPoolOptions(*lb.innerLb.ID, *lbpElm.ID)
+		lbpGetOptions = &vpcv1.GetLoadBalancerPoolOptions{LoadBalancerID: lb.innerLb.ID, ID: lbpElm.ID}
 		log.Debugf("CheckLoadBalancerPool: lbpGetOptions = %s %s", *lbpGetOptions.LoadBalancerID, *lbpGetOptions.ID)
 
 		lbp, _, err = vpcSvc.GetLoadBalancerPoolWithContext(ctx, lbpGetOptions)
 		if err != nil {
-			fmt.Printf("%s %s could not get load balancer pool: %v\n", lbObjectName, lb.name, err)
+			result.AddError("lb.pool", "could not get load balancer pool: %v", err).
+				WithEvidence("pool", poolUserName)
 			return false
 		}
 		log.Debugf("CheckLoadBalancerPool: lbp = %+v", lbp)
@@ -402,12 +417,15 @@ func (lb *LoadBalancer) CheckLoadBalancerPool(poolNames []string, poolUserName s
 	}
 
 	if lbp == nil {
-		fmt.Printf("%s %s could not find pool %s.\n", lbObjectName, lb.name, poolUserName)
+		result.AddNotOK("lb.pool", "could not find pool %s.", poolUserName).
+			WithEvidence("pool", poolUserName)
 		return false
 	}
 
-	lbpms, err = lb.listLoadBalancerPoolMembers(*lbp.ID)
+	lbpms, err = lb.listLoadBalancerPoolMembers(ctx, *lbp.ID)
 	if err != nil {
+		result.AddError("lb.pool", "%v", err).
+			WithEvidence("pool", poolUserName)
 		return false
 	}
 
@@ -427,10 +445,13 @@ func (lb *LoadBalancer) CheckLoadBalancerPool(poolNames []string, poolUserName s
 		}
 	}
 	if okHealthCount == 0 {
-		fmt.Printf("%s %s did not find a healthy member of pool %s.\n", lbObjectName, lb.name, poolUserName)
+		result.AddNotOK("lb.pool", "did not find a healthy member of pool %s.", poolUserName).
+			WithEvidence("pool", poolUserName)
 		return false
 	} else {
-		fmt.Printf("%s %s found %d healthy members of pool %s.\n", lbObjectName, lb.name, okHealthCount, poolUserName)
+		result.AddOK("lb.pool", "found %d healthy members of pool %s.", okHealthCount, poolUserName).
+			WithEvidence("pool", poolUserName).
+			WithEvidence("healthyMembers", fmt.Sprintf("%d", okHealthCount))
 		return true
 	}
 }
@@ -455,7 +476,7 @@ func (lb *LoadBalancer) ObjectName() (string, error) {
 	return lbObjectName, nil
 }
 
-func (lb *LoadBalancer) Run() error {
+func (lb *LoadBalancer) Run(ctx context.Context) error {
 	if lb.innerLb != nil {
 		lb.name = *lb.innerLb.Name
 	}
@@ -463,55 +484,54 @@ func (lb *LoadBalancer) Run() error {
 	return nil
 }
 
-func (lb *LoadBalancer) CiStatus(shouldClean bool) {
+func (lb *LoadBalancer) CiStatus(ctx context.Context, shouldClean bool) *ObjectResult {
+	return NewObjectResult(lb, lb.name)
 }
 
-func (lb *LoadBalancer) ClusterStatus() {
+func (lb *LoadBalancer) ClusterStatus(ctx context.Context) *ObjectResult {
+	var (
+		result *ObjectResult
+	)
+
+	result = NewObjectResult(lb, lb.name)
+
 	if lb.innerLb == nil {
-		fmt.Printf("%s is NOTOK. Could not find a LB named %s\n", lbObjectName, lb.name)
-		return
+		result.AddError("lb.exists", "Could not find a LB named %s", lb.name)
+		return result
 	}
 
 	if *lb.innerLb.OperatingStatus != "online" {
-		fmt.Printf("%s %s is NOTOK. The status is %s\n", lbObjectName, lb.name, *lb.innerLb.OperatingStatus)
-		return
+		result.AddNotOK("lb.status", "The status is %s", *lb.innerLb.OperatingStatus).
+			WithEvidence("operatingStatus", *lb.innerLb.OperatingStatus)
+		return result
 	}
 
 	switch GetLoadBalancerType(*lb.innerLb.Name) {
 	case LoadBalancerTypeUnknown:
 	case LoadBalancerTypeInternal:
 		// Internal Load Balancer
-		if !lb.CheckLoadBalancerPool([]string{"pool-6443"}, "port 6443") {
-			fmt.Printf("%s %s is NOTOK.\n", lbObjectName, lb.name)
-			return
+		if !lb.CheckLoadBalancerPool(ctx, result, []string{"pool-6443"}, "port 6443") {
+			return result
 		}
 
-		if !lb.CheckLoadBalancerPool([]string{"machine-config-server", "additional-pool-22623"}, "machine config server") {
-			fmt.Printf("%s %s is NOTOK.\n", lbObjectName, lb.name)
-			return
+		if !lb.CheckLoadBalancerPool(ctx, result, []string{"machine-config-server", "additional-pool-22623"}, "machine config server") {
+			return result
 		}
 	case LoadBalancerTypeExternal:
 		// External Load Balancer
-		if !lb.CheckLoadBalancerPool([]string{"pool-6443"}, "port 6443") {
-			fmt.Printf("%s %s is NOTOK.\n", lbObjectName, lb.name)
-			return
+		if !lb.CheckLoadBalancerPool(ctx, result, []string{"pool-6443"}, "port 6443") {
+			return result
 		}
 	case LoadBalancerTypeKube:
 		// The Kube pool
-		if !lb.CheckLoadBalancerPool([]string{"tcp-80"}, "port 80") {
-			fmt.Printf("%s %s is NOTOK.\n", lbObjectName, lb.name)
-			return
+		if !lb.CheckLoadBalancerPool(ctx, result, []string{"tcp-80"}, "port 80") {
+			return result
 		}
 
-		if !lb.CheckLoadBalancerPool([]string{"tcp-443"}, "port 443") {
-			fmt.Printf("%s %s is NOTOK.\n", lbObjectName, lb.name)
-			return
+		if !lb.CheckLoadBalancerPool(ctx, result, []string{"tcp-443"}, "port 443") {
+			return result
 		}
 	}
 
-	fmt.Printf("%s %s is OK.\n", lbObjectName, lb.name)
-}
-
-func (lb *LoadBalancer) Priority() (int, error) {
-	return 70, nil
+	return result
 }
@@ -0,0 +1,90 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLoadBalancerClusterStatus(t *testing.T) {
+	type lbWant struct {
+		status CheckStatus
+		checks []string
+	}
+
+	tests := []struct {
+		name        string
+		clusterName string
+		// In the order of NewLoadBalancer: internal, external and kube.
+		want []lbWant
+	}{
+		{"healthy LBs", "good", []lbWant{
+			{CheckStatusOK, []string{"lb.pool"}},
+			{CheckStatusOK, []string{"lb.pool"}},
+			{CheckStatusOK, []string{"lb.pool"}},
+		}},
+		{"broken LBs", "bad", []lbWant{
+			{CheckStatusNotOK, []string{"lb.status"}},
+			{CheckStatusNotOK, []string{"lb.pool"}},
+			{CheckStatusError, []string{"lb.exists"}},
+		}},
+		{"LBs not found", "lost", []lbWant{
+			{CheckStatusError, []string{"lb.exists"}},
+			{CheckStatusError, []string{"lb.exists"}},
+			{CheckStatusError, []string{"lb.exists"}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestClusterServices(t, tt.clusterName, "g-good", "good-vpc")
+
+			lbs, _ := NewLoadBalancerAlt(context.Background(), services)
+			if len(lbs) != len(tt.want) {
+				t.Fatalf("NewLoadBalancerAlt returned %d LBs, want %d", len(lbs), len(tt.want))
+			}
+
+			for i, lb := range lbs {
+				result := lb.ClusterStatus(context.Background())
+				if result.Status != tt.want[i].status {
+					t.Errorf("%s: ClusterStatus().Status = %s, want %s%s", lb.name, result.Status, tt.want[i].status, dumpChecks(result))
+				}
+				for _, id := range tt.want[i].checks {
+					if !hasCheck(result, id, tt.want[i].status) {
+						t.Errorf("%s: ClusterStatus() has no %s check %s%s", lb.name, tt.want[i].status, id, dumpChecks(result))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLoadBalancerCiStatus(t *testing.T) {
+	services := newTestClusterServices(t, "bad", "g-good", "good-vpc")
+
+	lbs, _ := NewLoadBalancerAlt(context.Background(), services)
+	if len(lbs) != 3 {
+		t.Fatalf("NewLoadBalancerAlt returned %d LBs, want 3", len(lbs))
+	}
+
+	// check-ci does not look at the LBs, so even broken or missing ones report nothing.
+	for _, lb := range lbs {
+		result := lb.CiStatus(context.Background(), false)
+		if result.Status != CheckStatusOK || len(result.Checks) != 0 {
+			t.Errorf("%s: CiStatus() = %s with %d checks, want %s with none%s", lb.name, result.Status, len(result.Checks), CheckStatusOK, dumpChecks(result))
+		}
+	}
+}
@@ -17,16 +17,50 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"net/url"
+	"os"
 	"reflect"
+	"strings"
 
 	configv1 "github.com/openshift/api/config/v1"
 )
 
+var (
+	// The names of the services whose endpoint can be overridden.  These are the names
+	// the installer uses in serviceEndpoints plus TransitGateway.  GlobalTagging is not
+	// called by the checks, it is accepted so that the metadata of the installer is.
+	serviceEndpointNames = []string{
+		"CIS",
+		"COS",
+		"DNSServices",
+		"GlobalSearch",
+		"GlobalTagging",
+		"IAM",
+		"Power",
+		"ResourceController",
+		"ResourceManager",
+		"TransitGateway",
+		"VPC",
+	}
+)
+
 type Metadata struct {
 	ciMode         bool
 	createMetadata CreateMetadata
 	ciMetadata     CIMetadata
+
+	// The file or installation directory the metadata was read from.
+	location string
+
+	// The installation directory the metadata was read from, or "".
+	installDir string
+
+	// install-config.yaml, if it was found, and the topology of a running cluster, if
+	// it is known.  What the checks expect is derived from them unless SetExpectations
+	// was given a profile.
+	installConfig *InstallConfig
+	topology      Topology
+	expectations  *Expectations
 }
 
 type CreateMetadata struct {
@@ -66,29 +100,65 @@ type CIMetadata struct {
 	ServiceInstance string `json:"serviceInstance"`
 	Vpc             string `json:"vpc"`
 	TransitGateway  string `json:"transitGateway"`
+	// Optional, the same as in the installer's metadata.json.
+	ServiceEndpoints []configv1.PowerVSServiceEndpoint `json:"serviceEndpoints,omitempty"`
 }
 
+// NewMetadataFromCCMetadata reads the metadata.json of openshift-install.
 func NewMetadataFromCCMetadata(filename string) (*Metadata, error) {
 	var (
-		content  []byte
-		metadata Metadata
+		metadata *Metadata
 		err      error
 	)
 
-	content, err = ioutil.ReadFile(filename)
+	metadata, err = readCCMetadata(filename)
 	if err != nil {
-		log.Fatal("Error when opening file: ", err)
 		return nil, err
 	}
 
+	return validatedMetadata(metadata)
+}
+
+func readCCMetadata(filename string) (*Metadata, error) {
+	var (
+		content  []byte
+		metadata *Metadata
+		err      error
+	)
+
+	content, err = os.ReadFile(filename)
+	if err != nil {
+		log.Debug("Error when opening file: ", err)
+		return nil, fmt.Errorf("Error: Could not read the metadata (%v)", err)
+	}
+
+	metadata, err = newMetadataFromCCContent(content)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not parse the metadata %s (%v)", filename, err)
+	}
+	metadata.location = filename
+
+	return metadata, nil
+}
+
+func newMetadataFromCCContent(content []byte) (*Metadata, error) {
+	var (
+		metadata Metadata
+		err      error
+	)
+
 	log.Debugf("NewMetadataFromCCMetadata: content = %s", string(content))
 
 	err = json.Unmarshal(content, &metadata.createMetadata)
 	if err != nil {
-		log.Fatal("Error during Unmarshal(): ", err)
+		log.Debug("Error during Unmarshal(): ", err)
 		return nil, err
 	}
 
+	if metadata.createMetadata.PowerVS == nil {
+		return nil, fmt.Errorf("Error: The metadata is not for a PowerVS cluster")
+	}
+
 	metadata.ciMode = false
 
 	log.Debugf("NewMetadataFromCCMetadata: metadata = %+v", metadata)
@@ -98,24 +168,40 @@ func NewMetadataFromCCMetadata(filename string) (*Metadata, error) {
 	return &metadata, nil
 }
 
+// NewMetadataFromCIMetadata reads a CI metadata file.
 func NewMetadataFromCIMetadata(filename string) (*Metadata, error) {
 	var (
 		content  []byte
-		metadata Metadata
+		metadata *Metadata
 		err      error
 	)
 
-	content, err = ioutil.ReadFile(filename)
+	content, err = os.ReadFile(filename)
 	if err != nil {
-		log.Fatal("Error when opening file: ", err)
-		return nil, err
+		log.Debug("Error when opening file: ", err)
+		return nil, fmt.Errorf("Error: Could not read the metadata (%v)", err)
+	}
+
+	metadata, err = newMetadataFromCIContent(content)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not parse the metadata %s (%v)", filename, err)
 	}
+	metadata.location = filename
+
+	return validatedMetadata(metadata)
+}
+
+func newMetadataFromCIContent(content []byte) (*Metadata, error) {
+	var (
+		metadata Metadata
+		err      error
+	)
 
 	log.Debugf("NewMetadataFromCIMetadata: content = %s", string(content))
 
 	err = json.Unmarshal(content, &metadata.ciMetadata)
 	if err != nil {
-		log.Fatal("Error during Unmarshal(): ", err)
+		log.Debug("Error during Unmarshal(): ", err)
 		return nil, err
 	}
 
@@ -129,11 +215,143 @@ func NewMetadataFromCIMetadata(filename string) (*Metadata, error) {
 		VPCRegion:            metadata.ciMetadata.VPCRegion,
 		Zone:                 metadata.ciMetadata.Zone,
 		PowerVSResourceGroup: metadata.ciMetadata.ResourceGroup,
+		ServiceEndpoints:     metadata.ciMetadata.ServiceEndpoints,
 	}
 
 	return &metadata, nil
 }
 
+// NewMetadata reads the metadata from a file or from the installation directory of
+// openshift-install.  Whether a file is the metadata.json of the installer or a CI
+// metadata file is found out from the fields it has.  The problems with the metadata
+// are returned together in a *MetadataError.
+func NewMetadata(location string) (*Metadata, error) {
+	var (
+		metadata *Metadata
+		err      error
+	)
+
+	metadata, err = readMetadata(location)
+	if err != nil {
+		return nil, err
+	}
+
+	return validatedMetadata(metadata)
+}
+
+// readMetadata is NewMetadata without the validation.
+func readMetadata(location string) (*Metadata, error) {
+	var (
+		info     os.FileInfo
+		content  []byte
+		ciMode   bool
+		metadata *Metadata
+		err      error
+	)
+
+	info, err = os.Stat(location)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not read the metadata (%v)", err)
+	}
+	if info.IsDir() {
+		return readInstallDir(location)
+	}
+
+	content, err = os.ReadFile(location)
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not read the metadata (%v)", err)
+	}
+
+	ciMode, err = detectMetadataType(content)
+	if err != nil {
+		return nil, fmt.Errorf("%v (%s)", err, location)
+	}
+	log.Debugf("NewMetadata: %s ciMode = %v", location, ciMode)
+
+	if ciMode {
+		metadata, err = newMetadataFromCIContent(content)
+	} else {
+		metadata, err = newMetadataFromCCContent(content)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not parse the metadata %s (%v)", location, err)
+	}
+	metadata.location = location
+
+	return metadata, nil
+}
+
+func validatedMetadata(metadata *Metadata) (*Metadata, error) {
+	var (
+		err error
+	)
+
+	err = metadata.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// detectMetadataType returns true when the JSON has the fields of a CI metadata file and
+// false when it has the fields of the metadata.json of the installer.
+func detectMetadataType(content []byte) (bool, error) {
+	var (
+		fields map[string]json.RawMessage
+		err    error
+	)
+
+	err = json.Unmarshal(content, &fields)
+	if err != nil {
+		return false, fmt.Errorf("Error: The metadata is not a JSON object (%v)", err)
+	}
+
+	for _, field := range []string{"clusterName", "clusterID", "infraID", "powervs"} {
+		if _, ok := fields[field]; ok {
+			return false, nil
+		}
+	}
+
+	for _, field := range []string{"serviceInstance", "resourceGroup", "vpc", "transitGateway", "region"} {
+		if _, ok := fields[field]; ok {
+			return true, nil
+		}
+	}
+
+	return false, fmt.Errorf("Error: The metadata is neither the metadata.json of openshift-install nor a CI metadata file")
+}
+
+// loadMetadata reads the metadata named by the -metadata or the -installDir flag of a
+// command, only one of which may be set.
+func loadMetadata(metadataLocation string, installDir string) (*Metadata, error) {
+	var (
+		metadata *Metadata
+		err      error
+	)
+
+	metadata, err = readMetadataLocation(metadataLocation, installDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return validatedMetadata(metadata)
+}
+
+// readMetadataLocation is loadMetadata without the validation.
+func readMetadataLocation(metadataLocation string, installDir string) (*Metadata, error) {
+	switch {
+	case metadataLocation != "" && installDir != "":
+		return nil, fmt.Errorf("Error: Use either -metadata or -installDir, not both")
+	case installDir != "":
+		return readInstallDir(installDir)
+	case metadataLocation != "":
+		return readMetadata(metadataLocation)
+	}
+
+	return nil, fmt.Errorf("Error: No metadata file location set, use -metadata or -installDir")
+}
+
 func (m *Metadata) GetObjectName(ro RunnableObject) (string, error) {
 	if m.ciMode {
 		return m.GetCIObjectName(ro)
@@ -249,3 +467,166 @@ func (m *Metadata) GetTransitGatewayName() string {
 func (m *Metadata) GetVpcName() string {
 	return m.createMetadata.PowerVS.VPC
 }
+
+// GetInstallDir returns the installation directory of openshift-install, or "" when the
+// metadata was read from a file.
+func (m *Metadata) GetInstallDir() string {
+	return m.installDir
+}
+
+// GetExpectations returns what the checks expect of the cluster.
+func (m *Metadata) GetExpectations() *Expectations {
+	if m.expectations != nil {
+		return m.expectations
+	}
+
+	return NewExpectations(nil, m.installConfig, m.topology)
+}
+
+// SetExpectations sets what the checks expect of the cluster from the -expectations
+// profile, if there is one, and from install-config.yaml.
+func (m *Metadata) SetExpectations(profileFile string) error {
+	var (
+		profile *ExpectationsProfile
+		err     error
+	)
+
+	if profileFile != "" {
+		profile, err = readExpectationsProfile(profileFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	m.expectations = NewExpectations(profile, m.installConfig, m.topology)
+	if m.expectations.ControlPlaneReplicas < 1 {
+		return fmt.Errorf("Error: The expectations need at least 1 control plane node (%d)", m.expectations.ControlPlaneReplicas)
+	}
+	log.Debugf("SetExpectations: expectations = %+v", m.expectations)
+
+	return nil
+}
+
+func (m *Metadata) GetControlPlaneReplicas() int {
+	return m.GetExpectations().ControlPlaneReplicas
+}
+
+// GetComputeReplicas returns the expected number of compute nodes, and false when it is
+// not known.
+func (m *Metadata) GetComputeReplicas() (int, bool) {
+	var (
+		computeReplicas = m.GetExpectations().ComputeReplicas
+	)
+
+	return computeReplicas, computeReplicas >= 0
+}
+
+// SetServiceEndpoints adds the endpoint overrides from the -serviceEndpoints flag, which
+// is a comma separated list of NAME=URL, for example "IAM=https://private.iam.cloud.ibm.com".
+// They take precedence over the serviceEndpoints in the metadata.
+func (m *Metadata) SetServiceEndpoints(overrides string) error {
+	var (
+		serviceEndpoints []configv1.PowerVSServiceEndpoint
+		err              error
+	)
+
+	serviceEndpoints, err = parseServiceEndpoints(overrides)
+	if err != nil {
+		return err
+	}
+
+	for _, serviceEndpoint := range serviceEndpoints {
+		m.setServiceEndpoint(serviceEndpoint.Name, serviceEndpoint.URL)
+	}
+
+	return nil
+}
+
+// parseServiceEndpoints parses the -serviceEndpoints flag.
+func parseServiceEndpoints(overrides string) ([]configv1.PowerVSServiceEndpoint, error) {
+	var (
+		name             string
+		endpoint         string
+		found            bool
+		serviceEndpoints []configv1.PowerVSServiceEndpoint
+	)
+
+	for _, override := range strings.Split(overrides, ",") {
+		override = strings.TrimSpace(override)
+		if override == "" {
+			continue
+		}
+
+		name, endpoint, found = strings.Cut(override, "=")
+		if !found {
+			return nil, fmt.Errorf("Error: The service endpoint %s is not NAME=URL", override)
+		}
+		name = strings.TrimSpace(name)
+		endpoint = strings.TrimSpace(endpoint)
+
+		if !isServiceEndpointName(name) {
+			return nil, fmt.Errorf("Error: Unknown service endpoint name %s, use one of %s", name, strings.Join(serviceEndpointNames, ", "))
+		}
+
+		if !isServiceEndpointURL(endpoint) {
+			return nil, fmt.Errorf("Error: The service endpoint %s has an invalid URL %s", name, endpoint)
+		}
+
+		serviceEndpoints = append(serviceEndpoints, configv1.PowerVSServiceEndpoint{
+			Name: name,
+			URL:  endpoint,
+		})
+	}
+
+	return serviceEndpoints, nil
+}
+
+func (m *Metadata) setServiceEndpoint(name string, endpoint string) {
+	for i, serviceEndpoint := range m.createMetadata.PowerVS.ServiceEndpoints {
+		if strings.EqualFold(serviceEndpoint.Name, name) {
+			m.createMetadata.PowerVS.ServiceEndpoints[i].URL = endpoint
+			return
+		}
+	}
+
+	m.createMetadata.PowerVS.ServiceEndpoints = append(m.createMetadata.PowerVS.ServiceEndpoints, configv1.PowerVSServiceEndpoint{
+		Name: name,
+		URL:  endpoint,
+	})
+}
+
+// GetServiceEndpoint returns the overridden URL of the named service, otherwise defaultURL.
+func (m *Metadata) GetServiceEndpoint(name string, defaultURL string) string {
+	for _, serviceEndpoint := range m.createMetadata.PowerVS.ServiceEndpoints {
+		if strings.EqualFold(serviceEndpoint.Name, name) && serviceEndpoint.URL != "" {
+			log.Debugf("GetServiceEndpoint: %s = %s", name, serviceEndpoint.URL)
+			return serviceEndpoint.URL
+		}
+	}
+
+	return defaultURL
+}
+
+func isServiceEndpointName(name string) bool {
+	for _, serviceEndpointName := range serviceEndpointNames {
+		if strings.EqualFold(serviceEndpointName, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isServiceEndpointURL(endpoint string) bool {
+	var (
+		parsed *url.URL
+		err    error
+	)
+
+	parsed, err = url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+
+	return (parsed.Scheme == "https" || parsed.Scheme == "http") && parsed.Host != ""
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MetadataProblem is one thing which is wrong with the metadata.  Field is the name of
+// the field in the metadata file, for example "powervs.zone".
+type MetadataProblem struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (p MetadataProblem) String() string {
+	return fmt.Sprintf("%s: %s", p.Field, p.Message)
+}
+
+// MetadataError is returned by the metadata loaders with all of the problems found in
+// the metadata, not just the first one.
+type MetadataError struct {
+	// The file or installation directory the metadata was read from.
+	Location string            `json:"location"`
+	Problems []MetadataProblem `json:"problems"`
+}
+
+func (e *MetadataError) Error() string {
+	var (
+		sb strings.Builder
+	)
+
+	fmt.Fprintf(&sb, "Error: The metadata %s is not valid:", e.Location)
+	for _, problem := range e.Problems {
+		fmt.Fprintf(&sb, "\n    %s", problem)
+	}
+
+	return sb.String()
+}
+
+// Validate checks that the fields which the mode of the metadata needs are set, that the
+// region, zone and VPC region go together, and that the service endpoints are URLs.  It
+// returns nil or a *MetadataError.
+func (m *Metadata) Validate() error {
+	var (
+		prefix   string
+		problems []MetadataProblem
+	)
+
+	addProblem := func(field string, format string, a ...any) {
+		problems = append(problems, MetadataProblem{
+			Field:   prefix + field,
+			Message: fmt.Sprintf(format, a...),
+		})
+	}
+	require := func(field string, value string) {
+		if value == "" {
+			addProblem(field, "is required")
+		}
+	}
+
+	if m.ciMode {
+		require("region", m.ciMetadata.Region)
+		require("zone", m.ciMetadata.Zone)
+		require("resourceGroup", m.ciMetadata.ResourceGroup)
+		require("serviceInstance", m.ciMetadata.ServiceInstance)
+		require("vpc", m.ciMetadata.Vpc)
+		require("transitGateway", m.ciMetadata.TransitGateway)
+	} else {
+		require("clusterName", m.createMetadata.ClusterName)
+		require("infraID", m.createMetadata.InfraID)
+
+		if m.createMetadata.PowerVS == nil {
+			addProblem("powervs", "is required")
+			return &MetadataError{Location: m.location, Problems: problems}
+		}
+
+		prefix = "powervs."
+		require("region", m.createMetadata.PowerVS.Region)
+		require("zone", m.createMetadata.PowerVS.Zone)
+		require("powerVSResourceGroup", m.createMetadata.PowerVS.PowerVSResourceGroup)
+		require("BaseDomain", m.createMetadata.PowerVS.BaseDomain)
+
+		// A public cluster uses CIS and a private cluster uses DNS Services.
+		if m.createMetadata.PowerVS.CISInstanceCRN == "" && m.createMetadata.PowerVS.DNSInstanceCRN == "" {
+			addProblem("cisInstanceCRN", "is required, or %sdnsInstanceCRN for a private cluster", prefix)
+		}
+	}
+
+	for _, problem := range m.validateRegion() {
+		addProblem(problem.Field, "%s", problem.Message)
+	}
+
+	for i, serviceEndpoint := range m.createMetadata.PowerVS.ServiceEndpoints {
+		if serviceEndpoint.Name == "" {
+			addProblem(fmt.Sprintf("serviceEndpoints[%d].name", i), "is required")
+		}
+		if !isServiceEndpointURL(serviceEndpoint.URL) {
+			addProblem(fmt.Sprintf("serviceEndpoints[%d].url", i), "%q is not an http or https URL", serviceEndpoint.URL)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &MetadataError{Location: m.location, Problems: problems}
+	}
+
+	return nil
+}
+
+// validateRegion checks the region, the zone and the VPC region against Regions.  Empty
+// fields are left to the required checks.
+func (m *Metadata) validateRegion() []MetadataProblem {
+	var (
+		powerVS  = m.createMetadata.PowerVS
+		region   Region
+		found    bool
+		problems []MetadataProblem
+	)
+
+	if powerVS.Region == "" {
+		return nil
+	}
+
+	region, found = regionCatalog.Region(powerVS.Region)
+	if !found {
+		return []MetadataProblem{{
+			Field:   "region",
+			Message: fmt.Sprintf("%s is not a PowerVS region, use one of %s", powerVS.Region, strings.Join(regionCatalog.RegionNames(), ", ")),
+		}}
+	}
+
+	if _, found = region.Zones[powerVS.Zone]; powerVS.Zone != "" && !found {
+		problems = append(problems, MetadataProblem{
+			Field:   "zone",
+			Message: fmt.Sprintf("%s is not a zone of the region %s, use one of %s", powerVS.Zone, powerVS.Region, strings.Join(sortedKeys(region.Zones), ", ")),
+		})
+	}
+
+	// A region which only the datacenters API knows has no VPC region to compare with.
+	if powerVS.VPCRegion != "" && region.VPCRegion != "" && powerVS.VPCRegion != region.VPCRegion {
+		problems = append(problems, MetadataProblem{
+			Field:   "vpcRegion",
+			Message: fmt.Sprintf("%s does not go with the region %s, whose VPC region is %s", powerVS.VPCRegion, powerVS.Region, region.VPCRegion),
+		})
+	}
+
+	return problems
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	var (
+		keys = make([]string, 0, len(m))
+	)
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
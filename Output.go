@@ -0,0 +1,369 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// OutputFormat is the format of the results printed by a command.
+type OutputFormat string
+
+const (
+	OutputFormatText  OutputFormat = "text"
+	OutputFormatJSON  OutputFormat = "json"
+	OutputFormatYAML  OutputFormat = "yaml"
+	OutputFormatJUnit OutputFormat = "junit"
+)
+
+// ParseOutputFormat converts the value of the -output flag into an OutputFormat.
+func ParseOutputFormat(format string) (OutputFormat, error) {
+	switch strings.ToLower(format) {
+	case "text":
+		return OutputFormatText, nil
+	case "json":
+		return OutputFormatJSON, nil
+	case "yaml":
+		return OutputFormatYAML, nil
+	case "junit", "xml":
+		return OutputFormatJUnit, nil
+	}
+
+	return "", fmt.Errorf("Error: output is not text/json/yaml/junit (%s)", format)
+}
+
+// Report is the aggregated result of all the RunnableObjects of a command.
+type Report struct {
+	Command   string          `json:"command"`
+	Version   string          `json:"version"`
+	Release   string          `json:"release"`
+	Status    CheckStatus     `json:"status"`
+	Cancelled bool            `json:"cancelled,omitempty"`
+	Results   []*ObjectResult `json:"results"`
+	Errors    []string        `json:"errors,omitempty"`
+}
+
+// NewReport aggregates the results of a command.
+func NewReport(command string, results []*ObjectResult, discoveryErrs []error) *Report {
+	var (
+		report *Report
+	)
+
+	report = &Report{
+		Command: command,
+		Version: version,
+		Release: release,
+		Status:  CheckStatusOK,
+		Results: results,
+	}
+
+	for _, r := range results {
+		if statusRank(r.Status) > statusRank(report.Status) {
+			report.Status = r.Status
+		}
+	}
+
+	for _, err := range discoveryErrs {
+		report.Errors = append(report.Errors, err.Error())
+	}
+
+	return report
+}
+
+// renderReport writes the report in the requested format.
+func renderReport(w io.Writer, format OutputFormat, report *Report) error {
+	switch format {
+	case OutputFormatText:
+		renderText(w, report.Results)
+		if report.Cancelled {
+			fmt.Fprintf(w, "The run was interrupted, the results are incomplete.\n")
+		}
+		return nil
+	case OutputFormatJSON:
+		return renderJSON(w, report)
+	case OutputFormatYAML:
+		return renderYAML(w, report)
+	case OutputFormatJUnit:
+		return renderJUnit(w, report)
+	}
+
+	return fmt.Errorf("Error: unknown output format %s", format)
+}
+
+func renderJSON(w io.Writer, report any) error {
+	var (
+		encoder *json.Encoder
+	)
+
+	encoder = json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
+
+func renderYAML(w io.Writer, report any) error {
+	var (
+		data []byte
+		err  error
+	)
+
+	// Use the json tags so that both formats have the same field names.
+	data, err = yaml.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+
+	return err
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// renderJUnit writes one test suite per object and one test case per check.
+func renderJUnit(w io.Writer, report *Report) error {
+	var (
+		suites junitTestSuites
+	)
+
+	suites = junitTestSuites{
+		Name:   fmt.Sprintf("PowerVS-Check %s", report.Command),
+		Suites: make([]junitTestSuite, 0, len(report.Results)),
+	}
+
+	suites.addReport(report, "")
+
+	return writeJUnit(w, &suites)
+}
+
+// addReport adds the suites of the report with their names prefixed by prefix.
+func (suites *junitTestSuites) addReport(report *Report, prefix string) {
+	for _, r := range report.Results {
+		suite := junitTestSuite{
+			Name:      prefix + resultPrefix(r),
+			TestCases: make([]junitTestCase, 0, len(r.Checks)),
+		}
+
+		for _, check := range r.Checks {
+			testCase := junitTestCase{
+				Name:      fmt.Sprintf("%s %s", check.ID, check.Message),
+				ClassName: r.ObjectType,
+				SystemOut: junitEvidence(check),
+			}
+
+			switch check.Status {
+			case CheckStatusNotOK:
+				testCase.Failure = &junitMessage{
+					Message: check.Message,
+					Type:    string(check.Severity),
+					Text:    fmt.Sprintf("%s is NOTOK. %s", resultPrefix(r), check.Message),
+				}
+				suite.Failures++
+			case CheckStatusError:
+				testCase.Error = &junitMessage{
+					Message: check.Message,
+					Type:    string(check.Severity),
+					Text:    fmt.Sprintf("%s is NOTOK. %s", resultPrefix(r), check.Message),
+				}
+				suite.Errors++
+			case CheckStatusSkipped:
+				testCase.Skipped = &junitMessage{
+					Message: check.Message,
+					Type:    string(check.Severity),
+				}
+				suite.Skipped++
+			}
+
+			suite.TestCases = append(suite.TestCases, testCase)
+			suite.Tests++
+		}
+
+		suites.addSuite(suite)
+	}
+
+	if len(report.Errors) > 0 {
+		suites.addErrors(prefix+"Discovery", report.Errors)
+	}
+}
+
+// addErrors adds a suite with one failed test case per error.
+func (suites *junitTestSuites) addErrors(name string, errs []string) {
+	var (
+		suite = junitTestSuite{
+			Name:      name,
+			TestCases: make([]junitTestCase, 0, len(errs)),
+		}
+	)
+
+	for _, errMessage := range errs {
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      errMessage,
+			ClassName: "Discovery",
+			Error: &junitMessage{
+				Message: errMessage,
+				Type:    string(CheckSeverityCritical),
+				Text:    errMessage,
+			},
+		})
+		suite.Tests++
+		suite.Errors++
+	}
+
+	suites.addSuite(suite)
+}
+
+func (suites *junitTestSuites) addSuite(suite junitTestSuite) {
+	suites.Suites = append(suites.Suites, suite)
+	suites.Tests += suite.Tests
+	suites.Failures += suite.Failures
+	suites.Errors += suite.Errors
+	suites.Skipped += suite.Skipped
+}
+
+func writeJUnit(w io.Writer, suites *junitTestSuites) error {
+	var (
+		encoder *xml.Encoder
+		err     error
+	)
+
+	_, err = io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	encoder = xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	err = encoder.Encode(suites)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+
+	return err
+}
+
+// junitEvidence flattens the evidence of a check into "key=value" lines.
+func junitEvidence(check *CheckResult) string {
+	var (
+		keys  []string
+		lines []string
+	)
+
+	for key := range check.Evidence {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("%s=%s", key, check.Evidence[key]))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// resultPrefix returns "<object type> <name>", or just the object type if there is no name.
+func resultPrefix(r *ObjectResult) string {
+	if r.Name == "" {
+		return r.ObjectType
+	}
+
+	return fmt.Sprintf("%s %s", r.ObjectType, r.Name)
+}
+
+// renderChecksText writes one line per check of the object.
+func renderChecksText(w io.Writer, r *ObjectResult) {
+	var (
+		prefix string
+	)
+
+	prefix = resultPrefix(r)
+
+	for _, check := range r.Checks {
+		switch check.Status {
+		case CheckStatusOK, CheckStatusSkipped:
+			fmt.Fprintf(w, "%s %s\n", prefix, check.Message)
+		default:
+			fmt.Fprintf(w, "%s is NOTOK. %s\n", prefix, check.Message)
+		}
+	}
+}
+
+// renderObjectText writes the checks of the object followed by its overall status.
+func renderObjectText(w io.Writer, r *ObjectResult) {
+	// Nothing was checked, so there is nothing to say.
+	if len(r.Checks) == 0 {
+		return
+	}
+
+	renderChecksText(w, r)
+
+	if r.Status == CheckStatusSkipped {
+		return
+	}
+
+	if r.IsOK() {
+		fmt.Fprintf(w, "%s is OK.\n", resultPrefix(r))
+	} else {
+		fmt.Fprintf(w, "%s is NOTOK.\n", resultPrefix(r))
+	}
+}
+
+// renderText writes the human readable form of the results.
+func renderText(w io.Writer, results []*ObjectResult) {
+	for _, r := range results {
+		renderObjectText(w, r)
+	}
+}
@@ -18,15 +18,24 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	defaultWorkers = 4
+)
+
 var (
 	// Replaced with:
 	//   -ldflags="-X main.version=$(git describe --always --long --dirty)"
@@ -36,16 +45,39 @@ var (
 	shouldDebug  = false
 	shouldDelete = false
 
-	log *logrus.Logger
+	// The number of objects which are queried at the same time, see -workers.
+	numWorkers = defaultWorkers
+
+	// Where watch-create prints its progress, see -output.
+	progressOut io.Writer = os.Stdout
+
+	// Where the diagnostics of the instances which are not ACTIVE are saved, see
+	// -diagnosticsDir.  They are not gathered when it is empty.
+	diagnosticsDir = ""
+
+	// The -serviceEndpoints of watch-create, which are applied to the metadata every
+	// time it is read from the installation directory.
+	watchServiceEndpoints = ""
+
+	// Discarded until a command sets it up from -shouldDebug, so that the functions which
+	// log can also be called before that, or from elsewhere.
+	log = &logrus.Logger{
+		Out:       io.Discard,
+		Formatter: new(logrus.TextFormatter),
+		Level:     logrus.DebugLevel,
+	}
 )
 
 func printUsage(executableName string) {
 	fmt.Fprintf(os.Stderr, "Usage: %s [ "+
+		"check-batch | "+
 		"check-ci | "+
 		"check-create | "+
 		"check-kubeconfig | "+
 		"check-capi-kubeconfig | "+
 		"create-jumpbox | "+
+		"list-regions | "+
+		"validate-metadata | "+
 		"watch-create "+
 		"]\n", executableName)
 }
@@ -53,12 +85,17 @@ func printUsage(executableName string) {
 func main() {
 	var (
 		executableName           string
+		checkBatchFlags          *flag.FlagSet
 		checkCiFlags             *flag.FlagSet
 		checkCreateFlags         *flag.FlagSet
 		checkKubeconfigFlags     *flag.FlagSet
 		checkCapiKubeconfigFlags *flag.FlagSet
 		createJumpboxFlags       *flag.FlagSet
+		listRegionsFlags         *flag.FlagSet
+		validateMetadataFlags    *flag.FlagSet
 		watchCreateClusterFlags  *flag.FlagSet
+		ctx                      context.Context
+		stop                     context.CancelFunc
 		err                      error
 	)
 
@@ -72,46 +109,89 @@ func main() {
 
 	if len(os.Args) == 1 {
 		printUsage(executableName)
-		os.Exit(1)
+		os.Exit(exitCodeUsage)
 	} else if len(os.Args) == 2 && os.Args[1] == "-version" {
 		fmt.Fprintf(os.Stderr, "version = %v\nrelease = %v\n", version, release)
 		os.Exit(1)
 	}
 
+	checkBatchFlags = flag.NewFlagSet("check-batch", flag.ExitOnError)
 	checkCiFlags = flag.NewFlagSet("check-ci", flag.ExitOnError)
 	checkCreateFlags = flag.NewFlagSet("check-create", flag.ExitOnError)
 	checkKubeconfigFlags = flag.NewFlagSet("check-kubeconfig", flag.ExitOnError)
 	checkCapiKubeconfigFlags = flag.NewFlagSet("check-capi-kubeconfig", flag.ExitOnError)
 	createJumpboxFlags = flag.NewFlagSet("create-jumpbox", flag.ExitOnError)
+	listRegionsFlags = flag.NewFlagSet("list-regions", flag.ExitOnError)
+	validateMetadataFlags = flag.NewFlagSet("validate-metadata", flag.ExitOnError)
 	watchCreateClusterFlags = flag.NewFlagSet("watch-create", flag.ExitOnError)
 
+	// The first Ctrl-C cancels the outstanding API calls so that a partial
+	// summary can be printed.  Once it has been seen, the default handling is
+	// restored and a second Ctrl-C exits immediately.
+	ctx, stop = signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+		fmt.Fprintf(os.Stderr, "Interrupted, waiting for the outstanding requests to stop.  Press Ctrl-C again to exit now.\n")
+	}()
+
 	switch strings.ToLower(os.Args[1]) {
+	case "check-batch":
+		err = checkBatchCommand(ctx, checkBatchFlags, os.Args[2:])
+
 	case "check-ci":
-		err = checkCiCommand(checkCiFlags, os.Args[2:])
+		err = checkCiCommand(ctx, checkCiFlags, os.Args[2:])
 
 	case "check-create":
-		err = checkCreateCommand(checkCreateFlags, os.Args[2:])
+		err = checkCreateCommand(ctx, checkCreateFlags, os.Args[2:])
 
 	case "check-kubeconfig":
-		err = checkKubeconfigCommand(checkKubeconfigFlags, os.Args[2:])
+		err = checkKubeconfigCommand(ctx, checkKubeconfigFlags, os.Args[2:])
 
 	case "check-capi-kubeconfig":
-		err = checkCapiKubeconfigCommand(checkCapiKubeconfigFlags, os.Args[2:])
+		err = checkCapiKubeconfigCommand(ctx, checkCapiKubeconfigFlags, os.Args[2:])
 
 	case "create-jumpbox":
-		err = createJumpboxCommand(createJumpboxFlags, os.Args[2:])
+		err = createJumpboxCommand(ctx, createJumpboxFlags, os.Args[2:])
+
+	case "list-regions":
+		err = listRegionsCommand(ctx, listRegionsFlags, os.Args[2:])
+
+	case "validate-metadata":
+		err = validateMetadataCommand(ctx, validateMetadataFlags, os.Args[2:])
 
 	case "watch-create":
-		err = watchCreateCommand(watchCreateClusterFlags, os.Args[2:])
+		err = watchCreateCommand(ctx, watchCreateClusterFlags, os.Args[2:])
 
 	default:
 		fmt.Fprintf(os.Stderr, "Error: Unknown command %s\n", os.Args[1])
 		printUsage(executableName)
-		os.Exit(1)
+		os.Exit(exitCodeUsage)
+	}
+
+	if tracer != nil {
+		traceErr := tracer.Finish(os.Stderr)
+		if traceErr != nil {
+			fmt.Fprintln(os.Stderr, traceErr)
+		}
 	}
 
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		var exitErr *ExitError
+
+		if errors.As(err, &exitErr) {
+			if exitErr.Err != nil {
+				fmt.Fprintln(os.Stderr, exitErr.Err)
+			}
+			os.Exit(exitErr.Code)
+		}
+
+		fmt.Fprintln(os.Stderr, err)
+		if ctx.Err() != nil {
+			os.Exit(exitCodeInterrupted)
+		}
+		os.Exit(exitCodeError)
 	}
+
+	os.Exit(exitCodeOK)
 }
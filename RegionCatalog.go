@@ -0,0 +1,341 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"strings"
+
+	"github.com/IBM-Cloud/power-go-client/helpers"
+	powerclient "github.com/IBM-Cloud/power-go-client/power/client"
+	"github.com/IBM-Cloud/power-go-client/power/client/datacenters"
+	"github.com/IBM-Cloud/power-go-client/power/models"
+
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+const (
+	// Where the datacenters are listed from unless the Power service endpoint is
+	// overridden.  The listing is the same in every region and needs no API key.
+	defaultDatacentersEndpoint = "https://us-south.power-iaas.cloud.ibm.com"
+
+	builtinRegionCatalog = "built-in"
+)
+
+var (
+	// The regions which the metadata is validated against, see -regionCatalog.
+	regionCatalog = NewBuiltinRegionCatalog()
+)
+
+// RegionCatalog holds the PowerVS regions with their zones, and the VPC and COS regions
+// which go with them.
+type RegionCatalog struct {
+	// "built-in" or the URL the datacenters were listed from.
+	Source string
+
+	Regions map[string]Region
+}
+
+// RegionListing is one zone of the region catalog as list-regions prints it.
+type RegionListing struct {
+	Region       string   `json:"region"`
+	Description  string   `json:"description"`
+	Zone         string   `json:"zone"`
+	VPCRegion    string   `json:"vpcRegion,omitempty"`
+	COSRegion    string   `json:"cosRegion,omitempty"`
+	SysTypes     []string `json:"sysTypes"`
+	Capabilities []string `json:"capabilities,omitempty"`
+	Status       string   `json:"status,omitempty"`
+}
+
+// NewBuiltinRegionCatalog returns the catalog of the hard-coded Regions.
+func NewBuiltinRegionCatalog() *RegionCatalog {
+	return &RegionCatalog{
+		Source:  builtinRegionCatalog,
+		Regions: Regions,
+	}
+}
+
+// NewRegionCatalog adds the datacenters which the PowerVS API lists to the built-in
+// regions.  The API knows the zones, their system types and capabilities, but not the
+// VPC and COS regions, so a region which is not built in has neither.
+func NewRegionCatalog(client PIDatacentersClient, source string) (*RegionCatalog, error) {
+	var (
+		datacenters *models.Datacenters
+		catalog     *RegionCatalog
+		regionName  string
+		region      Region
+		zone        Zone
+		found       bool
+		err         error
+	)
+
+	datacenters, err = client.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not list the PowerVS datacenters (%v)", err)
+	}
+
+	catalog = &RegionCatalog{
+		Source:  source,
+		Regions: make(map[string]Region, len(Regions)),
+	}
+	for name, builtin := range Regions {
+		builtin.Zones = maps.Clone(builtin.Zones)
+		catalog.Regions[name] = builtin
+	}
+
+	for _, datacenter := range datacenters.Datacenters {
+		if datacenter == nil || datacenter.Location == nil || datacenter.Location.Region == nil {
+			continue
+		}
+		log.Debugf("NewRegionCatalog: datacenter = %+v", datacenter.Location)
+
+		// Private datacenters, for example Satellite locations, are not PowerVS zones.
+		if datacenter.Type != nil && *datacenter.Type != "" && *datacenter.Type != "off-premises" {
+			log.Debugf("NewRegionCatalog: skipping %s of type %s", *datacenter.Location.Region, *datacenter.Type)
+			continue
+		}
+
+		regionName = catalog.regionOfDatacenter(datacenter)
+		if regionName == "" {
+			log.Debugf("NewRegionCatalog: no region for %s", *datacenter.Location.Region)
+			continue
+		}
+
+		region, found = catalog.Regions[regionName]
+		if !found {
+			region = Region{
+				Zones: make(map[string]Zone),
+			}
+			if datacenter.Location.RegionDisplayName != nil {
+				region.Description = *datacenter.Location.RegionDisplayName
+			}
+		}
+
+		zone = region.Zones[*datacenter.Location.Region]
+		if datacenter.CapabilitiesDetails != nil && datacenter.CapabilitiesDetails.SupportedSystems != nil {
+			zone.SysTypes = datacenter.CapabilitiesDetails.SupportedSystems.General
+		}
+		zone.Capabilities = datacenter.Capabilities
+		if datacenter.Status != nil {
+			zone.Status = *datacenter.Status
+		}
+		region.Zones[*datacenter.Location.Region] = zone
+
+		catalog.Regions[regionName] = region
+	}
+
+	return catalog, nil
+}
+
+// regionOfDatacenter returns the region of a datacenter, which is the host of its URL,
+// for example dal from https://dal.power-iaas.cloud.ibm.com, or otherwise the built-in
+// region which has the zone.
+func (catalog *RegionCatalog) regionOfDatacenter(datacenter *models.Datacenter) string {
+	var (
+		parsed *url.URL
+		err    error
+	)
+
+	if datacenter.Location.URL != nil {
+		parsed, err = url.Parse(*datacenter.Location.URL)
+		if err == nil && strings.HasSuffix(parsed.Hostname(), ".power-iaas.cloud.ibm.com") {
+			return strings.TrimSuffix(parsed.Hostname(), ".power-iaas.cloud.ibm.com")
+		}
+	}
+
+	for name, region := range catalog.Regions {
+		if _, found := region.Zones[*datacenter.Location.Region]; found {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// Region returns the named region.
+func (catalog *RegionCatalog) Region(name string) (Region, bool) {
+	region, found := catalog.Regions[name]
+
+	return region, found
+}
+
+// RegionNames returns the names of the regions, sorted.
+func (catalog *RegionCatalog) RegionNames() []string {
+	return sortedKeys(catalog.Regions)
+}
+
+// VPCRegion returns the VPC region which goes with the PowerVS region.
+func (catalog *RegionCatalog) VPCRegion(name string) (string, error) {
+	region, found := catalog.Regions[name]
+	if !found || region.VPCRegion == "" {
+		return "", fmt.Errorf("VPC region corresponding to a PowerVS region %s not found ", name)
+	}
+
+	return region.VPCRegion, nil
+}
+
+// Listings returns every zone of the catalog, sorted by region and then by zone.
+func (catalog *RegionCatalog) Listings() []RegionListing {
+	var (
+		region       Region
+		zone         Zone
+		capabilities []string
+		listings     = make([]RegionListing, 0)
+	)
+
+	for _, regionName := range catalog.RegionNames() {
+		region = catalog.Regions[regionName]
+
+		for _, zoneName := range sortedKeys(region.Zones) {
+			zone = region.Zones[zoneName]
+
+			capabilities = make([]string, 0)
+			for _, capability := range sortedKeys(zone.Capabilities) {
+				if zone.Capabilities[capability] {
+					capabilities = append(capabilities, capability)
+				}
+			}
+
+			listings = append(listings, RegionListing{
+				Region:       regionName,
+				Description:  region.Description,
+				Zone:         zoneName,
+				VPCRegion:    region.VPCRegion,
+				COSRegion:    region.COSRegion,
+				SysTypes:     zone.SysTypes,
+				Capabilities: capabilities,
+				Status:       zone.Status,
+			})
+		}
+	}
+
+	return listings
+}
+
+// publicDatacentersClient lists the public PowerVS datacenters.  Unlike
+// *instance.IBMPIDatacentersClient, it needs neither an API key nor a service instance.
+type publicDatacentersClient struct {
+	ctx context.Context
+	api *powerclient.PowerIaasAPI
+}
+
+func newPublicDatacentersClient(ctx context.Context, endpoint string) (*publicDatacentersClient, error) {
+	var (
+		parsed  *url.URL
+		runtime *httptransport.Runtime
+		err     error
+	)
+
+	parsed, err = url.Parse(endpoint)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("Error: The Power endpoint %s is not a URL", endpoint)
+	}
+
+	runtime = httptransport.New(parsed.Host, "/", []string{parsed.Scheme})
+	runtime.Transport = cloudHTTPClient("Power").Transport
+
+	return &publicDatacentersClient{
+		ctx: ctx,
+		api: powerclient.New(runtime, strfmt.Default),
+	}, nil
+}
+
+func (client *publicDatacentersClient) GetAll() (*models.Datacenters, error) {
+	var (
+		params *datacenters.V1DatacentersGetallParams
+		resp   *datacenters.V1DatacentersGetallOK
+		err    error
+	)
+
+	params = datacenters.NewV1DatacentersGetallParams().WithContext(client.ctx).WithTimeout(helpers.PIGetTimeOut)
+
+	resp, err = client.api.Datacenters.V1DatacentersGetall(params)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || resp.Payload == nil {
+		return nil, fmt.Errorf("Error: The datacenters listing is empty")
+	}
+
+	return resp.Payload, nil
+}
+
+// setupRegionCatalog sets the regions the metadata is validated against from the
+// -regionCatalog flag.  "builtin" uses the hard-coded table and "api" lists the
+// datacenters from the Power endpoint of -serviceEndpoints, or else from the fixtures
+// or the PowerVS API.  The user asked for the API, so a failed listing is an error.
+func setupRegionCatalog(ctx context.Context, source string, serviceEndpoints string, fixtures string) error {
+	var (
+		endpoints []configv1.PowerVSServiceEndpoint
+		endpoint  string
+		override  bool
+		fake      *FakeCloud
+		client    PIDatacentersClient
+		catalog   *RegionCatalog
+		err       error
+	)
+
+	switch strings.ToLower(source) {
+	case "builtin", "built-in":
+		regionCatalog = NewBuiltinRegionCatalog()
+		return nil
+	case "api":
+	default:
+		return fmt.Errorf("Error: regionCatalog is not api/builtin (%s)", source)
+	}
+
+	endpoints, err = parseServiceEndpoints(serviceEndpoints)
+	if err != nil {
+		return err
+	}
+	endpoint = defaultDatacentersEndpoint
+	for _, serviceEndpoint := range endpoints {
+		if strings.EqualFold(serviceEndpoint.Name, "Power") {
+			endpoint = serviceEndpoint.URL
+			override = true
+		}
+	}
+
+	if fixtures != "" && !override {
+		fake, err = NewFakeCloud(fixtures)
+		if err != nil {
+			return err
+		}
+		client = fake.DatacentersClient()
+		endpoint = fakeCloudURL
+	} else {
+		client, err = newPublicDatacentersClient(ctx, endpoint)
+		if err != nil {
+			return err
+		}
+	}
+
+	catalog, err = NewRegionCatalog(client, endpoint)
+	if err != nil {
+		return err
+	}
+	log.Debugf("setupRegionCatalog: regions = %v", catalog.RegionNames())
+
+	regionCatalog = catalog
+
+	return nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// RunMode is the kind of command which wants to use a RunnableObject.
+type RunMode string
+
+const (
+	// RunModeCI is used by check-ci against the CI pools.
+	RunModeCI RunMode = "ci"
+
+	// RunModeCreate is used by check-create and watch-create against an installed cluster.
+	RunModeCreate RunMode = "create"
+
+	// RunModeJumpbox is used by create-jumpbox.
+	RunModeJumpbox RunMode = "jumpbox"
+)
+
+// RunnableObjectRegistration describes a kind of RunnableObject.
+type RunnableObjectRegistration struct {
+	// Name is the object name, for example vpcObjectName.
+	Name string
+
+	// Key is a short name which can also be used with -only and -skip.
+	Key string
+
+	// New queries the cloud for the objects.
+	New NewRunnableObjects
+
+	// Modes lists the commands which use the object.
+	Modes []RunMode
+
+	// Dependencies lists the object names which must be checked first.
+	Dependencies []string
+
+	// Order places the object kind in the registry, and so in the output, among the
+	// kinds which do not depend on each other.  Lower values come first.
+	Order int
+}
+
+var (
+	// registry holds the object kinds sorted by their Order, so that the order does
+	// not depend on the order the files are initialized in.
+	registry = make([]*RunnableObjectRegistration, 0)
+)
+
+// RegisterRunnableObject adds an object kind to the registry.  It is meant to be
+// called from the init function of the file which implements the object.
+func RegisterRunnableObject(registration RunnableObjectRegistration) {
+	var (
+		index int
+	)
+
+	for _, existing := range registry {
+		if existing.Name == registration.Name || existing.Key == registration.Key {
+			panic(fmt.Sprintf("RegisterRunnableObject: %s (%s) is already registered", registration.Name, registration.Key))
+		}
+		if existing.Order == registration.Order {
+			panic(fmt.Sprintf("RegisterRunnableObject: %s has the same order as %s (%d)", registration.Name, existing.Name, registration.Order))
+		}
+	}
+
+	index, _ = slices.BinarySearchFunc(registry, registration.Order, func(existing *RunnableObjectRegistration, order int) int {
+		return cmp.Compare(existing.Order, order)
+	})
+	registry = slices.Insert(registry, index, &registration)
+}
+
+// lookupRegistration finds an object kind by its name or key, ignoring case.
+func lookupRegistration(name string) *RunnableObjectRegistration {
+	name = strings.TrimSpace(name)
+
+	for _, registration := range registry {
+		if strings.EqualFold(registration.Name, name) || strings.EqualFold(registration.Key, name) {
+			return registration
+		}
+	}
+
+	return nil
+}
+
+// dependenciesOf returns the object names the runnable object depends on.
+func dependenciesOf(robj RunnableObject) []string {
+	var (
+		robjObjectName string
+		registration   *RunnableObjectRegistration
+		err            error
+	)
+
+	robjObjectName, err = robj.ObjectName()
+	if err != nil {
+		return nil
+	}
+
+	registration = lookupRegistration(robjObjectName)
+	if registration == nil {
+		return nil
+	}
+
+	return registration.Dependencies
+}
+
+// parseObjectList converts a comma separated list of object names or keys.
+func parseObjectList(flagName string, list string) (map[string]bool, error) {
+	var (
+		selected     = make(map[string]bool)
+		registration *RunnableObjectRegistration
+		keys         []string
+	)
+
+	if strings.TrimSpace(list) == "" {
+		return selected, nil
+	}
+
+	for _, name := range strings.Split(list, ",") {
+		registration = lookupRegistration(name)
+		if registration == nil {
+			for _, registration = range registry {
+				keys = append(keys, registration.Key)
+			}
+			return nil, fmt.Errorf("Error: Unknown object %q in -%s, use one of %s", strings.TrimSpace(name), flagName, strings.Join(keys, ", "))
+		}
+		selected[registration.Name] = true
+	}
+
+	return selected, nil
+}
+
+// selectRunnableObjects returns the object kinds for the mode, limited by the -only
+// and -skip lists.
+func selectRunnableObjects(mode RunMode, only string, skip string) ([]NewRunnableObjectsEntry, error) {
+	var (
+		onlyNames map[string]bool
+		skipNames map[string]bool
+		entries   = make([]NewRunnableObjectsEntry, 0)
+		err       error
+	)
+
+	onlyNames, err = parseObjectList("only", only)
+	if err != nil {
+		return nil, err
+	}
+
+	skipNames, err = parseObjectList("skip", skip)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, registration := range registry {
+		if !registration.SupportsMode(mode) {
+			continue
+		}
+		if len(onlyNames) > 0 && !onlyNames[registration.Name] {
+			continue
+		}
+		if skipNames[registration.Name] {
+			continue
+		}
+
+		entries = append(entries, NewRunnableObjectsEntry{
+			NRO:  registration.New,
+			Name: registration.Name,
+		})
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("Error: No objects are left to check after -only and -skip")
+	}
+
+	return entries, nil
+}
+
+// SupportsMode returns true if the object kind is used by the mode.
+func (registration *RunnableObjectRegistration) SupportsMode(mode RunMode) bool {
+	for _, supported := range registration.Modes {
+		if supported == mode {
+			return true
+		}
+	}
+
+	return false
+}
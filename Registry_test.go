@@ -0,0 +1,83 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSelectRunnableObjectsOrder(t *testing.T) {
+	tests := []struct {
+		mode RunMode
+		want []string
+	}{
+		{RunModeCreate, []string{vpcObjectName, cosObjectName, tgObjectName, lbObjectName, siObjectName, vpciObjectName, dnsObjectName}},
+		{RunModeCI, []string{vpcObjectName, tgObjectName, siObjectName}},
+		{RunModeJumpbox, []string{vpcObjectName, siObjectName}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			entries, err := selectRunnableObjects(tt.mode, "", "")
+			if err != nil {
+				t.Fatalf("selectRunnableObjects() returned %v", err)
+			}
+
+			names := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				names = append(names, entry.Name)
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("selectRunnableObjects() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterRunnableObjectOrder(t *testing.T) {
+	var (
+		saved = registry
+	)
+
+	t.Cleanup(func() {
+		registry = saved
+	})
+
+	// Register out of order, as files which are renamed would be.
+	registry = make([]*RunnableObjectRegistration, 0)
+	for _, registration := range []RunnableObjectRegistration{
+		{Name: "test-c", Key: "c", Order: 30},
+		{Name: "test-a", Key: "a", Order: 10},
+		{Name: "test-b", Key: "b", Order: 20},
+	} {
+		RegisterRunnableObject(registration)
+	}
+
+	names := make([]string, 0, len(registry))
+	for _, registration := range registry {
+		names = append(names, registration.Name)
+	}
+	if want := []string{"test-a", "test-b", "test-c"}; !slices.Equal(names, want) {
+		t.Errorf("registry = %v, want %v", names, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("RegisterRunnableObject() did not panic on a duplicated order")
+		}
+	}()
+	RegisterRunnableObject(RunnableObjectRegistration{Name: "test-d", Key: "d", Order: 20})
+}
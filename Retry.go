@@ -0,0 +1,296 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand/v2"
+	gohttp "net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/go-sdk-core/v5/core"
+)
+
+// RetryPolicy says how often and how long to wait before retrying a failed IBM Cloud
+// API call.
+type RetryPolicy struct {
+	// How many times a call is retried after the first attempt.
+	MaxRetries int
+
+	// The wait before the first retry, it doubles for every further retry.
+	BaseDelay time.Duration
+
+	// The longest wait between two attempts, unless Retry-After asks for more.
+	MaxDelay time.Duration
+
+	// The longest wait which a Retry-After is followed for, so that a service cannot
+	// stall the command.
+	MaxRetryAfter time.Duration
+
+	// How long one attempt may take, until its response body is closed, so that a
+	// connection which hangs is given up and retried.
+	AttemptTimeout time.Duration
+}
+
+var (
+	// The policy which every IBM Cloud client uses.
+	retryPolicy = RetryPolicy{
+		MaxRetries:     5,
+		BaseDelay:      500 * time.Millisecond,
+		MaxDelay:       30 * time.Second,
+		MaxRetryAfter:  2 * time.Minute,
+		AttemptTimeout: 2 * time.Minute,
+	}
+
+	// The status codes which mean the call may succeed when it is tried again.
+	retryableStatusCodes = map[int]bool{
+		gohttp.StatusTooManyRequests:     true,
+		gohttp.StatusInternalServerError: true,
+		gohttp.StatusBadGateway:          true,
+		gohttp.StatusServiceUnavailable:  true,
+		gohttp.StatusGatewayTimeout:      true,
+	}
+)
+
+// retryCountKey is the context key of an *int which RetryTransport sets to the number of
+// retries a request needed.
+type retryCountKey struct{}
+
+// RetryTransport retries the requests which fail with a transient error.  The waits
+// between the attempts grow exponentially with jitter, a Retry-After header is
+// respected, and no attempt is started which could not finish before the deadline of
+// the request's context.  Every attempt has a timeout of its own, since the contexts of
+// some clients, for example the PowerVS ones, have no deadline.
+type RetryTransport struct {
+	next gohttp.RoundTripper
+
+	policy RetryPolicy
+}
+
+// RoundTrip implements gohttp.RoundTripper.
+func (transport *RetryTransport) RoundTrip(request *gohttp.Request) (*gohttp.Response, error) {
+	var (
+		ctx        = request.Context()
+		body       []byte
+		attemptCtx context.Context
+		cancel     context.CancelFunc
+		attempt    *gohttp.Request
+		response   *gohttp.Response
+		wait       time.Duration
+		retries    int
+		err        error
+	)
+
+	// Every attempt needs its own copy of the body.
+	if request.Body != nil {
+		body, err = io.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for retries = 0; ; retries++ {
+		attemptCtx, cancel = context.WithTimeout(ctx, transport.policy.AttemptTimeout)
+		attempt = request.Clone(attemptCtx)
+		if request.Body != nil {
+			attempt.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		response, err = transport.next.RoundTrip(attempt)
+		if err != nil {
+			cancel()
+		} else {
+			// The body is read after RoundTrip returns, so the attempt ends when it is closed.
+			response.Body = &cancelOnCloseBody{ReadCloser: response.Body, cancel: cancel}
+		}
+
+		if !transport.shouldRetry(request, response, err) || retries >= transport.policy.MaxRetries {
+			break
+		}
+
+		wait = transport.policy.backoff(retries, response)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			log.Debugf("RetryTransport: %s %s not retried, the deadline is before the next attempt", request.Method, request.URL)
+			break
+		}
+
+		if err != nil {
+			log.Debugf("RetryTransport: %s %s returned %v, retry %d of %d in %v", request.Method, request.URL, err, retries+1, transport.policy.MaxRetries, wait)
+		} else {
+			log.Debugf("RetryTransport: %s %s returned %s, retry %d of %d in %v", request.Method, request.URL, response.Status, retries+1, transport.policy.MaxRetries, wait)
+
+			// The response is thrown away, so let the connection be reused.
+			_, _ = io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			setRetryCount(ctx, retries+1)
+			return nil, ctx.Err()
+		}
+	}
+
+	if retries > 0 {
+		log.Debugf("RetryTransport: %s %s needed %d retries", request.Method, request.URL, retries)
+	}
+	setRetryCount(ctx, retries)
+
+	return response, err
+}
+
+// cancelOnCloseBody is the body of a response which cancels the context of its attempt
+// when it is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer.
+func (body *cancelOnCloseBody) Close() error {
+	var (
+		err error
+	)
+
+	err = body.ReadCloser.Close()
+	body.cancel()
+
+	return err
+}
+
+func setRetryCount(ctx context.Context, retries int) {
+	if count, ok := ctx.Value(retryCountKey{}).(*int); ok {
+		*count = retries
+	}
+}
+
+// shouldRetry returns true when the attempt failed in a way which another attempt may fix.
+// Only a rate limited call is retried when the method is not idempotent, since in any
+// other case the first attempt may have already done something.
+func (transport *RetryTransport) shouldRetry(request *gohttp.Request, response *gohttp.Response, err error) bool {
+	if request.Context().Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return isIdempotent(request.Method)
+	}
+
+	if response.StatusCode == gohttp.StatusTooManyRequests {
+		return true
+	}
+
+	return retryableStatusCodes[response.StatusCode] && isIdempotent(request.Method)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case gohttp.MethodGet, gohttp.MethodHead, gohttp.MethodOptions, gohttp.MethodPut, gohttp.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// backoff returns how long to wait before the next attempt.  It is the Retry-After of
+// the response if there is one, up to MaxRetryAfter, otherwise between half and all of
+// an exponentially growing delay.
+func (policy RetryPolicy) backoff(retries int, response *gohttp.Response) time.Duration {
+	var (
+		delay time.Duration
+	)
+
+	if response != nil {
+		delay = retryAfter(response.Header.Get("Retry-After"))
+		if delay > 0 {
+			return min(delay, policy.MaxRetryAfter)
+		}
+	}
+
+	delay = policy.BaseDelay << retries
+	if delay > policy.MaxDelay || delay <= 0 {
+		delay = policy.MaxDelay
+	}
+
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// retryAfter parses a Retry-After header, which is either a number of seconds or a date.
+func retryAfter(value string) time.Duration {
+	var (
+		seconds int
+		date    time.Time
+		err     error
+	)
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	seconds, err = strconv.Atoi(value)
+	if err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	date, err = gohttp.ParseTime(value)
+	if err == nil {
+		return time.Until(date)
+	}
+
+	return 0
+}
+
+// cloudHTTPClient returns the HTTP client which the IBM Cloud SDKs use to call a service,
+// one of serviceEndpointNames.  It retries the transient errors, with -trace records the
+// calls and, with -record or -replay, goes through the cassette.
+func cloudHTTPClient(service string) *gohttp.Client {
+	var (
+		next gohttp.RoundTripper = gohttp.DefaultTransport
+	)
+
+	if cassette != nil {
+		next = cassette
+	}
+
+	next = &RetryTransport{
+		next:   next,
+		policy: retryPolicy,
+	}
+
+	if tracer != nil {
+		next = &TraceTransport{
+			next:    next,
+			service: service,
+			tracer:  tracer,
+		}
+	}
+
+	return &gohttp.Client{
+		Transport: next,
+	}
+}
+
+// useCloudHTTPClient makes an IBM Cloud SDK service use cloudHTTPClient.
+func useCloudHTTPClient(name string, service *core.BaseService) {
+	service.SetHTTPClient(cloudHTTPClient(name))
+}
@@ -0,0 +1,333 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	gohttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	// Retries quickly, so that the tests do not wait.
+	testRetryPolicy = RetryPolicy{
+		MaxRetries:     3,
+		BaseDelay:      time.Millisecond,
+		MaxDelay:       5 * time.Millisecond,
+		MaxRetryAfter:  time.Minute,
+		AttemptTimeout: 5 * time.Second,
+	}
+)
+
+// testRetryServer answers every request with the next of its handlers, and with the
+// last one once they are used up.  It records the bodies of the requests.
+type testRetryServer struct {
+	mutex    sync.Mutex
+	handlers []gohttp.HandlerFunc
+	bodies   []string
+}
+
+func newTestRetryServer(t *testing.T, handlers ...gohttp.HandlerFunc) (*testRetryServer, *httptest.Server) {
+	var (
+		trs = &testRetryServer{handlers: handlers}
+	)
+
+	server := httptest.NewServer(gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		trs.mutex.Lock()
+		trs.bodies = append(trs.bodies, string(body))
+		handler := trs.handlers[min(len(trs.bodies), len(trs.handlers))-1]
+		trs.mutex.Unlock()
+
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return trs, server
+}
+
+// attempts returns how many requests the server received.
+func (trs *testRetryServer) attempts() int {
+	trs.mutex.Lock()
+	defer trs.mutex.Unlock()
+
+	return len(trs.bodies)
+}
+
+func respondWith(status int, header ...string) gohttp.HandlerFunc {
+	return func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		for i := 0; i+1 < len(header); i += 2 {
+			w.Header().Set(header[i], header[i+1])
+		}
+		w.WriteHeader(status)
+	}
+}
+
+// doTestRequest makes a request through a RetryTransport and returns the status code and
+// the number of retries which the transport reported.
+func doTestRequest(t *testing.T, ctx context.Context, policy RetryPolicy, method string, url string, body string) (int, int, error) {
+	var (
+		client = &gohttp.Client{
+			Transport: &RetryTransport{
+				next:   gohttp.DefaultTransport,
+				policy: policy,
+			},
+		}
+		bodyReader io.Reader
+		retries    = -1
+	)
+
+	t.Helper()
+
+	if body != "" {
+		bodyReader = strings.NewReader(body)
+	}
+
+	request, err := gohttp.NewRequestWithContext(context.WithValue(ctx, retryCountKey{}, &retries), method, url, bodyReader)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, retries, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	return response.StatusCode, retries, nil
+}
+
+func TestRetryTransportStatusCodes(t *testing.T) {
+	tests := []struct {
+		status       int
+		wantAttempts int
+	}{
+		{gohttp.StatusTooManyRequests, 2},
+		{gohttp.StatusInternalServerError, 2},
+		{gohttp.StatusBadGateway, 2},
+		{gohttp.StatusServiceUnavailable, 2},
+		{gohttp.StatusGatewayTimeout, 2},
+		{gohttp.StatusBadRequest, 1},
+		{gohttp.StatusUnauthorized, 1},
+		{gohttp.StatusNotFound, 1},
+		{gohttp.StatusNotImplemented, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(gohttp.StatusText(tt.status), func(t *testing.T) {
+			trs, server := newTestRetryServer(t, respondWith(tt.status), respondWith(gohttp.StatusOK))
+
+			status, retries, err := doTestRequest(t, context.Background(), testRetryPolicy, gohttp.MethodGet, server.URL, "")
+			if err != nil {
+				t.Fatalf("GET: %v", err)
+			}
+
+			wantStatus := gohttp.StatusOK
+			if tt.wantAttempts == 1 {
+				wantStatus = tt.status
+			}
+			if status != wantStatus || trs.attempts() != tt.wantAttempts || retries != tt.wantAttempts-1 {
+				t.Errorf("GET = %d after %d attempts and %d retries, want %d after %d attempts", status, trs.attempts(), retries, wantStatus, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryTransportGivesUp(t *testing.T) {
+	trs, server := newTestRetryServer(t, respondWith(gohttp.StatusServiceUnavailable))
+
+	status, retries, err := doTestRequest(t, context.Background(), testRetryPolicy, gohttp.MethodGet, server.URL, "")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+
+	if status != gohttp.StatusServiceUnavailable || trs.attempts() != testRetryPolicy.MaxRetries+1 || retries != testRetryPolicy.MaxRetries {
+		t.Errorf("GET = %d after %d attempts and %d retries, want %d after %d attempts", status, trs.attempts(), retries, gohttp.StatusServiceUnavailable, testRetryPolicy.MaxRetries+1)
+	}
+}
+
+func TestRetryTransportNotIdempotent(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		status       int
+		wantAttempts int
+	}{
+		{"POST rate limited", gohttp.MethodPost, gohttp.StatusTooManyRequests, 2},
+		{"POST server error", gohttp.MethodPost, gohttp.StatusInternalServerError, 1},
+		{"POST unavailable", gohttp.MethodPost, gohttp.StatusServiceUnavailable, 1},
+		{"PATCH unavailable", gohttp.MethodPatch, gohttp.StatusServiceUnavailable, 1},
+		{"PUT unavailable", gohttp.MethodPut, gohttp.StatusServiceUnavailable, 2},
+		{"DELETE unavailable", gohttp.MethodDelete, gohttp.StatusServiceUnavailable, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trs, server := newTestRetryServer(t, respondWith(tt.status), respondWith(gohttp.StatusOK))
+
+			_, _, err := doTestRequest(t, context.Background(), testRetryPolicy, tt.method, server.URL, `{"name": "test"}`)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.method, err)
+			}
+
+			if trs.attempts() != tt.wantAttempts {
+				t.Errorf("%s made %d attempts, want %d", tt.method, trs.attempts(), tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryTransportReplaysBody(t *testing.T) {
+	var (
+		body = `{"name": "test", "size": 10}`
+	)
+
+	trs, server := newTestRetryServer(t, respondWith(gohttp.StatusTooManyRequests), respondWith(gohttp.StatusTooManyRequests), respondWith(gohttp.StatusCreated))
+
+	status, _, err := doTestRequest(t, context.Background(), testRetryPolicy, gohttp.MethodPost, server.URL, body)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	if status != gohttp.StatusCreated || trs.attempts() != 3 {
+		t.Fatalf("POST = %d after %d attempts, want %d after 3", status, trs.attempts(), gohttp.StatusCreated)
+	}
+
+	for i, got := range trs.bodies {
+		if got != body {
+			t.Errorf("attempt %d sent the body %q, want %q", i+1, got, body)
+		}
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	var (
+		policy = RetryPolicy{
+			BaseDelay:     100 * time.Millisecond,
+			MaxDelay:      time.Second,
+			MaxRetryAfter: time.Minute,
+		}
+	)
+
+	tests := []struct {
+		name       string
+		retries    int
+		retryAfter string
+		wantMin    time.Duration
+		wantMax    time.Duration
+	}{
+		{"first retry", 0, "", 50 * time.Millisecond, 100 * time.Millisecond},
+		{"third retry", 2, "", 200 * time.Millisecond, 400 * time.Millisecond},
+		{"capped at MaxDelay", 10, "", 500 * time.Millisecond, time.Second},
+		{"Retry-After seconds", 0, "7", 7 * time.Second, 7 * time.Second},
+		{"Retry-After date", 0, time.Now().Add(30 * time.Second).UTC().Format(gohttp.TimeFormat), 28 * time.Second, 30 * time.Second},
+		{"Retry-After capped at MaxRetryAfter", 0, "3600", time.Minute, time.Minute},
+		{"Retry-After date capped at MaxRetryAfter", 0, time.Now().Add(time.Hour).UTC().Format(gohttp.TimeFormat), time.Minute, time.Minute},
+		{"Retry-After in the past", 0, time.Now().Add(-time.Hour).UTC().Format(gohttp.TimeFormat), 50 * time.Millisecond, 100 * time.Millisecond},
+		{"Retry-After not valid", 0, "soon", 50 * time.Millisecond, 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &gohttp.Response{Header: gohttp.Header{}}
+			if tt.retryAfter != "" {
+				response.Header.Set("Retry-After", tt.retryAfter)
+			}
+
+			// The delay is jittered, so try it a few times.
+			for i := 0; i < 20; i++ {
+				wait := policy.backoff(tt.retries, response)
+				if wait < tt.wantMin || wait > tt.wantMax {
+					t.Fatalf("backoff(%d, Retry-After %q) = %v, want between %v and %v", tt.retries, tt.retryAfter, wait, tt.wantMin, tt.wantMax)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransportRetryAfter(t *testing.T) {
+	trs, server := newTestRetryServer(t, respondWith(gohttp.StatusTooManyRequests, "Retry-After", "1"), respondWith(gohttp.StatusOK))
+
+	start := time.Now()
+	status, _, err := doTestRequest(t, context.Background(), testRetryPolicy, gohttp.MethodGet, server.URL, "")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+
+	if elapsed := time.Since(start); status != gohttp.StatusOK || trs.attempts() != 2 || elapsed < time.Second {
+		t.Errorf("GET = %d after %d attempts in %v, want %d after 2 attempts in at least 1s", status, trs.attempts(), elapsed, gohttp.StatusOK)
+	}
+}
+
+func TestRetryTransportDeadline(t *testing.T) {
+	trs, server := newTestRetryServer(t, respondWith(gohttp.StatusServiceUnavailable, "Retry-After", "10"), respondWith(gohttp.StatusOK))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// The next attempt could not start before the deadline, so the transport returns the
+	// response it has instead of waiting.
+	start := time.Now()
+	status, retries, err := doTestRequest(t, ctx, testRetryPolicy, gohttp.MethodGet, server.URL, "")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+
+	if elapsed := time.Since(start); status != gohttp.StatusServiceUnavailable || trs.attempts() != 1 || retries != 0 || elapsed >= time.Second {
+		t.Errorf("GET = %d after %d attempts and %d retries in %v, want %d after 1 attempt before the deadline", status, trs.attempts(), retries, elapsed, gohttp.StatusServiceUnavailable)
+	}
+}
+
+func TestRetryTransportAttemptTimeout(t *testing.T) {
+	var (
+		policy = testRetryPolicy
+	)
+
+	policy.AttemptTimeout = 50 * time.Millisecond
+
+	// The first attempt hangs until it is given up.
+	hang := func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}
+	trs, server := newTestRetryServer(t, hang, respondWith(gohttp.StatusOK))
+
+	start := time.Now()
+	status, retries, err := doTestRequest(t, context.Background(), policy, gohttp.MethodGet, server.URL, "")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+
+	if elapsed := time.Since(start); status != gohttp.StatusOK || trs.attempts() != 2 || retries != 1 || elapsed >= 5*time.Second {
+		t.Errorf("GET = %d after %d attempts and %d retries in %v, want %d after 2 attempts", status, trs.attempts(), retries, elapsed, gohttp.StatusOK)
+	}
+
+	// A POST which hangs may have done something, so it is not retried.
+	trs, server = newTestRetryServer(t, hang, respondWith(gohttp.StatusOK))
+
+	_, _, err = doTestRequest(t, context.Background(), policy, gohttp.MethodPost, server.URL, "{}")
+	if !errors.Is(err, context.DeadlineExceeded) || trs.attempts() != 1 {
+		t.Errorf("POST returned %v after %d attempts, want %v after 1", err, trs.attempts(), context.DeadlineExceeded)
+	}
+}
@@ -15,22 +15,25 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"sync"
 )
 
 type RunnableObject interface {
 	CRN() (string, error)
 	Name() (string, error)
 	ObjectName() (string, error)
-	Run() error
-	CiStatus(shouldClean bool)
-	ClusterStatus()
-	Priority() (int, error)
+	Run(ctx context.Context) error
+	CiStatus(ctx context.Context, shouldClean bool) *ObjectResult
+	ClusterStatus(ctx context.Context) *ObjectResult
 }
 
-type NewRunnableObject func(*Services) (RunnableObject, error)
-type NewRunnableObjects func(*Services) ([]RunnableObject, []error)
+type NewRunnableObject func(context.Context, *Services) (RunnableObject, error)
+type NewRunnableObjects func(context.Context, *Services) ([]RunnableObject, []error)
 
 type NewRunnableObjectEntry struct {
 	NRO  NewRunnableObject
@@ -42,66 +45,302 @@ type NewRunnableObjectsEntry struct {
 	Name string
 }
 
-func BubbleSort(input []RunnableObject) []RunnableObject {
-	priority := func(ro RunnableObject) int {
-		p, e := ro.Priority()
-		if e != nil {
-			return -1
-		}
-		return p
-	}
-	swapped := true
-	// While we have swapped at least one element...
-	for swapped {
-		swapped = false
-		for i := 1; i < len(input); i++ {
-			// Does the next element have a higher priority than this element?
-			if priority(input[i]) > priority(input[i-1]) {
-				// Then swap them!
-				input[i], input[i-1] = input[i-1], input[i]
-				swapped = true
+// sortRunnableObjects orders the objects so that every object comes after the objects it
+// depends on.  Objects which do not depend on each other keep their original order.
+func sortRunnableObjects(robjs []RunnableObject) ([]RunnableObject, error) {
+	var (
+		indicesByName  = make(map[string][]int)
+		inDegree       = make([]int, len(robjs))
+		dependents     = make([][]int, len(robjs))
+		done           = make([]bool, len(robjs))
+		sorted         = make([]RunnableObject, 0, len(robjs))
+		robjObjectName string
+		remaining      []string
+		next           int
+		err            error
+	)
+
+	for i, robj := range robjs {
+		robjObjectName, err = robj.ObjectName()
+		if err != nil {
+			return nil, fmt.Errorf("Error: Could not figure out the objects' name! (%s)\n", err)
+		}
+		indicesByName[robjObjectName] = append(indicesByName[robjObjectName], i)
+	}
+
+	// Build the edges of the graph.  A dependency on an object kind which
+	// is not being checked by this command is ignored.
+	for i, robj := range robjs {
+		for _, dependency := range dependenciesOf(robj) {
+			for _, j := range indicesByName[dependency] {
+				dependents[j] = append(dependents[j], i)
+				inDegree[i]++
 			}
 		}
 	}
-	return input
+
+	for len(sorted) < len(robjs) {
+		// Pick the first object, in the original order, which has no unsorted dependencies.
+		next = -1
+		for i := range robjs {
+			if !done[i] && inDegree[i] == 0 {
+				next = i
+				break
+			}
+		}
+
+		if next == -1 {
+			for i, robj := range robjs {
+				if !done[i] {
+					robjObjectName, _ = robj.ObjectName()
+					remaining = append(remaining, robjObjectName)
+				}
+			}
+			return nil, fmt.Errorf("Error: There is a dependency cycle between %s", strings.Join(remaining, ", "))
+		}
+
+		done[next] = true
+		sorted = append(sorted, robjs[next])
+
+		for _, i := range dependents[next] {
+			inDegree[i]--
+		}
+	}
+
+	return sorted, nil
 }
 
-func initializeRunnableObjects(services *Services, robjsFuncs []NewRunnableObjectsEntry) ([]RunnableObject, error) {
+// failedDependency returns the name of a dependency which could not be queried, or
+// for which every object could not be found or was itself skipped.  A dependency
+// which exists but failed some of its checks does not stop its dependents.
+func failedDependency(robj RunnableObject, resultsByName map[string][]*ObjectResult, undiscovered map[string]bool) string {
 	var (
-		robjsResult    []RunnableObject
-		errs           []error
+		results []*ObjectResult
+		failed  bool
+	)
+
+	for _, dependency := range dependenciesOf(robj) {
+		if undiscovered[dependency] {
+			return dependency
+		}
+
+		results = resultsByName[dependency]
+		if len(results) == 0 {
+			continue
+		}
+
+		failed = true
+		for _, result := range results {
+			if !isMissingResult(result) {
+				failed = false
+			}
+		}
+
+		if failed {
+			return dependency
+		}
+	}
+
+	return ""
+}
+
+// isMissingResult returns true if the object of the result could not be found, or
+// was itself skipped because one of its dependencies is missing.
+func isMissingResult(result *ObjectResult) bool {
+	for _, check := range result.Checks {
+		switch {
+		case check.Status == CheckStatusError && strings.HasSuffix(check.ID, ".exists"):
+			return true
+		case check.Status == CheckStatusSkipped && check.ID == "dependency":
+			return true
+		}
+	}
+
+	return false
+}
+
+// runConcurrently calls fn for every index from 0 to count-1 with at most numWorkers
+// calls running at the same time.  fn(i) is only called after fn has returned for
+// every index in waitFor(i).  waitFor may be nil.  Once ctx is cancelled, fn is
+// not called for the indices which have not started yet.
+func runConcurrently(ctx context.Context, count int, waitFor func(int) []int, fn func(int)) {
+	var (
+		workers   = numWorkers
+		semaphore chan struct{}
+		done      = make([]chan struct{}, count)
+		wg        sync.WaitGroup
+	)
+
+	if workers < 1 {
+		workers = 1
+	}
+	semaphore = make(chan struct{}, workers)
+
+	for i := 0; i < count; i++ {
+		done[i] = make(chan struct{})
+	}
+
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			defer close(done[i])
+
+			// Wait for the prerequisites without holding a worker.
+			if waitFor != nil {
+				for _, j := range waitFor(i) {
+					select {
+					case <-done[j]:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-semaphore }()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			fn(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// checkRunnableObjects calls status on each of the sorted objects.  An object is
+// skipped when one of its dependencies is missing or is one of the discoveryErrs.
+// Independent objects are checked concurrently but the results are returned in the
+// order of the objects.  Objects which were not checked before ctx was cancelled have
+// a cancelled result.
+func checkRunnableObjects(ctx context.Context, robjs []RunnableObject, discoveryErrs []error, status func(context.Context, RunnableObject) *ObjectResult) []*ObjectResult {
+	var (
+		indicesByName = make(map[string][]int)
+		dependencies  = make([][]int, len(robjs))
+		results       = make([]*ObjectResult, len(robjs))
+		undiscovered  = make(map[string]bool)
+		discoveryErr  *DiscoveryError
+	)
+
+	for _, err := range discoveryErrs {
+		if errors.As(err, &discoveryErr) {
+			undiscovered[discoveryErr.ObjectName] = true
+		}
+	}
+
+	for i, robj := range robjs {
+		robjObjectName, _ := robj.ObjectName()
+		indicesByName[robjObjectName] = append(indicesByName[robjObjectName], i)
+	}
+
+	// The objects are sorted, so the dependencies of an object always have a lower index.
+	for i, robj := range robjs {
+		for _, dependency := range dependenciesOf(robj) {
+			for _, j := range indicesByName[dependency] {
+				if j < i {
+					dependencies[i] = append(dependencies[i], j)
+				}
+			}
+		}
+	}
+
+	runConcurrently(ctx, len(robjs),
+		func(i int) []int {
+			return dependencies[i]
+		},
+		func(i int) {
+			var (
+				robj          = robjs[i]
+				resultsByName = make(map[string][]*ObjectResult)
+				dependency    string
+			)
+
+			robjObjectName, _ := robj.ObjectName()
+
+			for _, j := range dependencies[i] {
+				if results[j] == nil {
+					continue
+				}
+				resultsByName[results[j].ObjectType] = append(resultsByName[results[j].ObjectType], results[j])
+			}
+
+			dependency = failedDependency(robj, resultsByName, undiscovered)
+			if dependency == "" {
+				results[i] = status(ctx, robj)
+			} else {
+				log.Debugf("checkRunnableObjects: skipping %s because %s failed", robjObjectName, dependency)
+				results[i] = NewSkippedResult(robj, dependency)
+			}
+		})
+
+	for i, robj := range robjs {
+		if results[i] == nil {
+			results[i] = NewCancelledResult(robj)
+		}
+	}
+
+	return results
+}
+
+// DiscoveryError is returned for the objects of a kind which could not be queried.
+type DiscoveryError struct {
+	ObjectName string
+	Err        error
+}
+
+func (e *DiscoveryError) Error() string {
+	return fmt.Sprintf("Could not create a %s object (%v)", e.ObjectName, e.Err)
+}
+
+func (e *DiscoveryError) Unwrap() error {
+	return e.Err
+}
+
+// initializeRunnableObjects queries and runs the objects.  Objects which could not be
+// queried are returned as discovery errors, anything else stops the work.
+func initializeRunnableObjects(ctx context.Context, services *Services, robjsFuncs []NewRunnableObjectsEntry) ([]RunnableObject, []error, error) {
+	var (
+		robjsResults   = make([][]RunnableObject, len(robjsFuncs))
+		errsResults    = make([][]error, len(robjsFuncs))
+		runErrs        []error
+		discoveryErrs  = make([]error, 0)
 		robjObjectName string
 		crnName        string
 		robjsCluster   = make([]RunnableObject, 0, 5)
 		err            error
 	)
 
-	// Loop through New functions which return an array of runnable objects.
-	for _, nroe := range robjsFuncs {
-		fmt.Fprintf(os.Stderr, "Querying the %s...\n", nroe.Name)
+	// Call the New functions, which return an array of runnable objects, concurrently.
+	runConcurrently(ctx, len(robjsFuncs), nil, func(i int) {
+		fmt.Fprintf(os.Stderr, "Querying the %s...\n", robjsFuncs[i].Name)
 
-		// Call the New function.
-		robjsResult, errs = nroe.NRO(services)
+		robjsResults[i], errsResults[i] = robjsFuncs[i].NRO(ctx, services)
+	})
 
+	// Loop through the results in the order of the New functions.
+	for i, nroe := range robjsFuncs {
 		// Loop through the returned errors.
-		for _, err = range errs {
+		for _, err = range errsResults[i] {
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: Could not create a %s object (%v)!\n", nroe.Name, err)
+				discoveryErrs = append(discoveryErrs, &DiscoveryError{ObjectName: nroe.Name, Err: err})
 			}
 		}
 
 		// Loop through the array of returned results.
-		for _, robj := range robjsResult {
+		for _, robj := range robjsResults[i] {
 			// What is the runnable object's name?
 			robjObjectName, err = robj.ObjectName()
 			if err != nil {
-				return nil, fmt.Errorf("Error: Could not figure out the objects' name! (%s)\n", err)
-			}
-
-			// Also make sure the priority is valid.
-			_, err = robj.Priority()
-			if err != nil {
-				return nil, fmt.Errorf("Error: Could not get the priority for %s: %s\n", robjObjectName, err)
+				return nil, nil, fmt.Errorf("Error: Could not figure out the objects' name! (%s)\n", err)
 			}
 
 			// Append the runnable object.
@@ -118,16 +357,30 @@ func initializeRunnableObjects(services *Services, robjsFuncs []NewRunnableObjec
 		}
 	}
 
-	// Run each object.
+	// Order the objects by their dependencies.
+	robjsCluster, err = sortRunnableObjects(robjsCluster)
+	if err != nil {
+		return nil, nil, err
+	}
 	for _, robj := range robjsCluster {
 		robjObjectName, _ = robj.ObjectName()
-		fmt.Fprintf(os.Stderr, "Running the %s...\n", robjObjectName)
+		log.Debugf("Sorted %s %+v", robjObjectName, robj)
+	}
+	fmt.Fprintf(os.Stderr, "Sorted the objects.\n")
+
+	// Run each object.
+	runErrs = make([]error, len(robjsCluster))
+	runConcurrently(ctx, len(robjsCluster), nil, func(i int) {
+		name, _ := robjsCluster[i].ObjectName()
+		fmt.Fprintf(os.Stderr, "Running the %s...\n", name)
 
-		err = robj.Run()
+		runErrs[i] = robjsCluster[i].Run(ctx)
+	})
+	for _, err = range runErrs {
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
-	return robjsCluster, nil
+	return robjsCluster, discoveryErrs, nil
 }
@@ -0,0 +1,465 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testRunnableObject is a RunnableObject of one of the test kinds, whose status has
+// a single check.
+type testRunnableObject struct {
+	objectName string
+	name       string
+	checkID    string
+	status     CheckStatus
+}
+
+func (o *testRunnableObject) CRN() (string, error) {
+	return "", nil
+}
+
+func (o *testRunnableObject) Name() (string, error) {
+	return o.name, nil
+}
+
+func (o *testRunnableObject) ObjectName() (string, error) {
+	return o.objectName, nil
+}
+
+func (o *testRunnableObject) Run(ctx context.Context) error {
+	return nil
+}
+
+func (o *testRunnableObject) CiStatus(ctx context.Context, shouldClean bool) *ObjectResult {
+	return o.ClusterStatus(ctx)
+}
+
+func (o *testRunnableObject) ClusterStatus(ctx context.Context) *ObjectResult {
+	var (
+		result = NewObjectResult(o, o.name)
+	)
+
+	if o.checkID != "" {
+		result.AddCheck(o.checkID, o.status, CheckSeverityCritical, "test check")
+	}
+
+	return result
+}
+
+// registerTestKinds adds object kinds with the dependencies to the registry for the
+// length of the test.  The kinds are named after the keys of dependencies.
+func registerTestKinds(t *testing.T, dependencies map[string][]string) {
+	var (
+		saved = registry
+	)
+
+	t.Cleanup(func() {
+		registry = saved
+	})
+
+	registry = slices.Clone(registry)
+	for i, name := range slices.Sorted(maps.Keys(dependencies)) {
+		RegisterRunnableObject(RunnableObjectRegistration{
+			Name:         name,
+			Key:          name,
+			Dependencies: dependencies[name],
+			Order:        1000 + i,
+		})
+	}
+}
+
+// newTestObjects returns one object for each name, which is "kind" or "kind/name".
+func newTestObjects(names ...string) []RunnableObject {
+	var (
+		robjs = make([]RunnableObject, 0, len(names))
+	)
+
+	for _, name := range names {
+		kind, objName, _ := strings.Cut(name, "/")
+		robjs = append(robjs, &testRunnableObject{objectName: kind, name: objName})
+	}
+
+	return robjs
+}
+
+// testObjectNames returns the names newTestObjects was given for the objects.
+func testObjectNames(robjs []RunnableObject) []string {
+	var (
+		names = make([]string, 0, len(robjs))
+	)
+
+	for _, robj := range robjs {
+		o := robj.(*testRunnableObject)
+		if o.name == "" {
+			names = append(names, o.objectName)
+		} else {
+			names = append(names, o.objectName+"/"+o.name)
+		}
+	}
+
+	return names
+}
+
+func TestSortRunnableObjects(t *testing.T) {
+	registerTestKinds(t, map[string][]string{
+		"test-vpc":   nil,
+		"test-si":    nil,
+		"test-cos":   nil,
+		"test-tg":    {"test-vpc", "test-si"},
+		"test-lb":    {"test-vpc"},
+		"test-dns":   {"test-lb"},
+		"test-loop1": {"test-loop2"},
+		"test-loop2": {"test-loop1"},
+	})
+
+	tests := []struct {
+		name    string
+		objects []string
+		want    []string
+		wantErr string
+	}{
+		{
+			"already sorted",
+			[]string{"test-vpc", "test-si", "test-tg", "test-lb", "test-dns"},
+			[]string{"test-vpc", "test-si", "test-tg", "test-lb", "test-dns"},
+			"",
+		},
+		{
+			"dependencies first, otherwise stable",
+			[]string{"test-dns", "test-tg", "test-lb", "test-cos", "test-si", "test-vpc"},
+			[]string{"test-cos", "test-si", "test-vpc", "test-tg", "test-lb", "test-dns"},
+			"",
+		},
+		{
+			"objects of a kind keep their order",
+			[]string{"test-tg/b", "test-vpc/b", "test-tg/a", "test-vpc/a"},
+			[]string{"test-vpc/b", "test-vpc/a", "test-tg/b", "test-tg/a"},
+			"",
+		},
+		{
+			"dependencies which are not checked",
+			[]string{"test-dns", "test-tg", "test-cos"},
+			[]string{"test-dns", "test-tg", "test-cos"},
+			"",
+		},
+		{
+			"cycle",
+			[]string{"test-vpc", "test-loop1", "test-loop2"},
+			nil,
+			"dependency cycle between test-loop1, test-loop2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted, err := sortRunnableObjects(newTestObjects(tt.objects...))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("sortRunnableObjects() returned %v, want an error with %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sortRunnableObjects() returned %v", err)
+			}
+			if got := testObjectNames(sorted); !slices.Equal(got, tt.want) {
+				t.Errorf("sortRunnableObjects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRunnableObjectsSkipsDependents(t *testing.T) {
+	registerTestKinds(t, map[string][]string{
+		"test-vpc": nil,
+		"test-si":  nil,
+		"test-tg":  {"test-vpc", "test-si"},
+		"test-lb":  {"test-vpc"},
+		"test-dns": {"test-lb"},
+	})
+
+	tests := []struct {
+		name          string
+		objects       []*testRunnableObject
+		discoveryErrs []error
+		wantSkipped   []string
+	}{
+		{
+			"all found",
+			[]*testRunnableObject{
+				{objectName: "test-vpc", checkID: "vpc.exists", status: CheckStatusOK},
+				{objectName: "test-si", checkID: "pvs.exists", status: CheckStatusOK},
+				{objectName: "test-tg"},
+				{objectName: "test-lb"},
+				{objectName: "test-dns"},
+			},
+			nil,
+			nil,
+		},
+		{
+			"a failed listing is not missing",
+			[]*testRunnableObject{
+				{objectName: "test-vpc"},
+				{objectName: "test-si", checkID: "pvs.dhcp", status: CheckStatusError},
+				{objectName: "test-tg"},
+			},
+			nil,
+			nil,
+		},
+		{
+			"missing dependencies skip their dependents",
+			[]*testRunnableObject{
+				{objectName: "test-vpc", checkID: "vpc.exists", status: CheckStatusError},
+				{objectName: "test-si"},
+				{objectName: "test-tg"},
+				{objectName: "test-lb"},
+				{objectName: "test-dns"},
+			},
+			nil,
+			[]string{"test-tg", "test-lb", "test-dns"},
+		},
+		{
+			"one of the objects of a kind is missing",
+			[]*testRunnableObject{
+				{objectName: "test-vpc", name: "a", checkID: "vpc.exists", status: CheckStatusError},
+				{objectName: "test-vpc", name: "b"},
+				{objectName: "test-lb"},
+			},
+			nil,
+			nil,
+		},
+		{
+			"undiscovered dependencies skip their dependents",
+			[]*testRunnableObject{
+				{objectName: "test-vpc"},
+				{objectName: "test-tg"},
+				{objectName: "test-lb"},
+			},
+			[]error{&DiscoveryError{ObjectName: "test-si", Err: errors.New("test error")}},
+			[]string{"test-tg"},
+		},
+		{
+			"dependencies which are not checked",
+			[]*testRunnableObject{
+				{objectName: "test-tg"},
+				{objectName: "test-dns"},
+			},
+			nil,
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				robjs   = make([]RunnableObject, 0, len(tt.objects))
+				skipped []string
+			)
+
+			for _, o := range tt.objects {
+				robjs = append(robjs, o)
+			}
+
+			results := checkRunnableObjects(context.Background(), robjs, tt.discoveryErrs, func(ctx context.Context, robj RunnableObject) *ObjectResult {
+				return robj.ClusterStatus(ctx)
+			})
+			if len(results) != len(robjs) {
+				t.Fatalf("checkRunnableObjects() returned %d results, want %d", len(results), len(robjs))
+			}
+
+			for _, result := range results {
+				if hasCheck(result, "dependency", CheckStatusSkipped) {
+					skipped = append(skipped, result.ObjectType)
+				}
+			}
+			if !slices.Equal(skipped, tt.wantSkipped) {
+				t.Errorf("checkRunnableObjects() skipped %v, want %v%s", skipped, tt.wantSkipped, dumpResults(results))
+			}
+		})
+	}
+}
+
+// dumpResults returns the checks of the results, for the failure messages.
+func dumpResults(results []*ObjectResult) string {
+	var (
+		dump string
+	)
+
+	for _, result := range results {
+		dump += fmt.Sprintf("\n%s %s:%s", result.ObjectType, result.Status, dumpChecks(result))
+	}
+
+	return dump
+}
+
+// setTestWorkers sets -workers for the length of the test.
+func setTestWorkers(t *testing.T, workers int) {
+	var (
+		saved = numWorkers
+	)
+
+	t.Cleanup(func() {
+		numWorkers = saved
+	})
+
+	numWorkers = workers
+}
+
+func TestRunConcurrentlyLimitsWorkers(t *testing.T) {
+	var (
+		mutex   sync.Mutex
+		active  int
+		maximum int
+		called  = make([]bool, 20)
+	)
+
+	setTestWorkers(t, 3)
+
+	runConcurrently(context.Background(), len(called), nil, func(i int) {
+		mutex.Lock()
+		active++
+		maximum = max(maximum, active)
+		called[i] = true
+		mutex.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mutex.Lock()
+		active--
+		mutex.Unlock()
+	})
+
+	if maximum > 3 {
+		t.Errorf("runConcurrently() ran %d calls at the same time, want at most 3", maximum)
+	}
+	for i, ok := range called {
+		if !ok {
+			t.Errorf("runConcurrently() did not call fn(%d)", i)
+		}
+	}
+}
+
+func TestRunConcurrentlyWaitsForDependencies(t *testing.T) {
+	var (
+		// 0 and 1 are independent, 2 waits for both, 3 waits for 2 and 4 for 0.
+		waitFor = [][]int{nil, nil, {0, 1}, {2}, {0}}
+		mutex   sync.Mutex
+		ended   = make([]bool, len(waitFor))
+	)
+
+	setTestWorkers(t, 4)
+
+	runConcurrently(context.Background(), len(waitFor),
+		func(i int) []int {
+			return waitFor[i]
+		},
+		func(i int) {
+			// Give the calls which do not wait a chance to overtake the ones they wait for.
+			time.Sleep(time.Duration(len(waitFor)-i) * time.Millisecond)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			for _, j := range waitFor[i] {
+				if !ended[j] {
+					t.Errorf("runConcurrently() called fn(%d) before fn(%d) returned", i, j)
+				}
+			}
+			ended[i] = true
+		})
+
+	for i, ok := range ended {
+		if !ok {
+			t.Errorf("runConcurrently() did not call fn(%d)", i)
+		}
+	}
+}
+
+func TestRunConcurrentlyCancelled(t *testing.T) {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		mutex       sync.Mutex
+		called      []int
+	)
+
+	defer cancel()
+
+	setTestWorkers(t, 2)
+
+	// Chain the calls so that the order is known, and cancel in the third.
+	runConcurrently(ctx, 6,
+		func(i int) []int {
+			if i == 0 {
+				return nil
+			}
+			return []int{i - 1}
+		},
+		func(i int) {
+			mutex.Lock()
+			called = append(called, i)
+			mutex.Unlock()
+
+			if i == 2 {
+				cancel()
+			}
+		})
+
+	if !slices.Equal(called, []int{0, 1, 2}) {
+		t.Errorf("runConcurrently() called fn for %v, want [0 1 2]", called)
+	}
+}
+
+func TestCheckRunnableObjectsCancelled(t *testing.T) {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		robjs       = newTestObjects("test-a", "test-b", "test-c", "test-d")
+	)
+
+	defer cancel()
+
+	registerTestKinds(t, map[string][]string{
+		"test-a": nil,
+		"test-b": {"test-a"},
+		"test-c": {"test-b"},
+		"test-d": {"test-c"},
+	})
+	setTestWorkers(t, 4)
+
+	results := checkRunnableObjects(ctx, robjs, nil, func(ctx context.Context, robj RunnableObject) *ObjectResult {
+		result := robj.ClusterStatus(ctx)
+		if result.ObjectType == "test-b" {
+			cancel()
+		}
+		return result
+	})
+
+	for i, wantCancelled := range []bool{false, false, true, true} {
+		if results[i] == nil {
+			t.Fatalf("checkRunnableObjects() returned no result for object %d", i)
+		}
+		if got := hasCheck(results[i], "cancelled", CheckStatusSkipped); got != wantCancelled {
+			t.Errorf("%s cancelled = %v, want %v%s", results[i].ObjectType, got, wantCancelled, dumpChecks(results[i]))
+		}
+	}
+}
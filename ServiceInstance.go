@@ -16,10 +16,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	gohttp "net/http"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/IBM/go-sdk-core/v5/core"
 
@@ -32,6 +35,10 @@ import (
 	"github.com/IBM-Cloud/power-go-client/power/models"
 	// https://github.com/IBM-Cloud/power-go-client/tree/master/power/models
 	// https://raw.githubusercontent.com/IBM-Cloud/power-go-client/refs/heads/master/power/models/p_vm_instance.go
+
+	httptransport "github.com/go-openapi/runtime/client"
+
+	"k8s.io/utils/ptr"
 )
 
 const (
@@ -43,6 +50,16 @@ const (
 	siObjectName = "Power Service Instance"
 )
 
+func init() {
+	RegisterRunnableObject(RunnableObjectRegistration{
+		Name:  siObjectName,
+		Key:   "pvs",
+		New:   NewServiceInstance,
+		Modes: []RunMode{RunModeCI, RunModeCreate, RunModeJumpbox},
+		Order: 50,
+	})
+}
+
 type ServiceInstance struct {
 	name           string
 	dhcpName       string
@@ -52,27 +69,28 @@ type ServiceInstance struct {
 	services       *Services
 	innerSi        *resourcecontrollerv2.ResourceInstance
 	piSession      *ibmpisession.IBMPISession
-	networkClient  *instance.IBMPINetworkClient
+	networkClient  PINetworkClient
 	innerNetwork   *models.Network
-	keyClient      *instance.IBMPIKeyClient
+	keyClient      PIKeyClient
 	innerSshKey    *models.SSHKey
-	imageClient    *instance.IBMPIImageClient
+	imageClient    PIImageClient
 	stockImageId   string
 	rhcosImageId   string
-	dhcpClient     *instance.IBMPIDhcpClient
+	dhcpClient     PIDhcpClient
 	dhcpServer     *models.DHCPServerDetail
-	instanceClient *instance.IBMPIInstanceClient
-	jobClient      *instance.IBMPIJobClient
+	instanceClient PIInstanceClient
+	volumeClient   PIVolumeClient
+	jobClient      PIJobClient
 }
 
-func NewServiceInstance(services *Services) ([]RunnableObject, []error) {
+func NewServiceInstance(ctx context.Context, services *Services) ([]RunnableObject, []error) {
 	var (
 		asis []*ServiceInstance
 		aros []RunnableObject
 		errs []error
 	)
 
-	asis, errs = innerNewServiceInstance(services)
+	asis, errs = innerNewServiceInstance(ctx, services)
 
 	aros = make([]RunnableObject, len(asis))
 	// Go does not support type converting the entire array.
@@ -84,11 +102,11 @@ func NewServiceInstance(services *Services) ([]RunnableObject, []error) {
 	return aros, errs
 }
 
-func NewServiceInstanceAlt(services *Services) ([]*ServiceInstance, []error) {
-	return innerNewServiceInstance(services)
+func NewServiceInstanceAlt(ctx context.Context, services *Services) ([]*ServiceInstance, []error) {
+	return innerNewServiceInstance(ctx, services)
 }
 
-func innerNewServiceInstance(services *Services) ([]*ServiceInstance, []error) {
+func innerNewServiceInstance(ctx context.Context, services *Services) ([]*ServiceInstance, []error) {
 	var (
 		siName          string
 		infraID         string
@@ -98,8 +116,8 @@ func innerNewServiceInstance(services *Services) ([]*ServiceInstance, []error) {
 		networkName     string
 		resourceGroupID string
 		guid            string
-		controllerSvc   *resourcecontrollerv2.ResourceControllerV2
-		ctx             context.Context
+		controllerSvc   ResourceControllerClient
+		listCtx         context.Context
 		cancel          context.CancelFunc
 		foundInstances  []string
 		si              *ServiceInstance
@@ -140,16 +158,16 @@ func innerNewServiceInstance(services *Services) ([]*ServiceInstance, []error) {
 
 	controllerSvc = services.GetControllerSvc()
 
-	ctx, cancel = services.GetContextWithTimeout()
+	listCtx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	if fUseTagSearch {
-		foundInstances, err = listByTag(TagTypeServiceInstance, services)
+		foundInstances, err = listByTag(listCtx, TagTypeServiceInstance, services)
 	} else {
 		if guid != "" {
-			foundInstances, err = findServiceInstance(controllerSvc, ctx, guid, resourceGroupID)
+			foundInstances, err = findServiceInstance(controllerSvc, listCtx, guid, resourceGroupID)
 		} else {
-			foundInstances, err = findServiceInstance(controllerSvc, ctx, siName, resourceGroupID)
+			foundInstances, err = findServiceInstance(controllerSvc, listCtx, siName, resourceGroupID)
 		}
 	}
 	log.Debugf("NewServiceInstance: foundInstances = %+v, err = %v", foundInstances, err)
@@ -179,9 +197,9 @@ func innerNewServiceInstance(services *Services) ([]*ServiceInstance, []error) {
 
 		log.Debugf("NewServiceInstance: instanceID = %s", instanceID)
 
-		getResourceOptions = controllerSvc.NewGetResourceInstanceOptions(instanceID)
+		getResourceOptions = &resourcecontrollerv2.GetResourceInstanceOptions{ID: &instanceID}
 
-		innerResource, response, err = controllerSvc.GetResourceInstanceWithContext(ctx, getResourceOptions)
+		innerResource, response, err = controllerSvc.GetResourceInstanceWithContext(listCtx, getResourceOptions)
 		if err != nil {
 			err = fmt.Errorf("%s failed to get instance %s: %s: %v", siObjectName, instanceID, response, err)
 		}
@@ -223,7 +241,7 @@ func innerNewServiceInstance(services *Services) ([]*ServiceInstance, []error) {
 		si.name = innerResourceName
 		si.innerSi = innerResource
 
-		err = createClients(si)
+		err = createClients(ctx, si)
 		if err != nil {
 			si.innerSi = nil
 		}
@@ -247,7 +265,7 @@ func innerNewServiceInstance(services *Services) ([]*ServiceInstance, []error) {
 }
 
 // findServiceInstance returns the service instance matching by name in the IBM Cloud.
-func findServiceInstance(controllerSvc *resourcecontrollerv2.ResourceControllerV2, ctx context.Context, name string, resourceGroupID string) ([]string, error) {
+func findServiceInstance(controllerSvc ResourceControllerClient, ctx context.Context, name string, resourceGroupID string) ([]string, error) {
 	var (
 		options   *resourcecontrollerv2.ListResourceInstancesOptions
 		resources *resourcecontrollerv2.ResourceInstancesList
@@ -275,7 +293,7 @@ func findServiceInstance(controllerSvc *resourcecontrollerv2.ResourceControllerV
 		return false
 	}
 
-	options = controllerSvc.NewListResourceInstancesOptions()
+	options = &resourcecontrollerv2.ListResourceInstancesOptions{}
 	// options.SetType("resource_instance")
 	options.SetResourceGroupID(resourceGroupID)
 	options.SetResourceID(virtualServerResourceID)
@@ -340,16 +358,23 @@ func findServiceInstance(controllerSvc *resourcecontrollerv2.ResourceControllerV
 	return []string{}, nil
 }
 
-func createClients(si *ServiceInstance) error {
+// createClients creates the PowerVS clients of the service instance.  The clients make
+// all of their calls with ctx, so it is the context of the command and not a timeout.
+func createClients(ctx context.Context, si *ServiceInstance) error {
 	var (
 		piSession *ibmpisession.IBMPISession
 		err       error
 	)
 
+	if si.services.GetFakeCloud() != nil {
+		si.services.GetFakeCloud().setServiceInstanceClients(ctx, si)
+		return nil
+	}
+
 	if si.piSession == nil {
 		piSession, err = createPiSession(si)
 		if err != nil {
-			log.Fatalf("Error: createPiSession returns %v", err)
+			log.Debugf("Error: createPiSession returns %v", err)
 			return err
 		}
 		log.Debugf("createClients: piSession = %+v", piSession)
@@ -360,7 +385,7 @@ func createClients(si *ServiceInstance) error {
 	}
 
 	if si.networkClient == nil {
-		si.networkClient = instance.NewIBMPINetworkClient(context.Background(), si.piSession, *si.innerSi.GUID)
+		si.networkClient = instance.NewIBMPINetworkClient(ctx, si.piSession, *si.innerSi.GUID)
 		log.Debugf("createClients: networkClient = %v", si.networkClient)
 	}
 	if si.networkClient == nil {
@@ -368,7 +393,7 @@ func createClients(si *ServiceInstance) error {
 	}
 
 	if si.keyClient == nil {
-		si.keyClient = instance.NewIBMPIKeyClient(context.Background(), si.piSession, *si.innerSi.GUID)
+		si.keyClient = instance.NewIBMPIKeyClient(ctx, si.piSession, *si.innerSi.GUID)
 		log.Debugf("createClients: keyClient = %v", si.keyClient)
 	}
 	if si.keyClient == nil {
@@ -376,7 +401,7 @@ func createClients(si *ServiceInstance) error {
 	}
 
 	if si.imageClient == nil {
-		si.imageClient = instance.NewIBMPIImageClient(context.Background(), si.piSession, *si.innerSi.GUID)
+		si.imageClient = instance.NewIBMPIImageClient(ctx, si.piSession, *si.innerSi.GUID)
 		log.Debugf("createClients: imageClient = %v", si.imageClient)
 	}
 	if si.imageClient == nil {
@@ -384,7 +409,7 @@ func createClients(si *ServiceInstance) error {
 	}
 
 	if si.dhcpClient == nil {
-		si.dhcpClient = instance.NewIBMPIDhcpClient(context.Background(), si.piSession, *si.innerSi.GUID)
+		si.dhcpClient = instance.NewIBMPIDhcpClient(ctx, si.piSession, *si.innerSi.GUID)
 		log.Debugf("createClients: dhcpClient = %v", si.dhcpClient)
 	}
 	if si.dhcpClient == nil {
@@ -392,15 +417,23 @@ func createClients(si *ServiceInstance) error {
 	}
 
 	if si.instanceClient == nil {
-		si.instanceClient = instance.NewIBMPIInstanceClient(context.Background(), si.piSession, *si.innerSi.GUID)
+		si.instanceClient = instance.NewIBMPIInstanceClient(ctx, si.piSession, *si.innerSi.GUID)
 		log.Debugf("createClients: instanceClient = %v", si.instanceClient)
 	}
 	if si.instanceClient == nil {
 		return fmt.Errorf("Error: createClients has a nil instanceClient!")
 	}
 
+	if si.volumeClient == nil {
+		si.volumeClient = instance.NewIBMPIVolumeClient(ctx, si.piSession, *si.innerSi.GUID)
+		log.Debugf("createClients: volumeClient = %v", si.volumeClient)
+	}
+	if si.volumeClient == nil {
+		return fmt.Errorf("Error: createClients has a nil volumeClient!")
+	}
+
 	if si.jobClient == nil {
-		si.jobClient = instance.NewIBMPIJobClient(context.Background(), si.piSession, *si.innerSi.GUID)
+		si.jobClient = instance.NewIBMPIJobClient(ctx, si.piSession, *si.innerSi.GUID)
 		log.Debugf("createClients: jobClient = %v", si.jobClient)
 	}
 	if si.jobClient == nil {
@@ -410,10 +443,28 @@ func createClients(si *ServiceInstance) error {
 	return nil
 }
 
+// withClients returns a copy of the service instance whose PowerVS clients make all of
+// their calls with ctx, for example the one of a cleanup step.
+func (si *ServiceInstance) withClients(ctx context.Context) (*ServiceInstance, error) {
+	var (
+		stepSi = *si
+	)
+
+	stepSi.networkClient = nil
+	stepSi.keyClient = nil
+	stepSi.imageClient = nil
+	stepSi.dhcpClient = nil
+	stepSi.instanceClient = nil
+	stepSi.volumeClient = nil
+	stepSi.jobClient = nil
+
+	return &stepSi, createClients(ctx, &stepSi)
+}
+
 func createPiSession(si *ServiceInstance) (*ibmpisession.IBMPISession, error) {
 	var (
 		metadata      *Metadata
-		authenticator *core.IamAuthenticator
+		authenticator core.Authenticator
 		piOptions     *ibmpisession.IBMPIOptions
 		piSession     *ibmpisession.IBMPISession
 		err           error
@@ -421,20 +472,14 @@ func createPiSession(si *ServiceInstance) (*ibmpisession.IBMPISession, error) {
 
 	metadata = si.services.GetMetadata()
 
-	authenticator = &core.IamAuthenticator{
-		ApiKey: si.services.GetApiKey(),
-	}
-	err = authenticator.Validate()
-	if err != nil {
-		return nil, err
-	}
+	authenticator = si.services.GetAuthenticator()
 
 	log.Debugf("createPiSession: region = %+v", metadata.GetRegion())
 	piOptions = &ibmpisession.IBMPIOptions{
 		Authenticator: authenticator,
 		Debug:         false,
 		Region:        metadata.GetRegion(),
-		URL:           fmt.Sprintf("https://%s.power-iaas.cloud.ibm.com", metadata.GetRegion()),
+		URL:           metadata.GetServiceEndpoint("Power", fmt.Sprintf("https://%s.power-iaas.cloud.ibm.com", metadata.GetRegion())),
 		UserAccount:   si.services.GetUser().Account,
 		Zone:          metadata.GetZone(),
 	}
@@ -444,6 +489,13 @@ func createPiSession(si *ServiceInstance) (*ibmpisession.IBMPISession, error) {
 	if err != nil {
 		return nil, fmt.Errorf("Error ibmpisession.New: %v", err)
 	}
+
+	// The PowerVS client does not take an http.Client, so replace the transport it uses.
+	runtime, ok := piSession.Power.Transport.(*httptransport.Runtime)
+	if !ok {
+		return nil, fmt.Errorf("Error: The PowerVS transport is a %T", piSession.Power.Transport)
+	}
+	runtime.Transport = cloudHTTPClient("Power").Transport
 	log.Debugf("createPiSession: piSession = %v", piSession)
 
 	return piSession, nil
@@ -475,7 +527,9 @@ func (si *ServiceInstance) FindDhcpServer() (*models.DHCPServerDetail, error) {
 		if strings.Contains(*dhcpServer.Network.Name, si.dhcpName) {
 			log.Debugf("FindDhcpServer: FOUND %s %s", *dhcpServer.ID, *dhcpServer.Network.Name)
 
-			dhcpServerDetail, err = si.dhcpClient.Get(*dhcpServer.ID)
+			dhcpServerDetail, err = cachedCall(si.services.GetCache(), si.cacheKey("pvs.dhcpServer", *dhcpServer.ID), func() (*models.DHCPServerDetail, error) {
+				return si.dhcpClient.Get(*dhcpServer.ID)
+			})
 			if err != nil {
 				return nil, fmt.Errorf("Error: si.dhcpClient.Get returns %v", err)
 			}
@@ -503,7 +557,7 @@ func (si *ServiceInstance) GetDhcpServers() ([]*models.DHCPServer, error) {
 		return nil, fmt.Errorf("Error: getDhcpServers has nil dhcpClient")
 	}
 
-	dhcpServers, err = si.dhcpClient.GetAll()
+	dhcpServers, err = cachedCall(si.services.GetCache(), si.cacheKey("pvs.dhcpServers"), si.dhcpClient.GetAll)
 	if err != nil {
 		return nil, fmt.Errorf("Error: si.dhcpClient.GetAll returns %v", err)
 	}
@@ -574,9 +628,9 @@ func (si *ServiceInstance) GetImages() ([]*models.ImageReference, error) {
 		return nil, fmt.Errorf("Error: getImages has nil imageClient")
 	}
 
-	images, err = si.imageClient.GetAll()
+	images, err = cachedCall(si.services.GetCache(), si.cacheKey("pvs.images"), si.imageClient.GetAll)
 	if err != nil {
-		log.Fatalf("Error: GetImages: GetAll returns %v", err)
+		log.Debugf("Error: GetImages: GetAll returns %v", err)
 		return nil, err
 	}
 	log.Debugf("GetImages: images = %+v", images)
@@ -591,9 +645,9 @@ func (si *ServiceInstance) FindStockImage(imageName string) (*models.ImageRefere
 		err      error
 	)
 
-	images, err = si.imageClient.GetAllStockImages(false, false)
+	images, err = si.getStockImages()
 	if err != nil {
-		log.Fatalf("Error: FindStockImage: GetAllStockImages returns %v", err)
+		log.Debugf("Error: FindStockImage: GetAllStockImages returns %v", err)
 		return nil, err
 	}
 
@@ -620,9 +674,9 @@ func (si *ServiceInstance) FindStockImages() ([]string, error) {
 		err      error
 	)
 
-	images, err = si.imageClient.GetAllStockImages(false, false)
+	images, err = si.getStockImages()
 	if err != nil {
-		log.Fatalf("Error: FindStockImages: GetAllStockImages returns %v", err)
+		log.Debugf("Error: FindStockImages: GetAllStockImages returns %v", err)
 		return nil, err
 	}
 
@@ -642,6 +696,12 @@ func (si *ServiceInstance) FindStockImages() ([]string, error) {
 	return result, nil
 }
 
+func (si *ServiceInstance) getStockImages() (*models.Images, error) {
+	return cachedCall(si.services.GetCache(), si.cacheKey("pvs.stockImages"), func() (*models.Images, error) {
+		return si.imageClient.GetAllStockImages(false, false)
+	})
+}
+
 func (si *ServiceInstance) FindPVMInstance(pvmInstanceName string) ([]*models.PVMInstance, error) {
 	var (
 		instanceRefs     []*models.PVMInstanceReference
@@ -687,9 +747,11 @@ func (si *ServiceInstance) FindPVMInstance(pvmInstanceName string) ([]*models.PV
 		if matchFunc(instanceRef, pvmInstanceName) {
 			log.Debugf("FindPVMInstance: FOUND %s %s", *instanceRef.ServerName, *instanceRef.PvmInstanceID)
 
-			instance, err = si.instanceClient.Get(*instanceRef.PvmInstanceID)
+			instance, err = cachedCall(si.services.GetCache(), si.cacheKey("pvs.instance", *instanceRef.PvmInstanceID), func() (*models.PVMInstance, error) {
+				return si.instanceClient.Get(*instanceRef.PvmInstanceID)
+			})
 			if err != nil {
-				log.Fatalf("Error: findPVMInstance: GetAll returns %v", err)
+				log.Debugf("Error: findPVMInstance: GetAll returns %v", err)
 				return nil, err
 			}
 
@@ -715,15 +777,60 @@ func (si *ServiceInstance) GetPVMInstances() ([]*models.PVMInstanceReference, er
 		return nil, fmt.Errorf("Error: GetPVMInstances has nil instanceClient")
 	}
 
-	instances, err = si.instanceClient.GetAll()
+	instances, err = cachedCall(si.services.GetCache(), si.cacheKey("pvs.instances"), si.instanceClient.GetAll)
 	if err != nil {
-		log.Fatalf("Error: GetPVMInstances: GetAll returns %v", err)
+		log.Debugf("Error: GetPVMInstances: GetAll returns %v", err)
 		return nil, err
 	}
 
 	return instances.PvmInstances, nil
 }
 
+func (si *ServiceInstance) GetVolumes() ([]*models.VolumeReference, error) {
+	var (
+		volumes *models.Volumes
+		err     error
+	)
+
+	if si.innerSi == nil {
+		return nil, fmt.Errorf("Error: GetVolumes called on nil ServiceInstance")
+	}
+	if si.volumeClient == nil {
+		return nil, fmt.Errorf("Error: GetVolumes has nil volumeClient")
+	}
+
+	volumes, err = cachedCall(si.services.GetCache(), si.cacheKey("pvs.volumes"), si.volumeClient.GetAll)
+	if err != nil {
+		log.Debugf("Error: GetVolumes: GetAll returns %v", err)
+		return nil, err
+	}
+
+	return volumes.Volumes, nil
+}
+
+func (si *ServiceInstance) GetInstanceVolumes(pvmInstanceID string) ([]*models.VolumeReference, error) {
+	var (
+		volumes *models.Volumes
+		err     error
+	)
+
+	if si.innerSi == nil {
+		return nil, fmt.Errorf("Error: GetInstanceVolumes called on nil ServiceInstance")
+	}
+	if si.volumeClient == nil {
+		return nil, fmt.Errorf("Error: GetInstanceVolumes has nil volumeClient")
+	}
+
+	volumes, err = cachedCall(si.services.GetCache(), si.cacheKey("pvs.instanceVolumes", pvmInstanceID), func() (*models.Volumes, error) {
+		return si.volumeClient.GetAllInstanceVolumes(pvmInstanceID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Error: GetInstanceVolumes: si.volumeClient.GetAllInstanceVolumes returns %v", err)
+	}
+
+	return volumes.Volumes, nil
+}
+
 func (si *ServiceInstance) FindSshKey() (*models.SSHKey, error) {
 	var (
 		keys *models.SSHKeys
@@ -740,7 +847,7 @@ func (si *ServiceInstance) FindSshKey() (*models.SSHKey, error) {
 
 	log.Debugf("FindSshKey: si.sshKeyName = %s", si.sshKeyName)
 
-	keys, err = si.keyClient.GetAll()
+	keys, err = cachedCall(si.services.GetCache(), si.cacheKey("pvs.keys"), si.keyClient.GetAll)
 	if err != nil {
 		return nil, fmt.Errorf("Error: FindSshKey: si.keyClient.GetAll returns %v", err)
 	}
@@ -816,7 +923,7 @@ func (si *ServiceInstance) GetNetworks() ([]*models.NetworkReference, error) {
 		return nil, fmt.Errorf("Error: GetNetworks has nil networkClient")
 	}
 
-	networks, err = si.networkClient.GetAll()
+	networks, err = cachedCall(si.services.GetCache(), si.cacheKey("pvs.networks"), si.networkClient.GetAll)
 	if err != nil {
 		return nil, fmt.Errorf("Error: GetNetworks: si.networkClient.GetAll returns %v", err)
 	}
@@ -837,7 +944,9 @@ func (si *ServiceInstance) GetNetworkPorts(networkID string) ([]*models.NetworkP
 		return nil, fmt.Errorf("Error: GetNetworkPorts has nil networkClient")
 	}
 
-	networkPorts, err = si.networkClient.GetAllPorts(networkID)
+	networkPorts, err = cachedCall(si.services.GetCache(), si.cacheKey("pvs.networkPorts", networkID), func() (*models.NetworkPorts, error) {
+		return si.networkClient.GetAllPorts(networkID)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("Error: GetNetworkPorts: si.networkClient.GetAllPorts returns %v", err)
 	}
@@ -866,6 +975,25 @@ func (si *ServiceInstance) GetNetworkInterfaces(networkID string) ([]*models.Net
 	return networkInterfaces.Interfaces, nil
 }
 
+// cacheKey returns the key of a call against this service instance.
+func (si *ServiceInstance) cacheKey(call string, args ...string) string {
+	return cacheKey(call, append([]string{*si.innerSi.GUID}, args...)...)
+}
+
+// invalidateCache forgets the results of the calls against this service instance which
+// list or get the resources of a kind, for example "pvs.instance".
+func (si *ServiceInstance) invalidateCache(calls ...string) {
+	var (
+		prefixes = make([]string, 0, len(calls))
+	)
+
+	for _, call := range calls {
+		prefixes = append(prefixes, call+"("+*si.innerSi.GUID)
+	}
+
+	si.services.GetCache().Invalidate(prefixes...)
+}
+
 func (si *ServiceInstance) GetSshKeyname() string {
 	if si.innerSi == nil {
 		return ""
@@ -894,46 +1022,38 @@ func (si *ServiceInstance) ObjectName() (string, error) {
 	return siObjectName, nil
 }
 
-func (si *ServiceInstance) Run() error {
+func (si *ServiceInstance) Run(ctx context.Context) error {
 	// Nothing needs to be done!
 	return nil
 }
 
-func (si *ServiceInstance) CiStatus(shouldClean bool) {
+func (si *ServiceInstance) CiStatus(ctx context.Context, shouldClean bool) *ObjectResult {
 	var (
-		isOk         = true
+		result       *ObjectResult
 		dhcpServers  []*models.DHCPServer
 		imageRefs    []*models.ImageReference
+		volumes      []*models.VolumeReference
 		networkRefs  []*models.NetworkReference
 		instanceRefs []*models.PVMInstanceReference
 		err          error
 	)
 
+	result = NewObjectResult(si, si.name)
+
 	instanceRefs, err = si.GetPVMInstances()
 	if err != nil {
-		fmt.Printf("%s %s returned this error searching for instances: %v\n", siObjectName, si.name, err)
-		isOk = false
+		result.AddError("pvs.instances", "returned this error searching for instances: %v", err)
 	}
 
 	log.Debugf("CiStatus: instanceRefs = %+v", instanceRefs)
 	if len(instanceRefs) > 0 {
-		fmt.Printf("%s %s is NOTOK. Found %d instances.\n", siObjectName, si.name, len(instanceRefs))
-		isOk = false
-
-		if shouldClean {
-			for _, instanceRef := range instanceRefs {
-				err = si.instanceClient.Delete(*instanceRef.PvmInstanceID)
-				if err != nil {
-					fmt.Printf("%s %s returned this error deleting instance %s: %v\n", siObjectName, si.name, *instanceRef.PvmInstanceID, err)
-				}
-			}
-		}
+		result.AddNotOK("pvs.instances", "Found %d instances.", len(instanceRefs)).
+			WithEvidence("count", fmt.Sprintf("%d", len(instanceRefs)))
 	}
 
 	dhcpServers, err = si.GetDhcpServers()
 	if err != nil {
-		fmt.Printf("%s %s returned this error searching for DHCP servers: %v\n", siObjectName, si.name, err)
-		isOk = false
+		result.AddError("pvs.dhcp", "returned this error searching for DHCP servers: %v", err)
 	}
 
 	log.Debugf("CiStatus: dhcpServers = %+v", dhcpServers)
@@ -943,23 +1063,14 @@ func (si *ServiceInstance) CiStatus(shouldClean bool) {
 			dhcps = append(dhcps, *dhcpServer.Network.Name)
 		}
 
-		fmt.Printf("%s %s is NOTOK. Found %d DHCP servers (%+v).\n", siObjectName, si.name, len(dhcpServers), dhcps)
-		isOk = false
-
-		if shouldClean {
-			for _, dhcpServer := range dhcpServers {
-				err = si.dhcpClient.Delete(*dhcpServer.ID)
-				if err != nil {
-					fmt.Printf("%s %s returned this error deleting DHCP server %s: %v\n", siObjectName, si.name, *dhcpServer.ID, err)
-				}
-			}
-		}
+		result.AddNotOK("pvs.dhcp", "Found %d DHCP servers (%+v).", len(dhcpServers), dhcps).
+			WithEvidence("count", fmt.Sprintf("%d", len(dhcpServers))).
+			WithEvidence("networks", strings.Join(dhcps, ","))
 	}
 
 	imageRefs, err = si.GetImages()
 	if err != nil {
-		fmt.Printf("%s %s returned this error searching for images: %v\n", siObjectName, si.name, err)
-		isOk = false
+		result.AddError("pvs.images", "returned this error searching for images: %v", err)
 	}
 
 	log.Debugf("CiStatus: imageRefs = %+v", imageRefs)
@@ -969,23 +1080,47 @@ func (si *ServiceInstance) CiStatus(shouldClean bool) {
 			images = append(images, *imageRef.Name)
 		}
 
-		fmt.Printf("%s %s is NOTOK. Found %d images (%+v).\n", siObjectName, si.name, len(imageRefs), images)
-		isOk = false
+		result.AddNotOK("pvs.images", "Found %d images (%+v).", len(imageRefs), images).
+			WithEvidence("count", fmt.Sprintf("%d", len(imageRefs))).
+			WithEvidence("images", strings.Join(images, ","))
+	}
 
-		if shouldClean {
-			for _, imageRef := range imageRefs {
-				err = si.imageClient.Delete(*imageRef.ImageID)
-				if err != nil {
-					fmt.Printf("%s %s returned this error deleting image %s: %v\n", siObjectName, si.name, *imageRef.ImageID, err)
-				}
-			}
+	volumes, err = si.GetVolumes()
+	if err != nil {
+		result.AddError("pvs.volumes", "returned this error searching for volumes: %v", err)
+	}
+
+	log.Debugf("CiStatus: volumes = %+v", volumes)
+	leftoverInstances := make(map[string]bool)
+	for _, instanceRef := range instanceRefs {
+		leftoverInstances[*instanceRef.PvmInstanceID] = true
+	}
+	unattached := make([]string, 0)
+	dataVolumes := make([]string, 0)
+	for _, volume := range volumes {
+		switch {
+		case volumeCleanupReason(volume, func(pvmInstanceID string) bool { return leftoverInstances[pvmInstanceID] }) == "":
+		case len(volume.PvmInstanceIDs) == 0:
+			unattached = append(unattached, *volume.Name)
+		default:
+			dataVolumes = append(dataVolumes, *volume.Name)
 		}
 	}
+	if len(unattached) > 0 {
+		result.AddNotOK("pvs.volumes", "Found %d unattached volumes (%+v).", len(unattached), unattached).
+			WithEvidence("count", fmt.Sprintf("%d", len(unattached))).
+			WithEvidence("volumes", strings.Join(unattached, ","))
+	}
+	// They are deleted with the instances by -shouldClean.
+	if len(dataVolumes) > 0 {
+		result.AddNotOK("pvs.volumes", "Found %d data volumes of the leftover instances (%+v).", len(dataVolumes), dataVolumes).
+			WithEvidence("count", fmt.Sprintf("%d", len(dataVolumes))).
+			WithEvidence("volumes", strings.Join(dataVolumes, ","))
+	}
 
 	networkRefs, err = si.GetNetworks()
 	if err != nil {
-		fmt.Printf("%s %s returned this error searching for networks: %v\n", siObjectName, si.name, err)
-		isOk = false
+		result.AddError("pvs.networks", "returned this error searching for networks: %v", err)
 	}
 
 	log.Debugf("CiStatus: networkRefs = %+v", networkRefs)
@@ -995,17 +1130,21 @@ func (si *ServiceInstance) CiStatus(shouldClean bool) {
 			networks = append(networks, *networkRef.Name)
 		}
 
-		fmt.Printf("%s %s is NOTOK. Found %d networks (%+v).\n", siObjectName, si.name, len(networkRefs), networks)
-		isOk = false
+		result.AddNotOK("pvs.networks", "Found %d networks (%+v).", len(networkRefs), networks).
+			WithEvidence("count", fmt.Sprintf("%d", len(networkRefs))).
+			WithEvidence("networks", strings.Join(networks, ","))
 
 		for _, networkRef := range networkRefs {
 			networkPorts, err := si.GetNetworkPorts(*networkRef.NetworkID)
 			if err != nil {
-				fmt.Printf("%s %s returned this error calling GetNetworkPorts(%s) %v\n", siObjectName, si.name, *networkRef.NetworkID, err)
+				result.AddError("pvs.network.ports", "returned this error calling GetNetworkPorts(%s) %v", *networkRef.NetworkID, err).
+					WithEvidence("network", *networkRef.Name)
 				continue
 			}
 
-			fmt.Printf("%s %s Network %s has %d NetworkPorts\n", siObjectName, si.name, *networkRef.Name, len(networkPorts))
+			result.AddOK("pvs.network.ports", "Network %s has %d NetworkPorts", *networkRef.Name, len(networkPorts)).
+				WithEvidence("network", *networkRef.Name).
+				WithEvidence("count", fmt.Sprintf("%d", len(networkPorts)))
 			for _, networkPort := range networkPorts {
 				if networkPort.PvmInstance != nil {
 					var (
@@ -1017,68 +1156,509 @@ func (si *ServiceInstance) CiStatus(shouldClean bool) {
 						serverName = *instancesFound[0].ServerName
 					}
 
-					fmt.Printf("%s %s Found a server instance (%s) on the network\n", siObjectName, si.name, serverName)
-
-					if shouldClean {
-						err = si.instanceClient.Delete(networkPort.PvmInstance.PvmInstanceID)
-						if err != nil {
-							fmt.Printf("%s %s returned this error deleting instance %s: %v\n", siObjectName, si.name, networkPort.PvmInstance.PvmInstanceID, err)
-						}
-					}
+					result.AddNotOK("pvs.network.instance", "Found a server instance (%s) on the network", serverName).
+						WithEvidence("network", *networkRef.Name).
+						WithEvidence("instance", serverName)
 				}
 			}
 		}
+	}
 
-		if shouldClean {
-			for _, networkRef := range networkRefs {
-				err = si.networkClient.Delete(*networkRef.NetworkID)
-				if err != nil {
-					fmt.Printf("%s %s returned this error deleting network %s: %v\n", siObjectName, si.name, *networkRef.NetworkID, err)
+	if shouldClean && ctx.Err() == nil && !result.IsOK() {
+		si.cleanup(ctx, result)
+	}
+
+	if ctx.Err() != nil {
+		result.AddError("pvs.cancelled", "was interrupted before all of the resources were examined: %v", ctx.Err())
+	}
+
+	if result.IsOK() {
+		result.AddOK("pvs.empty", "has no leftover resources.")
+	}
+
+	return result
+}
+
+// cleanup deletes the leftover resources in the order of PlanCleanup, one at a time, and
+// reports what happened to each of them.
+func (si *ServiceInstance) cleanup(ctx context.Context, result *ObjectResult) {
+	var (
+		actions []*CleanupAction
+		outcome CleanupOutcome
+		err     error
+	)
+
+	actions, err = si.PlanCleanup(ctx)
+	if err != nil {
+		result.AddError("cleanup.plan", "returned this error planning the cleanup: %v", err)
+		return
+	}
+
+	for i, action := range actions {
+		action.Step = i + 1
+
+		// Do not start another deletion once the command is interrupted.
+		if ctx.Err() != nil {
+			result.AddCheck("cleanup.delete", CheckStatusSkipped, CheckSeverityInfo, fmt.Sprintf("step %d was not applied because the command was interrupted", action.Step)).
+				WithEvidence(action.Kind, action.ID)
+			continue
+		}
+
+		outcome, err = si.ApplyCleanup(ctx, action)
+		addCleanupOutcome(result, action, outcome, err)
+	}
+}
+
+// PlanCleanup returns the deletions which CiStatus makes with shouldClean, in the order
+// PowerVS allows them: the instances, including the ones only found on the networks, then
+// the volumes which are not attached to anything else, the DHCP servers, the ports which
+// are left on the networks, the networks and the images.
+func (si *ServiceInstance) PlanCleanup(ctx context.Context) ([]*CleanupAction, error) {
+	var (
+		actions       = make([]*CleanupAction, 0)
+		planned       = make(map[string]bool)
+		dhcpServers   []*models.DHCPServer
+		imageRefs     []*models.ImageReference
+		volumes       []*models.VolumeReference
+		networkRefs   []*models.NetworkReference
+		instanceRefs  []*models.PVMInstanceReference
+		networkPorts  []*models.NetworkPort
+		portsToDelete []*CleanupAction
+		reason        string
+		err           error
+	)
+
+	newAction := func(kind string, id string, name string, reason string) *CleanupAction {
+		if planned[kind+"/"+id] {
+			return nil
+		}
+		planned[kind+"/"+id] = true
+
+		return &CleanupAction{
+			ObjectType: siObjectName,
+			Object:     si.name,
+			Kind:       kind,
+			ID:         id,
+			Name:       name,
+			Reason:     reason,
+		}
+	}
+	addAction := func(action *CleanupAction) {
+		if action != nil {
+			actions = append(actions, action)
+		}
+	}
+
+	instanceRefs, err = si.GetPVMInstances()
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not plan the cleanup of the instances of %s (%v)", si.name, err)
+	}
+	for _, instanceRef := range instanceRefs {
+		addAction(newAction(CleanupKindPVMInstance, *instanceRef.PvmInstanceID, *instanceRef.ServerName, "leftover instance"))
+	}
+
+	networkRefs, err = si.GetNetworks()
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not plan the cleanup of the networks of %s (%v)", si.name, err)
+	}
+
+	// A network cannot be deleted while an instance or a port is still on it.
+	for _, networkRef := range networkRefs {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		networkPorts, err = si.GetNetworkPorts(*networkRef.NetworkID)
+		if err != nil {
+			return nil, fmt.Errorf("Error: Could not plan the cleanup of the network %s (%v)", *networkRef.Name, err)
+		}
+
+		for _, networkPort := range networkPorts {
+			if networkPort.PvmInstance == nil {
+				action := newAction(CleanupKindNetworkPort, *networkPort.PortID, ptr.Deref(networkPort.IPAddress, *networkPort.PortID), fmt.Sprintf("port on the network %s", *networkRef.Name))
+				if action != nil {
+					action.Parent = *networkRef.NetworkID
+					portsToDelete = append(portsToDelete, action)
 				}
+				continue
+			}
+
+			serverName := networkPort.PvmInstance.PvmInstanceID
+			instancesFound, _ := si.FindPVMInstance(serverName)
+			if len(instancesFound) == 1 {
+				serverName = *instancesFound[0].ServerName
 			}
+
+			addAction(newAction(CleanupKindPVMInstance, networkPort.PvmInstance.PvmInstanceID, serverName, fmt.Sprintf("instance on the network %s", *networkRef.Name)))
 		}
 	}
 
-	if !isOk {
-		return
+	volumes, err = si.GetVolumes()
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not plan the cleanup of the volumes of %s (%v)", si.name, err)
+	}
+
+	for _, volume := range volumes {
+		reason = volumeCleanupReason(volume, func(pvmInstanceID string) bool {
+			return planned[CleanupKindPVMInstance+"/"+pvmInstanceID]
+		})
+		if reason != "" {
+			addAction(newAction(CleanupKindVolume, *volume.VolumeID, *volume.Name, reason))
+		}
+	}
+
+	dhcpServers, err = si.GetDhcpServers()
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not plan the cleanup of the DHCP servers of %s (%v)", si.name, err)
+	}
+	for _, dhcpServer := range dhcpServers {
+		addAction(newAction(CleanupKindDHCPServer, *dhcpServer.ID, *dhcpServer.Network.Name, "leftover DHCP server"))
+	}
+
+	actions = append(actions, portsToDelete...)
+
+	for _, networkRef := range networkRefs {
+		addAction(newAction(CleanupKindNetwork, *networkRef.NetworkID, *networkRef.Name, "leftover network"))
+	}
+
+	imageRefs, err = si.GetImages()
+	if err != nil {
+		return nil, fmt.Errorf("Error: Could not plan the cleanup of the images of %s (%v)", si.name, err)
+	}
+	for _, imageRef := range imageRefs {
+		addAction(newAction(CleanupKindImage, *imageRef.ImageID, *imageRef.Name, "leftover image"))
+	}
+
+	log.Debugf("PlanCleanup: %d actions", len(actions))
+
+	return actions, nil
+}
+
+// volumeCleanupReason returns why the cleanup deletes a volume, or "" when it does not.
+// PowerVS deletes the boot volume with its instance, but only detaches the data volumes,
+// for example the ones of the persistent volumes of the cluster, so these are deleted
+// when all of the instances they are attached to are.
+func volumeCleanupReason(volume *models.VolumeReference, isDeleted func(pvmInstanceID string) bool) string {
+	var (
+		reason = "unattached volume"
+	)
+
+	for _, pvmInstanceID := range volume.PvmInstanceIDs {
+		if !isDeleted(pvmInstanceID) || ptr.Deref(volume.BootVolume, false) {
+			return ""
+		}
+		reason = "volume of a deleted instance"
 	}
 
-	fmt.Printf("%s %s is OK.\n", siObjectName, si.name)
+	return reason
 }
 
-func (si *ServiceInstance) ClusterStatus() {
+// ApplyCleanup makes one deletion of a plan from PlanCleanup.  It waits for the jobs of
+// the resource to finish, deletes it, retrying when that fails, and waits until PowerVS
+// no longer lists it, for at most cleanupPolicy.Timeout.  All of the calls to PowerVS
+// count against that timeout.
+func (si *ServiceInstance) ApplyCleanup(ctx context.Context, action *CleanupAction) (CleanupOutcome, error) {
 	var (
-		isOk = true
+		stepCtx context.Context
+		cancel  context.CancelFunc
+		stepSi  *ServiceInstance
+		exists  bool
+		err     error
 	)
 
+	log.Debugf("ApplyCleanup: %+v", action)
+
+	stepCtx, cancel = context.WithTimeout(ctx, cleanupPolicy.Timeout)
+	defer cancel()
+
+	stepSi, err = si.withClients(stepCtx)
+	if err != nil {
+		return CleanupOutcomeFailed, err
+	}
+
+	// Another step, for example the deletion of a DHCP server with its network, may
+	// have deleted it already.
+	exists, err = stepSi.cleanupResourceExists(action)
+	if err != nil {
+		return cleanupOutcomeOfError(ctx, stepCtx), err
+	}
+	if !exists {
+		return CleanupOutcomeGone, nil
+	}
+
+	// PowerVS refuses to delete a resource while a job, for example the capture of an
+	// instance or the export of an image, is running on it.
+	err = stepSi.waitForCleanupJobs(stepCtx, action)
+	if err != nil {
+		return cleanupOutcomeOfError(ctx, stepCtx), err
+	}
+
+	for attempt := 1; attempt <= cleanupPolicy.MaxAttempts; attempt++ {
+		err = stepSi.deleteCleanupResource(action)
+		if err == nil {
+			err = stepSi.waitUntilCleanedUp(stepCtx, action)
+			if err == nil {
+				return CleanupOutcomeDeleted, nil
+			}
+			return cleanupOutcomeOfError(ctx, stepCtx), err
+		}
+		log.Debugf("ApplyCleanup: attempt %d of %d to delete %s %s returned %v", attempt, cleanupPolicy.MaxAttempts, action.Kind, action.ID, err)
+
+		// The deletion fails when something else deleted the resource meanwhile.
+		exists, _ = stepSi.cleanupResourceExists(action)
+		if !exists {
+			return CleanupOutcomeDeleted, nil
+		}
+
+		if attempt < cleanupPolicy.MaxAttempts && sleepContext(stepCtx, cleanupPolicy.PollInterval) != nil {
+			return cleanupOutcomeOfError(ctx, stepCtx), fmt.Errorf("%v (%v)", err, stepCtx.Err())
+		}
+	}
+
+	return CleanupOutcomeFailed, fmt.Errorf("%v, after %d attempts", err, cleanupPolicy.MaxAttempts)
+}
+
+// cleanupOutcomeOfError tells a step which took too long from a command which was
+// interrupted.
+func cleanupOutcomeOfError(ctx context.Context, stepCtx context.Context) CleanupOutcome {
+	if ctx.Err() == nil && stepCtx.Err() != nil {
+		return CleanupOutcomeTimedOut
+	}
+
+	return CleanupOutcomeFailed
+}
+
+// deleteCleanupResource starts the deletion of the resource of a step.
+func (si *ServiceInstance) deleteCleanupResource(action *CleanupAction) error {
+	var (
+		err error
+	)
+
+	switch action.Kind {
+	case CleanupKindPVMInstance:
+		err = si.instanceClient.Delete(action.ID)
+		si.invalidateCache("pvs.instances", "pvs.instance", "pvs.networkPorts", "pvs.volumes", "pvs.instanceVolumes")
+	case CleanupKindVolume:
+		err = si.volumeClient.DeleteVolume(action.ID)
+		si.invalidateCache("pvs.volumes", "pvs.instanceVolumes")
+	case CleanupKindDHCPServer:
+		err = si.dhcpClient.Delete(action.ID)
+		si.invalidateCache("pvs.dhcpServers", "pvs.dhcpServer", "pvs.networks", "pvs.networkPorts")
+	case CleanupKindNetworkPort:
+		err = si.networkClient.DeletePort(action.Parent, action.ID)
+		si.invalidateCache("pvs.networkPorts")
+	case CleanupKindNetwork:
+		err = si.networkClient.Delete(action.ID)
+		si.invalidateCache("pvs.networks", "pvs.networkPorts")
+	case CleanupKindImage:
+		err = si.imageClient.Delete(action.ID)
+		si.invalidateCache("pvs.images")
+	default:
+		return fmt.Errorf("Error: A %s cannot delete a %s", siObjectName, action.Kind)
+	}
+
+	return err
+}
+
+// cleanupResourceExists returns whether PowerVS still lists the resource of a step.  It
+// does not use the cache, since the listing changes while the resource is deleted.
+func (si *ServiceInstance) cleanupResourceExists(action *CleanupAction) (bool, error) {
+	switch action.Kind {
+	case CleanupKindPVMInstance:
+		instances, err := si.instanceClient.GetAll()
+		if err != nil {
+			return true, err
+		}
+		for _, instanceRef := range instances.PvmInstances {
+			if ptr.Deref(instanceRef.PvmInstanceID, "") == action.ID {
+				log.Debugf("cleanupResourceExists: instance %s is %s", action.ID, ptr.Deref(instanceRef.Status, ""))
+				return true, nil
+			}
+		}
+	case CleanupKindVolume:
+		volumes, err := si.volumeClient.GetAll()
+		if err != nil {
+			return true, err
+		}
+		for _, volume := range volumes.Volumes {
+			if ptr.Deref(volume.VolumeID, "") == action.ID {
+				log.Debugf("cleanupResourceExists: volume %s is %s, attached to %v", action.ID, ptr.Deref(volume.State, ""), volume.PvmInstanceIDs)
+				return true, nil
+			}
+		}
+	case CleanupKindDHCPServer:
+		dhcpServers, err := si.dhcpClient.GetAll()
+		if err != nil {
+			return true, err
+		}
+		for _, dhcpServer := range dhcpServers {
+			if ptr.Deref(dhcpServer.ID, "") == action.ID {
+				return true, nil
+			}
+		}
+	case CleanupKindNetworkPort:
+		exists, err := si.cleanupResourceExists(&CleanupAction{Kind: CleanupKindNetwork, ID: action.Parent})
+		if err != nil || !exists {
+			return exists, err
+		}
+		networkPorts, err := si.networkClient.GetAllPorts(action.Parent)
+		if err != nil {
+			return true, err
+		}
+		for _, networkPort := range networkPorts.Ports {
+			if ptr.Deref(networkPort.PortID, "") == action.ID {
+				return true, nil
+			}
+		}
+	case CleanupKindNetwork:
+		networks, err := si.networkClient.GetAll()
+		if err != nil {
+			return true, err
+		}
+		for _, networkRef := range networks.Networks {
+			if ptr.Deref(networkRef.NetworkID, "") == action.ID {
+				return true, nil
+			}
+		}
+	case CleanupKindImage:
+		images, err := si.imageClient.GetAll()
+		if err != nil {
+			return true, err
+		}
+		for _, imageRef := range images.Images {
+			if ptr.Deref(imageRef.ImageID, "") == action.ID {
+				return true, nil
+			}
+		}
+	default:
+		return true, fmt.Errorf("Error: A %s cannot delete a %s", siObjectName, action.Kind)
+	}
+
+	return false, nil
+}
+
+// waitUntilCleanedUp waits until PowerVS no longer lists the resource of a step.
+func (si *ServiceInstance) waitUntilCleanedUp(ctx context.Context, action *CleanupAction) error {
+	var (
+		exists bool
+		err    error
+	)
+
+	for {
+		exists, err = si.cleanupResourceExists(action)
+		if err != nil {
+			log.Debugf("waitUntilCleanedUp: %s %s returned %v", action.Kind, action.ID, err)
+		}
+		if err == nil && !exists {
+			return nil
+		}
+
+		log.Debugf("waitUntilCleanedUp: waiting for %s %s to be deleted", action.Kind, action.ID)
+		if sleepContext(ctx, cleanupPolicy.PollInterval) != nil {
+			return fmt.Errorf("%s %s was still there after %v", action.Kind, action.ID, cleanupPolicy.Timeout)
+		}
+	}
+}
+
+// waitForCleanupJobs waits until no job is running on the resource of a step.  It fails
+// when PowerVS refuses to list the jobs, or fails to cleanupPolicy.MaxAttempts times in
+// a row.
+func (si *ServiceInstance) waitForCleanupJobs(ctx context.Context, action *CleanupAction) error {
+	var (
+		jobs     *models.Jobs
+		running  []string
+		failures int
+		err      error
+	)
+
+	for {
+		jobs, err = si.jobClient.GetAll()
+		if err != nil {
+			// Without the jobs, the deletion could start while one is still running, so
+			// ask again, unless asking again does not help.
+			failures++
+			log.Debugf("waitForCleanupJobs: GetAll returned %v (%d in a row)", err, failures)
+			if isClientError(err) || failures >= cleanupPolicy.MaxAttempts {
+				return fmt.Errorf("could not list the jobs of %s %s (%v)", action.Kind, action.ID, err)
+			}
+			if sleepContext(ctx, cleanupPolicy.PollInterval) != nil {
+				return fmt.Errorf("could not list the jobs of %s %s for %v (%v)", action.Kind, action.ID, cleanupPolicy.Timeout, err)
+			}
+			continue
+		}
+		failures = 0
+
+		running = running[:0]
+		for _, job := range jobs.Jobs {
+			if job.Operation == nil || ptr.Deref(job.Operation.ID, "") != action.ID || job.Status == nil {
+				continue
+			}
+
+			switch ptr.Deref(job.Status.State, "") {
+			case "completed", "failed":
+			default:
+				running = append(running, fmt.Sprintf("%s %s (%s)", ptr.Deref(job.Operation.Action, ""), ptr.Deref(job.ID, ""), ptr.Deref(job.Status.State, "")))
+			}
+		}
+		if len(running) == 0 {
+			return nil
+		}
+
+		log.Debugf("waitForCleanupJobs: %s %s has the running jobs %v", action.Kind, action.ID, running)
+		if sleepContext(ctx, cleanupPolicy.PollInterval) != nil {
+			return fmt.Errorf("the jobs %s were still running after %v", strings.Join(running, ", "), cleanupPolicy.Timeout)
+		}
+	}
+}
+
+// isClientError returns whether PowerVS refused a request, for example with a 403 or a
+// 404, so that making it again does not help.  A 429 only asks to slow down.
+func isClientError(err error) bool {
+	var (
+		apiErr interface {
+			IsClientError() bool
+			IsCode(code int) bool
+		}
+	)
+
+	return errors.As(err, &apiErr) && apiErr.IsClientError() && !apiErr.IsCode(gohttp.StatusTooManyRequests)
+}
+
+func (si *ServiceInstance) ClusterStatus(ctx context.Context) *ObjectResult {
+	var (
+		result           *ObjectResult
+		clusterInstances []*models.PVMInstance
+	)
+
+	result = NewObjectResult(si, si.name)
+
 	if si.innerSi == nil {
-		fmt.Printf("%s is NOTOK. Could not find a %s named %s\n", siObjectName, siObjectName, si.name)
-		return
+		result.AddError("pvs.exists", "Could not find a %s named %s", siObjectName, si.name)
+		return result
 	}
 
 	if *si.innerSi.State != "active" {
-		fmt.Printf("%s %s is NOTOK. The status is %s\n", siObjectName, si.name, *si.innerSi.State)
-		return
+		result.AddNotOK("pvs.state", "The status is %s", *si.innerSi.State).
+			WithEvidence("state", *si.innerSi.State)
+		return result
 	}
 
 	dhcpServer, err := si.FindDhcpServer()
 	log.Debugf("dhcpServer = %+v, err = %v", dhcpServer, err)
 	if err == nil && dhcpServer != nil {
-		fmt.Printf("%s %s has a DHCP server.\n", siObjectName, si.name)
+		result.AddOK("pvs.dhcp", "has a DHCP server.")
 	} else {
-		fmt.Printf("%s %s is NOTOK.  Did not find a DHCP server.\n", siObjectName, si.name)
-		isOk = false
+		result.AddNotOK("pvs.dhcp", "Did not find a DHCP server.")
 	}
 	if err != nil {
-		fmt.Printf("%s %s returned this error searching for DHCP servers: %v\n", siObjectName, si.name, err)
-		isOk = false
+		result.AddError("pvs.dhcp", "returned this error searching for DHCP servers: %v", err)
 	}
 
 	dhcpServers, err := si.GetDhcpServers()
 	if err != nil {
 	} else if len(dhcpServers) > 1 {
-		fmt.Printf("%s %s is NOTOK.  Found more than 1 DHCP server (%d).\n", siObjectName, si.name, len(dhcpServers))
-		isOk = false
+		result.AddNotOK("pvs.dhcp.count", "Found more than 1 DHCP server (%d).", len(dhcpServers)).
+			WithEvidence("count", fmt.Sprintf("%d", len(dhcpServers)))
 	}
 
 	imageRHCOS, err := si.FindImage(si.rhcosName)
@@ -1086,83 +1666,273 @@ func (si *ServiceInstance) ClusterStatus() {
 	if err == nil {
 		if imageRHCOS != nil {
 			if *imageRHCOS.State == "active" {
-				fmt.Printf("%s %s has an active RHCOS image.\n", siObjectName, si.name)
+				result.AddOK("pvs.image.rhcos", "has an active RHCOS image.")
 			} else {
-				fmt.Printf("%s %s does not have an active RHCOS image. (%s)\n", siObjectName, si.name, *imageRHCOS.State)
-				isOk = false
+				result.AddNotOK("pvs.image.rhcos", "does not have an active RHCOS image. (%s)", *imageRHCOS.State).
+					WithEvidence("state", *imageRHCOS.State)
 			}
 		} else {
-			fmt.Printf("%s %s something went wrong looking for the RHCOS image.\n", siObjectName, si.name)
-			isOk = false
+			result.AddNotOK("pvs.image.rhcos", "something went wrong looking for the RHCOS image.")
 		}
 	}
 	if err != nil {
-		fmt.Printf("%s %s returned this error searching for images: %v\n", siObjectName, si.name, err)
-		isOk = false
+		result.AddError("pvs.image.rhcos", "returned this error searching for images: %v", err)
 	}
 
 	sshKey, err := si.FindSshKey()
 	log.Debugf("sshKey = %+v, err = %v", sshKey, err)
 	if err == nil {
-		fmt.Printf("%s %s has an ssh key.\n", siObjectName, si.name)
+		result.AddOK("pvs.sshkey", "has an ssh key.")
 	} else {
-		fmt.Printf("%s %s returned this error searching for ssh keys: %v\n", siObjectName, si.name, err)
-		isOk = false
+		result.AddError("pvs.sshkey", "returned this error searching for ssh keys: %v", err)
 	}
 
-	for i := 0; i < 3; i++ {
+	for i := 0; i < si.services.GetMetadata().GetControlPlaneReplicas(); i++ {
+		masterName := fmt.Sprintf("master-%d", i)
+
 		mastersFound, err := si.FindPVMInstance(fmt.Sprintf("%s-.*-master-%d", si.services.GetMetadata().GetClusterName(), i))
 		log.Debugf("mastersFound = %+v, err = %v", mastersFound, err)
 		if err != nil {
-			fmt.Printf("%s %s did not have a master-%d instance got error: %v\n", siObjectName, si.name, i, err)
-			isOk = false
+			result.AddError("pvs.master", "did not have a master-%d instance got error: %v", i, err).
+				WithEvidence("instance", masterName)
 		} else if len(mastersFound) == 1 {
+			clusterInstances = append(clusterInstances, mastersFound[0])
+
 			log.Debugf("findPVMInstance master[%d].Status = %s", i, *mastersFound[0].Status)
 			log.Debugf("findPVMInstance master[%d].Health.Status = %s", i, mastersFound[0].Health.Status)
 
 			if *mastersFound[0].Status == "ACTIVE" {
-				fmt.Printf("%s %s found a healthy master-%d instance (status: %s, health: %s).\n", siObjectName, si.name, i, *mastersFound[0].Status, mastersFound[0].Health.Status)
+				result.AddOK("pvs.master", "found a healthy master-%d instance (status: %s, health: %s).", i, *mastersFound[0].Status, mastersFound[0].Health.Status).
+					WithEvidence("instance", masterName).
+					WithEvidence("status", *mastersFound[0].Status).
+					WithEvidence("health", mastersFound[0].Health.Status)
 			} else {
-				fmt.Printf("%s %s found an unhealthy master-%d instance (status: %s, health: %s).\n", siObjectName, si.name, i, *mastersFound[0].Status, mastersFound[0].Health.Status)
-				isOk = false
+				result.AddNotOK("pvs.master", "found an unhealthy master-%d instance (status: %s, health: %s).", i, *mastersFound[0].Status, mastersFound[0].Health.Status).
+					WithEvidence("instance", masterName).
+					WithEvidence("status", *mastersFound[0].Status).
+					WithEvidence("health", mastersFound[0].Health.Status)
+
+				si.addInstanceDiagnostics(result, mastersFound[0])
 			}
 		} else {
-			fmt.Printf("%s %s did not have 1 master-%d instance, found %d.\n", siObjectName, si.name, i, len(mastersFound))
-			isOk = false
+			result.AddNotOK("pvs.master", "did not have 1 master-%d instance, found %d.", i, len(mastersFound)).
+				WithEvidence("instance", masterName).
+				WithEvidence("count", fmt.Sprintf("%d", len(mastersFound)))
 		}
 	}
 
 	workersFound, err := si.FindPVMInstance(fmt.Sprintf("%s-.*-worker-", si.services.GetMetadata().GetClusterName()))
+	if expectedWorkers, ok := si.services.GetMetadata().GetComputeReplicas(); ok && err == nil && len(workersFound) != expectedWorkers {
+		result.AddNotOK("pvs.workers.count", "expected %d worker instances, found %d.", expectedWorkers, len(workersFound)).
+			WithEvidence("expected", fmt.Sprintf("%d", expectedWorkers)).
+			WithEvidence("count", fmt.Sprintf("%d", len(workersFound)))
+	}
 	if err != nil {
-		fmt.Printf("%s %s did not have a worker instance got error: %v\n", siObjectName, si.name, err)
-		isOk = false
+		result.AddError("pvs.workers", "did not have a worker instance got error: %v", err)
 	} else if len(workersFound) > 0 {
-		fmt.Printf("%s %s found %d worker instances.\n", siObjectName, si.name, len(workersFound))
+		result.AddOK("pvs.workers", "found %d worker instances.", len(workersFound)).
+			WithEvidence("count", fmt.Sprintf("%d", len(workersFound)))
+
+		clusterInstances = append(clusterInstances, workersFound...)
 
 		for _, worker := range workersFound {
 			log.Debugf("findPVMInstance worker.Status = %s", *worker.Status)
 			log.Debugf("findPVMInstance worker.Health.Status = %s", worker.Health.Status)
 
 			if *worker.Status == "ACTIVE" {
-				fmt.Printf("%s %s found a healthy worker instance %s (status: %s, health: %s).\n", siObjectName, si.name, *worker.ServerName, *worker.Status, worker.Health.Status)
+				result.AddOK("pvs.worker", "found a healthy worker instance %s (status: %s, health: %s).", *worker.ServerName, *worker.Status, worker.Health.Status).
+					WithEvidence("instance", *worker.ServerName).
+					WithEvidence("status", *worker.Status).
+					WithEvidence("health", worker.Health.Status)
 			} else {
-				fmt.Printf("%s %s found an unhealthy worker instance %s (status: %s, health: %s).\n", siObjectName, si.name, *worker.ServerName, *worker.Status, worker.Health.Status)
-				isOk = false
+				result.AddNotOK("pvs.worker", "found an unhealthy worker instance %s (status: %s, health: %s).", *worker.ServerName, *worker.Status, worker.Health.Status).
+					WithEvidence("instance", *worker.ServerName).
+					WithEvidence("status", *worker.Status).
+					WithEvidence("health", worker.Health.Status)
+
+				si.addInstanceDiagnostics(result, worker)
 			}
 		}
+	} else if expectedWorkers, ok := si.services.GetMetadata().GetComputeReplicas(); ok && expectedWorkers == 0 {
+		result.AddOK("pvs.workers", "has no worker instances, as expected.")
 	} else {
-		fmt.Printf("%s %s did not find any worker instances.\n", siObjectName, si.name)
-		isOk = false
+		result.AddNotOK("pvs.workers", "did not find any worker instances.")
+	}
+
+	for _, clusterInstance := range clusterInstances {
+		si.addInstanceConfigChecks(result, clusterInstance)
+		si.addVolumeChecks(result, clusterInstance)
 	}
 
-	if !isOk {
-		fmt.Printf("%s %s is NOTOK.\n", siObjectName, si.name)
+	return result
+}
+
+// addInstanceConfigChecks reports the configuration and the fault of an instance of the
+// cluster, and checks that its system type is one which the zone offers and that its
+// processors are not capped.
+func (si *ServiceInstance) addInstanceConfigChecks(result *ObjectResult, pvmInstance *models.PVMInstance) {
+	var (
+		serverName = ptr.Deref(pvmInstance.ServerName, "")
+		metadata   = si.services.GetMetadata()
+		addresses  = make([]string, 0)
+		processors = fmt.Sprintf("%g", ptr.Deref(pvmInstance.Processors, 0))
+		procType   = ptr.Deref(pvmInstance.ProcType, "")
+		memory     = fmt.Sprintf("%g", ptr.Deref(pvmInstance.Memory, 0))
+		created    = "-"
+		region     Region
+		zone       Zone
+		found      bool
+	)
+
+	for _, network := range pvmInstance.Networks {
+		address := network.IPAddress
+		if network.ExternalIP != "" {
+			address += "/" + network.ExternalIP
+		}
+		addresses = append(addresses, fmt.Sprintf("%s (%s)", address, network.NetworkName))
+	}
+
+	if !time.Time(pvmInstance.CreationDate).IsZero() {
+		created = pvmInstance.CreationDate.String()
+	}
+
+	result.AddOK("pvs.instance.config", "instance %s has the system type %s, %s %s processors, %s GB of memory and %s storage, the addresses %s, and was created %s.",
+		serverName,
+		orDash(pvmInstance.SysType),
+		processors,
+		procType,
+		memory,
+		orDash(ptr.Deref(pvmInstance.StorageType, "")),
+		orDash(strings.Join(addresses, ", ")),
+		created).
+		WithEvidence("instance", serverName).
+		WithEvidence("sysType", pvmInstance.SysType).
+		WithEvidence("processors", processors).
+		WithEvidence("procType", procType).
+		WithEvidence("memory", memory).
+		WithEvidence("storageType", ptr.Deref(pvmInstance.StorageType, "")).
+		WithEvidence("addresses", strings.Join(addresses, ",")).
+		WithEvidence("created", created)
+
+	if pvmInstance.Fault != nil && (pvmInstance.Fault.Code != 0 || pvmInstance.Fault.Message != "") {
+		result.AddNotOK("pvs.instance.fault", "instance %s has the fault %g: %s (created %s).", serverName, pvmInstance.Fault.Code, pvmInstance.Fault.Message, pvmInstance.Fault.Created.String()).
+			WithEvidence("instance", serverName).
+			WithEvidence("code", fmt.Sprintf("%g", pvmInstance.Fault.Code)).
+			WithEvidence("message", pvmInstance.Fault.Message).
+			WithEvidence("details", pvmInstance.Fault.Details).
+			WithEvidence("created", pvmInstance.Fault.Created.String())
+	}
+
+	// The zones which neither the built-in regions nor the API know the system types of
+	// are not checked.
+	region, found = regionCatalog.Region(metadata.GetRegion())
+	if found {
+		zone, found = region.Zones[metadata.GetZone()]
+	}
+	if found && len(zone.SysTypes) > 0 && pvmInstance.SysType != "" {
+		if slices.Contains(zone.SysTypes, pvmInstance.SysType) {
+			result.AddOK("pvs.instance.systype", "instance %s has the system type %s, which zone %s offers.", serverName, pvmInstance.SysType, metadata.GetZone()).
+				WithEvidence("instance", serverName).
+				WithEvidence("sysType", pvmInstance.SysType)
+		} else {
+			result.AddNotOK("pvs.instance.systype", "instance %s has the system type %s, which zone %s does not offer (%s).", serverName, pvmInstance.SysType, metadata.GetZone(), strings.Join(zone.SysTypes, ", ")).
+				WithEvidence("instance", serverName).
+				WithEvidence("sysType", pvmInstance.SysType).
+				WithEvidence("zoneSysTypes", strings.Join(zone.SysTypes, ","))
+		}
+	}
+
+	// Capped processors cannot use more than their entitlement, which can starve a node,
+	// but a cluster may well be sized for them, so this is only a notice.
+	if procType == "capped" {
+		result.AddOK("pvs.instance.proctype", "instance %s has %s capped processors, which cannot use more than their entitlement.", serverName, processors).
+			WithEvidence("instance", serverName).
+			WithEvidence("procType", procType).
+			WithEvidence("processors", processors)
+	}
+}
+
+// addInstanceDiagnostics saves the diagnostics of an instance which is not ACTIVE into
+// diagnosticsDir, and summarizes its last system reference codes.
+func (si *ServiceInstance) addInstanceDiagnostics(result *ObjectResult, pvmInstance *models.PVMInstance) {
+	var (
+		serverName  = ptr.Deref(pvmInstance.ServerName, "")
+		diagnostics *InstanceDiagnostics
+		filename    string
+		lastSRCs    []string
+		err         error
+	)
+
+	if diagnosticsDir == "" {
+		return
+	}
+
+	// The instance already has its SRCs, so no more calls are made.
+	diagnostics = NewInstanceDiagnostics(pvmInstance)
+
+	filename, err = diagnostics.Save(diagnosticsDir)
+	if err != nil {
+		result.AddError("pvs.instance.diagnostics", "could not save the diagnostics of %s: %v", serverName, err).
+			WithEvidence("instance", serverName)
 		return
 	}
 
-	fmt.Printf("%s %s is OK.\n", siObjectName, si.name)
+	lastSRCs = diagnostics.LastSRCs(diagnosticsSummarySRCs)
+	if len(lastSRCs) == 0 {
+		lastSRCs = []string{"none"}
+	}
+
+	result.AddOK("pvs.instance.diagnostics", "saved the diagnostics of %s into %s, the last system reference codes are %s.", serverName, filename, strings.Join(lastSRCs, ", ")).
+		WithEvidence("instance", serverName).
+		WithEvidence("file", filename)
 }
 
-func (si *ServiceInstance) Priority() (int, error) {
-	return 80, nil
+// addVolumeChecks lists the boot and data volumes of an instance of the cluster, and
+// is NOTOK when one of them is in an error state.
+func (si *ServiceInstance) addVolumeChecks(result *ObjectResult, pvmInstance *models.PVMInstance) {
+	var (
+		serverName = ptr.Deref(pvmInstance.ServerName, "")
+		volumes    []*models.VolumeReference
+		kind       string
+		state      string
+		tier       string
+		size       string
+		check      *CheckResult
+		err        error
+	)
+
+	volumes, err = si.GetInstanceVolumes(ptr.Deref(pvmInstance.PvmInstanceID, ""))
+	if err != nil {
+		result.AddError("pvs.volumes", "returned this error listing the volumes of %s: %v", serverName, err).
+			WithEvidence("instance", serverName)
+		return
+	}
+
+	if len(volumes) == 0 {
+		result.AddNotOK("pvs.volumes", "instance %s has no volumes.", serverName).
+			WithEvidence("instance", serverName)
+		return
+	}
+
+	for _, volume := range volumes {
+		kind = "data"
+		if ptr.Deref(volume.BootVolume, false) {
+			kind = "boot"
+		}
+		state = ptr.Deref(volume.State, "")
+		tier = ptr.Deref(volume.DiskType, "")
+		size = fmt.Sprintf("%g", ptr.Deref(volume.Size, 0))
+
+		if strings.HasPrefix(state, "error") {
+			check = result.AddNotOK("pvs.volume", "instance %s has the %s volume %s in an error state (state: %s, tier: %s, size: %s GB).", serverName, kind, ptr.Deref(volume.Name, ""), state, tier, size)
+		} else {
+			check = result.AddOK("pvs.volume", "instance %s has the %s volume %s (state: %s, tier: %s, size: %s GB).", serverName, kind, ptr.Deref(volume.Name, ""), state, tier, size)
+		}
+		check.WithEvidence("instance", serverName).
+			WithEvidence("volume", ptr.Deref(volume.Name, "")).
+			WithEvidence("type", kind).
+			WithEvidence("state", state).
+			WithEvidence("tier", tier).
+			WithEvidence("size", size)
+	}
 }
@@ -0,0 +1,375 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM-Cloud/power-go-client/power/models"
+
+	"github.com/go-openapi/runtime"
+
+	"k8s.io/utils/ptr"
+)
+
+func TestServiceInstanceClusterStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		clusterName string
+		guid        string
+		wantStatus  CheckStatus
+		wantCheck   string
+	}{
+		{"healthy cluster", "good", "g-good", CheckStatusOK, "pvs.master"},
+		{"capped processors are a notice", "good", "g-good", CheckStatusOK, "pvs.instance.proctype"},
+		{"master not active", "bad", "g-bad", CheckStatusNotOK, "pvs.master"},
+		{"no PowerVS resources", "broken", "g-broken", CheckStatusError, "pvs.dhcp"},
+		{"service instance not found", "lost", "g-lost", CheckStatusError, "pvs.exists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestClusterServices(t, tt.clusterName, tt.guid, "good-vpc")
+
+			sis, _ := NewServiceInstanceAlt(context.Background(), services)
+			if len(sis) != 1 {
+				t.Fatalf("NewServiceInstanceAlt returned %d service instances, want 1", len(sis))
+			}
+
+			result := sis[0].ClusterStatus(context.Background())
+			if result.Status != tt.wantStatus {
+				t.Errorf("ClusterStatus().Status = %s, want %s%s", result.Status, tt.wantStatus, dumpChecks(result))
+			}
+			if !hasCheck(result, tt.wantCheck, tt.wantStatus) {
+				t.Errorf("ClusterStatus() has no %s check %s%s", tt.wantStatus, tt.wantCheck, dumpChecks(result))
+			}
+		})
+	}
+}
+
+func TestServiceInstanceCiStatus(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name            string
+		serviceInstance string
+		ctx             context.Context
+		wantStatus      CheckStatus
+		wantChecks      []string
+	}{
+		{"no leftovers", "ci-empty", context.Background(), CheckStatusOK, []string{"pvs.empty"}},
+		{"leftovers", "ci-leftover", context.Background(), CheckStatusNotOK, []string{"pvs.instances", "pvs.dhcp", "pvs.images", "pvs.volumes", "pvs.networks", "pvs.network.instance"}},
+		{"no PowerVS resources", "broken-si", context.Background(), CheckStatusError, []string{"pvs.instances", "pvs.dhcp", "pvs.images", "pvs.volumes", "pvs.networks"}},
+		{"interrupted", "ci-empty", cancelled, CheckStatusError, []string{"pvs.cancelled"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestCIServices(t, tt.serviceInstance, "good-vpc", "")
+
+			sis, errs := NewServiceInstanceAlt(context.Background(), services)
+			if len(sis) != 1 || errs[0] != nil {
+				t.Fatalf("NewServiceInstanceAlt returned %d service instances and %v, want 1", len(sis), errs)
+			}
+
+			result := sis[0].CiStatus(tt.ctx, false)
+			if result.Status != tt.wantStatus {
+				t.Errorf("CiStatus().Status = %s, want %s%s", result.Status, tt.wantStatus, dumpChecks(result))
+			}
+			for _, id := range tt.wantChecks {
+				if !hasCheck(result, id, tt.wantStatus) {
+					t.Errorf("CiStatus() has no %s check %s%s", tt.wantStatus, id, dumpChecks(result))
+				}
+			}
+		})
+	}
+}
+
+// newTestLeftoverServiceInstance returns the service instance of the test fixtures which
+// has leftovers for the cleanup, with its own fake backend.
+func newTestLeftoverServiceInstance(t *testing.T) (*ServiceInstance, *FakeCloud) {
+	t.Helper()
+
+	services := newTestCIServices(t, "ci-leftover", "good-vpc", "")
+
+	sis, errs := NewServiceInstanceAlt(context.Background(), services)
+	if len(sis) != 1 || errs[0] != nil {
+		t.Fatalf("NewServiceInstanceAlt returned %d service instances and %v, want 1", len(sis), errs)
+	}
+
+	return sis[0], services.GetFakeCloud()
+}
+
+// setTestCleanupPolicy replaces the cleanup policy for the duration of the test.
+func setTestCleanupPolicy(t *testing.T, policy CleanupPolicy) {
+	saved := cleanupPolicy
+	cleanupPolicy = policy
+	t.Cleanup(func() { cleanupPolicy = saved })
+}
+
+// findCleanupAction returns the step of the plan which deletes the resource.
+func findCleanupAction(t *testing.T, actions []*CleanupAction, kind string, id string) *CleanupAction {
+	t.Helper()
+
+	for _, action := range actions {
+		if action.Kind == kind && action.ID == id {
+			return action
+		}
+	}
+
+	t.Fatalf("the plan has no step deleting %s %s", kind, id)
+	return nil
+}
+
+func TestPlanCleanupOrder(t *testing.T) {
+	setTestCleanupPolicy(t, CleanupPolicy{MaxAttempts: 3, PollInterval: time.Millisecond, Timeout: time.Second})
+
+	si, _ := newTestLeftoverServiceInstance(t)
+
+	actions, err := si.PlanCleanup(context.Background())
+	if err != nil {
+		t.Fatalf("PlanCleanup: %v", err)
+	}
+
+	// PowerVS refuses to delete a network which still has a port or an instance on it,
+	// and a volume which is still attached.
+	want := []string{
+		CleanupKindPVMInstance + "/leftover-m0",
+		CleanupKindVolume + "/leftover-volume",
+		CleanupKindVolume + "/leftover-data",
+		CleanupKindDHCPServer + "/leftover-dhcp",
+		CleanupKindNetworkPort + "/leftover-free-port",
+		CleanupKindNetwork + "/leftover-net",
+		CleanupKindImage + "/leftover-rhcos",
+	}
+	got := make([]string, len(actions))
+	for i, action := range actions {
+		got[i] = action.Kind + "/" + action.ID
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("PlanCleanup() = %v, want %v", got, want)
+	}
+
+	// Applied in that order, every deletion succeeds and nothing is left.
+	for _, action := range actions {
+		outcome, err := si.ApplyCleanup(context.Background(), action)
+		if outcome != CleanupOutcomeDeleted || err != nil {
+			t.Errorf("ApplyCleanup(%s %s) = %s, %v, want %s", action.Kind, action.ID, outcome, err, CleanupOutcomeDeleted)
+		}
+	}
+
+	actions, err = si.PlanCleanup(context.Background())
+	if err != nil || len(actions) != 0 {
+		t.Errorf("PlanCleanup() after the cleanup = %d steps, %v, want none", len(actions), err)
+	}
+}
+
+func TestApplyCleanupWaitsForJobs(t *testing.T) {
+	tests := []struct {
+		name        string
+		completeJob bool
+		wantOutcome CleanupOutcome
+	}{
+		{"job completes", true, CleanupOutcomeDeleted},
+		{"job keeps running", false, CleanupOutcomeTimedOut},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestCleanupPolicy(t, CleanupPolicy{MaxAttempts: 3, PollInterval: 5 * time.Millisecond, Timeout: 200 * time.Millisecond})
+
+			si, fake := newTestLeftoverServiceInstance(t)
+
+			actions, err := si.PlanCleanup(context.Background())
+			if err != nil {
+				t.Fatalf("PlanCleanup: %v", err)
+			}
+			action := findCleanupAction(t, actions, CleanupKindImage, "leftover-rhcos")
+
+			job := &models.Job{
+				ID:        ptr.To("leftover-job"),
+				Operation: &models.Operation{Action: ptr.To("imageExport"), ID: ptr.To("leftover-rhcos"), Target: ptr.To("image")},
+				Status:    &models.Status{State: ptr.To("running")},
+			}
+			fake.mutex.Lock()
+			fake.powerVS["g-leftover"].Jobs = []*models.Job{job}
+			fake.mutex.Unlock()
+
+			imageExists := func() bool {
+				fake.mutex.Lock()
+				defer fake.mutex.Unlock()
+
+				return len(fake.powerVS["g-leftover"].Images) != 0
+			}
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+
+				time.Sleep(50 * time.Millisecond)
+				if !imageExists() {
+					t.Errorf("the image was deleted while its job was running")
+				}
+				if tt.completeJob {
+					fake.mutex.Lock()
+					fake.powerVS["g-leftover"].Jobs = []*models.Job{{
+						ID:        job.ID,
+						Operation: job.Operation,
+						Status:    &models.Status{State: ptr.To("completed")},
+					}}
+					fake.mutex.Unlock()
+				}
+			}()
+
+			outcome, err := si.ApplyCleanup(context.Background(), action)
+			<-done
+			if outcome != tt.wantOutcome {
+				t.Errorf("ApplyCleanup() = %s, %v, want %s", outcome, err, tt.wantOutcome)
+			}
+			if imageExists() != (tt.wantOutcome != CleanupOutcomeDeleted) {
+				t.Errorf("ApplyCleanup() returned %s but the image exists is %v", outcome, imageExists())
+			}
+		})
+	}
+}
+
+func TestApplyCleanupRetries(t *testing.T) {
+	var (
+		serverError = errors.New("Error: The server has encountered an unexpected error")
+	)
+
+	tests := []struct {
+		name        string
+		call        string
+		errs        []error
+		wantOutcome CleanupOutcome
+		wantExists  bool
+	}{
+		{"delete fails once", "PIImageClient.Delete", []error{serverError}, CleanupOutcomeDeleted, false},
+		{"delete keeps failing", "PIImageClient.Delete", []error{serverError, serverError, serverError}, CleanupOutcomeFailed, true},
+		{"jobs fail once", "PIJobClient.GetAll", []error{serverError}, CleanupOutcomeDeleted, false},
+		{"jobs keep failing", "PIJobClient.GetAll", []error{serverError, serverError, serverError}, CleanupOutcomeFailed, true},
+		{"jobs are forbidden", "PIJobClient.GetAll", []error{fmt.Errorf("failed to perform get all jobs operation with error %w", &runtime.APIError{Code: 403})}, CleanupOutcomeFailed, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Only the retries may end the step, not its timeout.
+			setTestCleanupPolicy(t, CleanupPolicy{MaxAttempts: 3, PollInterval: time.Millisecond, Timeout: time.Minute})
+
+			si, fake := newTestLeftoverServiceInstance(t)
+
+			actions, err := si.PlanCleanup(context.Background())
+			if err != nil {
+				t.Fatalf("PlanCleanup: %v", err)
+			}
+			action := findCleanupAction(t, actions, CleanupKindImage, "leftover-rhcos")
+
+			fake.failPowerVS(tt.call, tt.errs...)
+
+			outcome, err := si.ApplyCleanup(context.Background(), action)
+			if outcome != tt.wantOutcome {
+				t.Errorf("ApplyCleanup() = %s, %v, want %s", outcome, err, tt.wantOutcome)
+			}
+
+			exists, err := si.cleanupResourceExists(action)
+			if err != nil || exists != tt.wantExists {
+				t.Errorf("cleanupResourceExists() = %v, %v, want %v", exists, err, tt.wantExists)
+			}
+		})
+	}
+}
+
+func TestApplyCleanupCancelled(t *testing.T) {
+	si, fake := newTestLeftoverServiceInstance(t)
+
+	actions, err := si.PlanCleanup(context.Background())
+	if err != nil {
+		t.Fatalf("PlanCleanup: %v", err)
+	}
+	action := findCleanupAction(t, actions, CleanupKindImage, "leftover-rhcos")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The calls of the step are made with its context, so nothing is deleted.
+	outcome, err := si.ApplyCleanup(ctx, action)
+	if outcome != CleanupOutcomeFailed || err == nil {
+		t.Errorf("ApplyCleanup() = %s, %v, want %s with an error", outcome, err, CleanupOutcomeFailed)
+	}
+
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	if len(fake.powerVS["g-leftover"].Images) == 0 {
+		t.Errorf("ApplyCleanup() deleted the image of a cancelled step")
+	}
+}
+
+func TestServiceInstanceDiagnostics(t *testing.T) {
+	saved := diagnosticsDir
+	diagnosticsDir = t.TempDir()
+	t.Cleanup(func() { diagnosticsDir = saved })
+
+	services := newTestClusterServices(t, "bad", "g-bad", "good-vpc")
+
+	sis, _ := NewServiceInstanceAlt(context.Background(), services)
+	if len(sis) != 1 {
+		t.Fatalf("NewServiceInstanceAlt returned %d service instances, want 1", len(sis))
+	}
+
+	result := sis[0].ClusterStatus(context.Background())
+	if !hasCheck(result, "pvs.instance.diagnostics", CheckStatusOK) {
+		t.Fatalf("ClusterStatus() has no %s check pvs.instance.diagnostics%s", CheckStatusOK, dumpChecks(result))
+	}
+
+	filename := filepath.Join(diagnosticsDir, "bad-abc-master-0.txt")
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatalf("the diagnostics were not saved: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("the diagnostics have the permissions %v, want 0600", info.Mode().Perm())
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	for _, want := range []string{"Status:       ERROR", "CRITICAL (boot failed)", "C2008060", "CA000040", "B2003110"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("the diagnostics do not have %q:\n%s", want, content)
+		}
+	}
+	// Only the state and the SRCs are captured, no console session is opened.
+	if strings.Contains(string(content), "http") {
+		t.Errorf("the diagnostics have a URL:\n%s", content)
+	}
+
+	// The summary has the last SRCs, newest last.
+	for _, check := range result.Checks {
+		if check.ID == "pvs.instance.diagnostics" && !strings.Contains(check.Message, "C2008060 (2025-01-01T10:00:00Z), CA000040 (2025-01-01T10:01:00Z), B2003110 (2025-01-01T10:02:00Z)") {
+			t.Errorf("the summary does not have the last SRCs: %s", check.Message)
+		}
+	}
+}
@@ -40,6 +40,11 @@ import (
 	"github.com/golang-jwt/jwt"
 )
 
+const (
+	// The default IAM endpoint, override it with the IAM service endpoint.
+	defaultIAMEndpoint = "https://iam.cloud.ibm.com"
+)
+
 var (
 	defaultTimeout = 5 * time.Minute
 )
@@ -54,20 +59,29 @@ type Services struct {
 	//
 	bxSession *bxsession.Session
 
+	// The IAM authenticator which all of the IBM Cloud clients share.
+	authenticator core.Authenticator
+
 	//
 	user *User
 
 	// type VpcV1 struct
-	vpcSvc *vpcv1.VpcV1
+	vpcSvc VpcClient
 
 	// type ResourceControllerV2
-	controllerSvc *resourcecontrollerv2.ResourceControllerV2
+	controllerSvc ResourceControllerClient
 
 	// type TransitGatewayApisV1
-	tgClient *transitgatewayapisv1.TransitGatewayApisV1
+	tgClient TransitGatewayClient
 
 	// type ResourceManagerV2
-	managementSvc *resourcemanagerv2.ResourceManagerV2
+	managementSvc ResourceManagerClient
+
+	// The in-memory backend when the services are fake, otherwise nil.
+	fakeCloud *FakeCloud
+
+	// The results of the list calls made during this run.
+	cache *CallCache
 
 	//
 	ctx context.Context
@@ -85,11 +99,24 @@ type User struct {
 	generation int
 }
 
-func NewServices(metadata *Metadata, apiKey string) (*Services, error) {
+func NewServices(ctx context.Context, metadata *Metadata, apiKey string) (*Services, error) {
+	var (
+		credentials *CloudCredentials
+		err         error
+	)
+
+	credentials, err = NewCloudCredentials(apiKey, metadata.GetServiceEndpoint("IAM", defaultIAMEndpoint))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewServicesWithCredentials(ctx, metadata, credentials)
+}
+
+// NewServicesWithCredentials returns the services of a cluster which use the IAM login of
+// credentials, so that several clusters log in only once.
+func NewServicesWithCredentials(ctx context.Context, metadata *Metadata, credentials *CloudCredentials) (*Services, error) {
 	var (
-		ctx             context.Context
-		bxSession       *bxsession.Session
-		user            *User
 		region          string
 		vpcRegion       string
 		vpcSvc          *vpcv1.VpcV1
@@ -101,19 +128,6 @@ func NewServices(metadata *Metadata, apiKey string) (*Services, error) {
 		err             error
 	)
 
-	ctx = context.Background()
-
-	bxSession, err = InitBXService(apiKey)
-	if err != nil {
-		return nil, err
-	}
-	log.Debugf("NewServices: bxSession = %+v", bxSession)
-
-	user, err = fetchUserDetails(bxSession, 2)
-	if err != nil {
-		return nil, err
-	}
-
 	region = metadata.GetRegion()
 	log.Debugf("NewServices: region = %s", region)
 
@@ -123,7 +137,7 @@ func NewServices(metadata *Metadata, apiKey string) (*Services, error) {
 	}
 	log.Debugf("NewServices: vpcRegion = %s", vpcRegion)
 
-	vpcSvc, err = initVPCService(apiKey, vpcRegion)
+	vpcSvc, err = initVPCService(credentials.authenticator, metadata.GetServiceEndpoint("VPC", "https://"+vpcRegion+".iaas.cloud.ibm.com/v1"))
 	if err != nil {
 		return nil, err
 	}
@@ -131,23 +145,23 @@ func NewServices(metadata *Metadata, apiKey string) (*Services, error) {
 		return nil, fmt.Errorf("NewServices could not create vpcSvc")
 	}
 
-	controllerSvc, err = initCloudObjectStorageService(apiKey)
+	controllerSvc, err = initCloudObjectStorageService(credentials.authenticator, metadata.GetServiceEndpoint("ResourceController", resourcecontrollerv2.DefaultServiceURL))
 	if err != nil {
-		log.Fatalf("Error: NewServices: initCloudObjectStorageService returns %v", err)
+		log.Debugf("Error: NewServices: initCloudObjectStorageService returns %v", err)
 		return nil, err
 	}
 	log.Debugf("NewServices: controllerSvc = %+v", controllerSvc)
 
-	tgClient, err = initTransitGatewayClient(apiKey)
+	tgClient, err = initTransitGatewayClient(credentials.authenticator, metadata.GetServiceEndpoint("TransitGateway", transitgatewayapisv1.DefaultServiceURL))
 	if err != nil {
-		log.Fatalf("Error: NewServices: initTransitGatewayClient returns %v", err)
+		log.Debugf("Error: NewServices: initTransitGatewayClient returns %v", err)
 		return nil, err
 	}
 	log.Debugf("NewServices: tgClient = %+v", tgClient)
 
-	managementSvc, err = initManagementService(apiKey)
+	managementSvc, err = initManagementService(credentials.authenticator, metadata.GetServiceEndpoint("ResourceManager", resourcemanagerv2.DefaultServiceURL))
 	if err != nil {
-		log.Fatalf("Error: NewServices: initManagementService returns %v", err)
+		log.Debugf("Error: NewServices: initManagementService returns %v", err)
 		return nil, err
 	}
 	log.Debugf("NewServices: managementSvc = %+v", managementSvc)
@@ -156,21 +170,23 @@ func NewServices(metadata *Metadata, apiKey string) (*Services, error) {
 	log.Debugf("NewServices: resourceGroupID = %s", resourceGroupID)
 
 	services = &Services{
-		apiKey:          apiKey,
+		apiKey:          credentials.apiKey,
 		metadata:        metadata,
-		bxSession:       bxSession,
-		user:            user,
+		bxSession:       credentials.bxSession,
+		authenticator:   credentials.authenticator,
+		user:            credentials.user,
 		vpcSvc:          vpcSvc,
 		controllerSvc:   controllerSvc,
 		tgClient:        tgClient,
 		managementSvc:   managementSvc,
+		cache:           NewCallCache(),
 		ctx:             ctx,
 		resourceGroupID: resourceGroupID,
 	}
 
 	resourceGroupID, err = services.ResourceGroupNameToID(resourceGroupID)
 	if err != nil {
-		log.Fatalf("Error: NewServices: ResourceGroupNameToID returns %v", err)
+		log.Debugf("Error: NewServices: ResourceGroupNameToID returns %v", err)
 		return nil, err
 	}
 	log.Debugf("NewServices: resourceGroupID = %s", resourceGroupID)
@@ -179,32 +195,86 @@ func NewServices(metadata *Metadata, apiKey string) (*Services, error) {
 	return services, nil
 }
 
+// NewFakeServices returns services which use an in-memory FakeCloud, seeded from the
+// fixtures file, instead of IBM Cloud.
+func NewFakeServices(ctx context.Context, metadata *Metadata, fixtures string) (*Services, error) {
+	var (
+		fakeCloud       *FakeCloud
+		services        *Services
+		resourceGroupID string
+		err             error
+	)
+
+	fakeCloud, err = NewFakeCloud(fixtures)
+	if err != nil {
+		return nil, err
+	}
+
+	services = &Services{
+		metadata: metadata,
+		user: &User{
+			ID:         "fake",
+			Account:    fakeCloud.account,
+			cloudName:  "fake",
+			cloudType:  "public",
+			generation: 2,
+		},
+		vpcSvc:        fakeCloud,
+		controllerSvc: fakeCloud,
+		tgClient:      fakeCloud,
+		managementSvc: fakeCloud,
+		fakeCloud:     fakeCloud,
+		cache:         NewCallCache(),
+		ctx:           ctx,
+	}
+
+	resourceGroupID, err = services.ResourceGroupNameToID(metadata.GetResourceGroup())
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("NewFakeServices: resourceGroupID = %s", resourceGroupID)
+	services.resourceGroupID = resourceGroupID
+
+	return services, nil
+}
+
 func (svc *Services) GetApiKey() string {
 	return svc.apiKey
 }
 
+// GetAuthenticator returns the IAM authenticator to use when creating an IBM Cloud client.
+func (svc *Services) GetAuthenticator() core.Authenticator {
+	return svc.authenticator
+}
+
 func (svc *Services) GetMetadata() *Metadata {
 	return svc.metadata
 }
 
-func (svc *Services) GetVpcSvc() *vpcv1.VpcV1 {
+func (svc *Services) GetVpcSvc() VpcClient {
 	return svc.vpcSvc
 }
 
-func (svc *Services) GetControllerSvc() *resourcecontrollerv2.ResourceControllerV2 {
+func (svc *Services) GetControllerSvc() ResourceControllerClient {
 	return svc.controllerSvc
 }
 
-func (svc *Services) GetTgClient() *transitgatewayapisv1.TransitGatewayApisV1 {
+func (svc *Services) GetTgClient() TransitGatewayClient {
 	return svc.tgClient
 }
 
-func (svc *Services) GetUser() *User {
-	return svc.user
+// GetFakeCloud returns the in-memory backend, or nil when the services use IBM Cloud.
+func (svc *Services) GetFakeCloud() *FakeCloud {
+	return svc.fakeCloud
 }
 
-func (svc *Services) GetContextWithTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(svc.ctx, defaultTimeout)
+// GetCache returns the cache of the list calls made during this run.
+func (svc *Services) GetCache() *CallCache {
+	return svc.cache
+}
+
+func (svc *Services) GetUser() *User {
+	return svc.user
 }
 
 func (svc *Services) GetResourceGroupID() string {
@@ -218,10 +288,13 @@ func (svc *Services) ResourceGroupNameToID(resourceGroupName string) (string, er
 		err                       error
 	)
 
-	listResourceGroupsOptions = svc.managementSvc.NewListResourceGroupsOptions()
+	listResourceGroupsOptions = &resourcemanagerv2.ListResourceGroupsOptions{}
 	listResourceGroupsOptions.AccountID = &svc.user.Account
 
-	resourceGroups, _, err = svc.managementSvc.ListResourceGroups(listResourceGroupsOptions)
+	resourceGroups, err = cachedCall(svc.cache, cacheKey("rg.resourceGroups", svc.user.Account), func() (*resourcemanagerv2.ResourceGroupList, error) {
+		resourceGroups, _, err := svc.managementSvc.ListResourceGroupsWithContext(svc.ctx, listResourceGroupsOptions)
+		return resourceGroups, err
+	})
 	if err != nil {
 		return "", err
 	}
@@ -235,17 +308,17 @@ func (svc *Services) ResourceGroupNameToID(resourceGroupName string) (string, er
 	return "", fmt.Errorf("resource group name (%s) not found", resourceGroupName)
 }
 
-func InitBXService(apiKey string) (*bxsession.Session, error) {
+func InitBXService(apiKey string, tokenProviderEndpoint string) (*bxsession.Session, error) {
 	var (
-		bxSession             *bxsession.Session
-		tokenProviderEndpoint string = "https://iam.cloud.ibm.com"
-		err                   error
+		bxSession *bxsession.Session
+		err       error
 	)
 
 	bxSession, err = bxsession.New(&bluemix.Config{
 		BluemixAPIKey:         apiKey,
 		TokenProviderEndpoint: &tokenProviderEndpoint,
 		Debug:                 false,
+		HTTPClient:            cloudHTTPClient("IAM"),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("Error bxsession.New: %v", err)
@@ -253,6 +326,7 @@ func InitBXService(apiKey string) (*bxsession.Session, error) {
 	log.Debugf("InitBXService: bxSession = %v", bxSession)
 
 	tokenRefresher, err := authentication.NewIAMAuthRepository(bxSession.Config, &rest.Client{
+		HTTPClient: cloudHTTPClient("IAM"),
 		DefaultHeader: gohttp.Header{
 			"User-Agent": []string{http.UserAgent()},
 		},
@@ -319,12 +393,8 @@ func fetchUserDetails(bxSession *bxsession.Session, generation int) (*User, erro
 	return &user, nil
 }
 
-func initVPCService(apiKey string, region string) (*vpcv1.VpcV1, error) {
+func initVPCService(authenticator core.Authenticator, serviceURL string) (*vpcv1.VpcV1, error) {
 	var (
-		authenticator core.Authenticator = &core.IamAuthenticator{
-			ApiKey: apiKey,
-		}
-
 		// type VpcV1 struct
 		vpcSvc *vpcv1.VpcV1
 
@@ -334,59 +404,49 @@ func initVPCService(apiKey string, region string) (*vpcv1.VpcV1, error) {
 	// https://raw.githubusercontent.com/IBM/vpc-go-sdk/master/vpcv1/vpc_v1.go
 	vpcSvc, err = vpcv1.NewVpcV1(&vpcv1.VpcV1Options{
 		Authenticator: authenticator,
-		URL:           "https://" + region + ".iaas.cloud.ibm.com/v1",
+		URL:           serviceURL,
 	})
 	log.Debugf("initVPCService: vpc.vpcSvc = %v", vpcSvc)
 	if err != nil {
-		log.Fatalf("Error: vpcv1.NewVpcV1 returns %v", err)
+		log.Debugf("Error: vpcv1.NewVpcV1 returns %v", err)
 		return nil, err
 	}
 	if vpcSvc == nil {
 		panic(fmt.Errorf("Error: vpcSvc is empty?"))
 	}
+	useCloudHTTPClient("VPC", vpcSvc.Service)
 
 	return vpcSvc, nil
 }
 
-func initCloudObjectStorageService(apiKey string) (*resourcecontrollerv2.ResourceControllerV2, error) {
+func initCloudObjectStorageService(authenticator core.Authenticator, serviceURL string) (*resourcecontrollerv2.ResourceControllerV2, error) {
 	var (
-		authenticator core.Authenticator = &core.IamAuthenticator{
-			ApiKey: apiKey,
-		}
 		controllerSvc *resourcecontrollerv2.ResourceControllerV2
 		err           error
 	)
 
 	controllerSvc, err = resourcecontrollerv2.NewResourceControllerV2(&resourcecontrollerv2.ResourceControllerV2Options{
 		Authenticator: authenticator,
+		URL:           serviceURL,
 	})
 	if err != nil {
-		log.Fatalf("Error: resourcecontrollerv2.NewResourceControllerV2 returns %v", err)
+		log.Debugf("Error: resourcecontrollerv2.NewResourceControllerV2 returns %v", err)
 		return nil, err
 	}
 	if controllerSvc == nil {
 		panic(fmt.Errorf("Error: controllerSvc is empty?"))
 	}
+	useCloudHTTPClient("ResourceController", controllerSvc.Service)
 
 	return controllerSvc, nil
 }
 
-func initResourceControllerService(apiKey string) (*resourcecontrollerv2.ResourceControllerV2, error) {
+func initResourceControllerService(authenticator core.Authenticator) (*resourcecontrollerv2.ResourceControllerV2, error) {
 	var (
-		authenticator core.Authenticator
 		controllerSvc *resourcecontrollerv2.ResourceControllerV2
 		err           error
 	)
 
-	authenticator = &core.IamAuthenticator{
-		ApiKey: apiKey,
-	}
-
-	err = authenticator.Validate()
-	if err != nil {
-		return nil, fmt.Errorf("NewServiceInstance: authenticator.Validate: %w", err)
-	}
-
 	// Instantiate the service with an API key based IAM authenticator
 	controllerSvc, err = resourcecontrollerv2.NewResourceControllerV2(&resourcecontrollerv2.ResourceControllerV2Options{
 		Authenticator: authenticator,
@@ -396,30 +456,22 @@ func initResourceControllerService(apiKey string) (*resourcecontrollerv2.Resourc
 	if err != nil {
 		return nil, fmt.Errorf("NewServiceInstance: creating ControllerV2 Service: %w", err)
 	}
+	useCloudHTTPClient("ResourceController", controllerSvc.Service)
 
 	return controllerSvc, nil
 }
 
-func initTransitGatewayClient(apiKey string) (*transitgatewayapisv1.TransitGatewayApisV1, error) {
+func initTransitGatewayClient(authenticator core.Authenticator, serviceURL string) (*transitgatewayapisv1.TransitGatewayApisV1, error) {
 	var (
-		authenticator core.Authenticator
-		versionDate   = "2023-07-04"
-		tgOptions     *transitgatewayapisv1.TransitGatewayApisV1Options
-		tgClient      *transitgatewayapisv1.TransitGatewayApisV1
-		err           error
+		versionDate = "2023-07-04"
+		tgOptions   *transitgatewayapisv1.TransitGatewayApisV1Options
+		tgClient    *transitgatewayapisv1.TransitGatewayApisV1
+		err         error
 	)
 
-	authenticator = &core.IamAuthenticator{
-		ApiKey: apiKey,
-	}
-
-	err = authenticator.Validate()
-	if err != nil {
-		return nil, fmt.Errorf("initTransitGatewayClient: authenticator.Validate: %w", err)
-	}
-
 	tgOptions = &transitgatewayapisv1.TransitGatewayApisV1Options{
 		Authenticator: authenticator,
+		URL:           serviceURL,
 		Version:       &versionDate,
 	}
 
@@ -427,31 +479,29 @@ func initTransitGatewayClient(apiKey string) (*transitgatewayapisv1.TransitGatew
 	if err != nil {
 		return nil, fmt.Errorf("initTransitGatewayClient: NewTransitGatewayApisV1: %w", err)
 	}
+	useCloudHTTPClient("TransitGateway", tgClient.Service)
 	log.Debugf("initTransitGatewayClient: tgClient = %+v", tgClient)
 
 	return tgClient, nil
 }
 
-func initManagementService(apiKey string) (*resourcemanagerv2.ResourceManagerV2, error) {
+func initManagementService(authenticator core.Authenticator, serviceURL string) (*resourcemanagerv2.ResourceManagerV2, error) {
 	var (
-		authenticator core.Authenticator
 		options       *resourcemanagerv2.ResourceManagerV2Options
 		managementSvc *resourcemanagerv2.ResourceManagerV2
 		err           error
 	)
 
-	authenticator = &core.IamAuthenticator{
-		ApiKey: apiKey,
-	}
-
 	options = &resourcemanagerv2.ResourceManagerV2Options{
 		Authenticator: authenticator,
+		URL:           serviceURL,
 	}
 
 	managementSvc, err = resourcemanagerv2.NewResourceManagerV2(options)
 	if err != nil {
 		return nil, err
 	}
+	useCloudHTTPClient("ResourceManager", managementSvc.Service)
 
 	return managementSvc, nil
 }
@@ -0,0 +1,442 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	gohttp "net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+var (
+	// Set by -trace or -traceFile, otherwise nil.
+	tracer *Tracer
+)
+
+// Tracer records every IBM Cloud API call of a run, so that a summary of where the time
+// went can be printed at exit and, with -traceFile, be saved as an OpenTelemetry trace.
+type Tracer struct {
+	// The command which is traced, it names the root span.
+	command string
+
+	// Where to save the OTLP JSON trace, or "" to only print the summary.
+	fileName string
+
+	start   time.Time
+	traceID string
+	spanID  string
+
+	lock  sync.Mutex
+	calls []*TracedCall
+}
+
+// TracedCall is one IBM Cloud API call, including its retries.
+type TracedCall struct {
+	// The service endpoint name, for example VPC or Power.
+	Service string
+
+	// The method and the path with the IDs replaced, for example "GET /v1/vpcs/{id}".
+	Operation string
+
+	URL        string
+	StatusCode int
+	Retries    int
+	Err        error
+
+	Start   time.Time
+	Latency time.Duration
+
+	spanID string
+}
+
+// NewTracer returns the tracer which -trace and -traceFile ask for, or nil when the calls
+// should not be traced.  Setting a trace file turns on tracing.
+func NewTracer(command string, trace string, fileName string) (*Tracer, error) {
+	switch strings.ToLower(trace) {
+	case "true":
+	case "false":
+		if fileName == "" {
+			return nil, nil
+		}
+	default:
+		return nil, fmt.Errorf("Error: trace is not true/false (%s)", trace)
+	}
+
+	return &Tracer{
+		command:  command,
+		fileName: fileName,
+		start:    time.Now(),
+		traceID:  newTraceID(16),
+		spanID:   newTraceID(8),
+		calls:    make([]*TracedCall, 0),
+	}, nil
+}
+
+// newTraceID returns a random hex ID of size bytes.
+func newTraceID(size int) string {
+	var (
+		id = make([]byte, size)
+	)
+
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+func (tracer *Tracer) add(call *TracedCall) {
+	tracer.lock.Lock()
+	defer tracer.lock.Unlock()
+
+	tracer.calls = append(tracer.calls, call)
+}
+
+// TraceTransport records the calls of one IBM Cloud service.  The latency of a call is the
+// time until its response headers arrived, over all of its retries.
+type TraceTransport struct {
+	next gohttp.RoundTripper
+
+	service string
+
+	tracer *Tracer
+}
+
+// RoundTrip implements gohttp.RoundTripper.
+func (transport *TraceTransport) RoundTrip(request *gohttp.Request) (*gohttp.Response, error) {
+	var (
+		retries  int
+		call     *TracedCall
+		response *gohttp.Response
+		err      error
+	)
+
+	call = &TracedCall{
+		Service:   transport.service,
+		Operation: traceOperation(request),
+		URL:       redactURL(request.URL.String()),
+		Start:     time.Now(),
+		spanID:    newTraceID(8),
+	}
+
+	request = request.WithContext(context.WithValue(request.Context(), retryCountKey{}, &retries))
+
+	response, err = transport.next.RoundTrip(request)
+
+	call.Latency = time.Since(call.Start)
+	call.Retries = retries
+	call.Err = err
+	if response != nil {
+		call.StatusCode = response.StatusCode
+	}
+	transport.tracer.add(call)
+
+	return response, err
+}
+
+// traceOperation returns the method and the path of the request with the segments which
+// look like IDs or CRNs replaced by {id}, so that the calls of one operation add up.
+func traceOperation(request *gohttp.Request) string {
+	var (
+		segments []string
+	)
+
+	segments = strings.Split(strings.Trim(request.URL.Path, "/"), "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "crn:") || (len(segment) >= 8 && strings.ContainsAny(segment, "0123456789")) {
+			segments[i] = "{id}"
+		}
+	}
+
+	return request.Method + " /" + strings.Join(segments, "/")
+}
+
+// traceStats adds up the calls of a service or of an operation.
+type traceStats struct {
+	name    string
+	calls   int
+	errors  int
+	retries int
+	total   time.Duration
+	max     time.Duration
+}
+
+func (stats *traceStats) add(call *TracedCall) {
+	stats.calls++
+	if call.Err != nil || call.StatusCode >= 400 {
+		stats.errors++
+	}
+	stats.retries += call.Retries
+	stats.total += call.Latency
+	if call.Latency > stats.max {
+		stats.max = call.Latency
+	}
+}
+
+// summarize returns the stats of the calls grouped by key, the slowest first.
+func summarize(calls []*TracedCall, key func(*TracedCall) string) []*traceStats {
+	var (
+		byKey  = make(map[string]*traceStats)
+		result = make([]*traceStats, 0)
+	)
+
+	for _, call := range calls {
+		stats, ok := byKey[key(call)]
+		if !ok {
+			stats = &traceStats{name: key(call)}
+			byKey[key(call)] = stats
+			result = append(result, stats)
+		}
+		stats.add(call)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].total > result[j].total
+	})
+
+	return result
+}
+
+func writeTraceStats(w io.Writer, title string, stats []*traceStats) {
+	var (
+		tw *tabwriter.Writer
+	)
+
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\tCALLS\tERRORS\tRETRIES\tTOTAL\tAVERAGE\tMAX\t\n", title)
+	for _, s := range stats {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%v\t%v\t%v\t\n",
+			s.name,
+			s.calls,
+			s.errors,
+			s.retries,
+			s.total.Round(time.Millisecond),
+			(s.total / time.Duration(s.calls)).Round(time.Millisecond),
+			s.max.Round(time.Millisecond))
+	}
+	tw.Flush()
+}
+
+// WriteSummary writes the tables of the calls by service and by operation.
+func (tracer *Tracer) WriteSummary(w io.Writer) {
+	tracer.lock.Lock()
+	defer tracer.lock.Unlock()
+
+	fmt.Fprintf(w, "\n%d IBM Cloud API calls in %v\n", len(tracer.calls), time.Since(tracer.start).Round(time.Millisecond))
+	if len(tracer.calls) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	writeTraceStats(w, "SERVICE", summarize(tracer.calls, func(call *TracedCall) string {
+		return call.Service
+	}))
+
+	fmt.Fprintln(w)
+	writeTraceStats(w, "OPERATION", summarize(tracer.calls, func(call *TracedCall) string {
+		return call.Service + " " + call.Operation
+	}))
+}
+
+// The OTLP JSON encoding of a trace, see
+// https://github.com/open-telemetry/opentelemetry-proto/blob/main/examples/trace.json
+type otlpTrace struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	otlpSpanKindInternal = 1
+	otlpSpanKindClient   = 3
+
+	otlpStatusCodeOK    = 1
+	otlpStatusCodeError = 2
+)
+
+func otlpString(key string, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: &value}}
+}
+
+func otlpInt(key string, value int) otlpAttribute {
+	var (
+		s = strconv.Itoa(value)
+	)
+
+	return otlpAttribute{Key: key, Value: otlpValue{IntValue: &s}}
+}
+
+func otlpTime(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// otlpSpans returns a root span for the command with a client span for every call.
+func (tracer *Tracer) otlpSpans(end time.Time) []otlpSpan {
+	var (
+		spans = make([]otlpSpan, 0, len(tracer.calls)+1)
+		span  otlpSpan
+	)
+
+	spans = append(spans, otlpSpan{
+		TraceID:           tracer.traceID,
+		SpanID:            tracer.spanID,
+		Name:              tracer.command,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: otlpTime(tracer.start),
+		EndTimeUnixNano:   otlpTime(end),
+		Status:            otlpStatus{Code: otlpStatusCodeOK},
+	})
+
+	for _, call := range tracer.calls {
+		span = otlpSpan{
+			TraceID:           tracer.traceID,
+			SpanID:            call.spanID,
+			ParentSpanID:      tracer.spanID,
+			Name:              call.Service + " " + call.Operation,
+			Kind:              otlpSpanKindClient,
+			StartTimeUnixNano: otlpTime(call.Start),
+			EndTimeUnixNano:   otlpTime(call.Start.Add(call.Latency)),
+			Attributes: []otlpAttribute{
+				otlpString("cloud.service", call.Service),
+				otlpString("http.request.method", strings.SplitN(call.Operation, " ", 2)[0]),
+				otlpString("url.full", call.URL),
+				otlpInt("http.request.resend_count", call.Retries),
+			},
+			Status: otlpStatus{Code: otlpStatusCodeOK},
+		}
+
+		if call.StatusCode != 0 {
+			span.Attributes = append(span.Attributes, otlpInt("http.response.status_code", call.StatusCode))
+		}
+
+		if call.Err != nil {
+			span.Status = otlpStatus{Code: otlpStatusCodeError, Message: call.Err.Error()}
+		} else if call.StatusCode >= 400 {
+			span.Status = otlpStatus{Code: otlpStatusCodeError, Message: gohttp.StatusText(call.StatusCode)}
+		}
+
+		spans = append(spans, span)
+	}
+
+	return spans
+}
+
+// WriteFile saves the calls as an OTLP JSON trace, which can be sent to an OpenTelemetry
+// collector or be loaded into Jaeger.
+func (tracer *Tracer) WriteFile() error {
+	var (
+		trace   otlpTrace
+		content []byte
+		err     error
+	)
+
+	tracer.lock.Lock()
+	defer tracer.lock.Unlock()
+
+	trace = otlpTrace{
+		ResourceSpans: []otlpResourceSpans{
+			{
+				Resource: otlpResource{
+					Attributes: []otlpAttribute{
+						otlpString("service.name", "PowerVS-Check"),
+						otlpString("service.version", version),
+					},
+				},
+				ScopeSpans: []otlpScopeSpans{
+					{
+						Scope: otlpScope{Name: "PowerVS-Check", Version: release},
+						Spans: tracer.otlpSpans(time.Now()),
+					},
+				},
+			},
+		},
+	}
+
+	content, err = json.MarshalIndent(trace, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error: Could not encode the trace (%v)", err)
+	}
+
+	err = os.WriteFile(tracer.fileName, content, 0644)
+	if err != nil {
+		return fmt.Errorf("Error: Could not write the trace file %s (%v)", tracer.fileName, err)
+	}
+
+	return nil
+}
+
+// Finish prints the summary of the calls and saves the trace file, if one was asked for.
+func (tracer *Tracer) Finish(w io.Writer) error {
+	tracer.WriteSummary(w)
+
+	if tracer.fileName == "" {
+		return nil
+	}
+
+	return tracer.WriteFile()
+}
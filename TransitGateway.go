@@ -28,8 +28,6 @@ import (
 type TransitGateway struct {
 	name string
 
-	tgClient *transitgatewayapisv1.TransitGatewayApisV1
-
 	services *Services
 
 	innerTg *transitgatewayapisv1.TransitGateway
@@ -39,14 +37,25 @@ const (
 	tgObjectName = "Transit Gateway"
 )
 
-func NewTransitGateway(services *Services) ([]RunnableObject, []error) {
+func init() {
+	RegisterRunnableObject(RunnableObjectRegistration{
+		Name:         tgObjectName,
+		Key:          "tg",
+		New:          NewTransitGateway,
+		Modes:        []RunMode{RunModeCI, RunModeCreate},
+		Dependencies: []string{vpcObjectName, siObjectName},
+		Order:        30,
+	})
+}
+
+func NewTransitGateway(ctx context.Context, services *Services) ([]RunnableObject, []error) {
 	var (
 		tgs      []*TransitGateway
 		errs     []error
 		ros      []RunnableObject
 	)
 
-	tgs, errs = innerNewTransitGateway(services)
+	tgs, errs = innerNewTransitGateway(ctx, services)
 
 	ros = make([]RunnableObject, len(tgs))
 	// Go does not support type converting the entire array.
@@ -58,15 +67,14 @@ func NewTransitGateway(services *Services) ([]RunnableObject, []error) {
 	return ros, errs
 }
 
-func NewTransitGatewayAlt(services *Services) ([]*TransitGateway, []error) {
-	return innerNewTransitGateway(services)
+func NewTransitGatewayAlt(ctx context.Context, services *Services) ([]*TransitGateway, []error) {
+	return innerNewTransitGateway(ctx, services)
 }
 
-func innerNewTransitGateway(services *Services) ([]*TransitGateway, []error) {
+func innerNewTransitGateway(ctx context.Context, services *Services) ([]*TransitGateway, []error) {
 	var (
 		tgName         string
-		tgClient       *transitgatewayapisv1.TransitGatewayApisV1
-		ctx            context.Context
+		tgClient       TransitGatewayClient
 		cancel         context.CancelFunc
 		foundInstances []string
 		tg             *TransitGateway
@@ -94,11 +102,11 @@ func innerNewTransitGateway(services *Services) ([]*TransitGateway, []error) {
 
 	tgClient = services.GetTgClient()
 
-	ctx, cancel = services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	if fUseTagSearch {
-		foundInstances, err = listByTag(TagTypeTransitGateway, services)
+		foundInstances, err = listByTag(ctx, TagTypeTransitGateway, services)
 	} else {
 		foundInstances, err = findTransitGateway(tgClient, ctx, tgName)
 	}
@@ -125,7 +133,7 @@ func innerNewTransitGateway(services *Services) ([]*TransitGateway, []error) {
 			innerTgName              string
 		)
 
-		getTransitGatewayOptions = tgClient.NewGetTransitGatewayOptions(instanceID)
+		getTransitGatewayOptions = &transitgatewayapisv1.GetTransitGatewayOptions{ID: &instanceID}
 
 		innerTg, _, err = tgClient.GetTransitGatewayWithContext(ctx, getTransitGatewayOptions)
 		if err != nil {
@@ -160,7 +168,7 @@ func innerNewTransitGateway(services *Services) ([]*TransitGateway, []error) {
 }
 
 // findTransitGateway find a Transit Gateway matching by name in the IBM Cloud.
-func findTransitGateway(tgClient *transitgatewayapisv1.TransitGatewayApisV1, ctx context.Context, name string) ([]string, error) {
+func findTransitGateway(tgClient TransitGatewayClient, ctx context.Context, name string) ([]string, error) {
 	var (
 		listTransitGatewaysOptions *transitgatewayapisv1.ListTransitGatewaysOptions
 		gatewayCollection          *transitgatewayapisv1.TransitGatewayCollection
@@ -194,7 +202,7 @@ func findTransitGateway(tgClient *transitgatewayapisv1.TransitGatewayApisV1, ctx
 	default:
 	}
 
-	listTransitGatewaysOptions = tgClient.NewListTransitGatewaysOptions()
+	listTransitGatewaysOptions = &transitgatewayapisv1.ListTransitGatewaysOptions{}
 	listTransitGatewaysOptions.Limit = &perPage
 
 	for moreData {
@@ -256,7 +264,7 @@ func findTransitGateway(tgClient *transitgatewayapisv1.TransitGatewayApisV1, ctx
 	if !foundOne {
 		log.Debugf("listTransitGatewaysByName: NO matching transit gateway against: %s", name)
 
-		listTransitGatewaysOptions = tgClient.NewListTransitGatewaysOptions()
+		listTransitGatewaysOptions = &transitgatewayapisv1.ListTransitGatewaysOptions{}
 		listTransitGatewaysOptions.Limit = &perPage
 		moreData = true
 
@@ -310,10 +318,9 @@ func findTransitGateway(tgClient *transitgatewayapisv1.TransitGatewayApisV1, ctx
 	return nil, nil
 }
 
-func (tg *TransitGateway) CheckConnections() (int, int, error) {
+func (tg *TransitGateway) CheckConnections(ctx context.Context) (int, int, error) {
 	var (
-		tgClient                     *transitgatewayapisv1.TransitGatewayApisV1
-		ctx                          context.Context
+		tgClient                     TransitGatewayClient
 		cancel                       context.CancelFunc
 		listConnectionsOptions       *transitgatewayapisv1.ListConnectionsOptions
 		transitConnectionCollections *transitgatewayapisv1.TransitConnectionCollection
@@ -332,10 +339,10 @@ func (tg *TransitGateway) CheckConnections() (int, int, error) {
 
 	tgClient = tg.services.GetTgClient()
 
-	ctx, cancel = tg.services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	listConnectionsOptions = tg.tgClient.NewListConnectionsOptions()
+	listConnectionsOptions = &transitgatewayapisv1.ListConnectionsOptions{}
 	listConnectionsOptions.SetLimit(perPage)
 
 	for moreData {
@@ -408,57 +415,55 @@ func (tg *TransitGateway) ObjectName() (string, error) {
 	return tgObjectName, nil
 }
 
-func (tg *TransitGateway) Run() error {
+func (tg *TransitGateway) Run(ctx context.Context) error {
 	// Nothing to do here!
 	return nil
 }
 
-func (tg *TransitGateway) CiStatus(shouldClean bool) {
+func (tg *TransitGateway) CiStatus(ctx context.Context, shouldClean bool) *ObjectResult {
+	return NewObjectResult(tg, tg.name)
 }
 
-func (tg *TransitGateway) ClusterStatus() {
+func (tg *TransitGateway) ClusterStatus(ctx context.Context) *ObjectResult {
 	var (
+		result   *ObjectResult
 		pvsCount int
 		vpcCount int
-		isOk     bool
 		err      error
 	)
 
+	result = NewObjectResult(tg, tg.name)
+
 	if tg.innerTg == nil {
-		fmt.Printf("%s is NOTOK. Could not find a TG named %s\n", tgObjectName, tg.name)
-		return
+		result.AddError("tg.exists", "Could not find a TG named %s", tg.name)
+		return result
 	}
 
 	if *tg.innerTg.Status != "available" {
-		fmt.Printf("%s %s is NOTOK.  The status is %s\n", tgObjectName, tg.name, *tg.innerTg.Status)
-		return
+		result.AddNotOK("tg.status", "The status is %s", *tg.innerTg.Status).
+			WithEvidence("status", *tg.innerTg.Status)
+		return result
 	}
 
-	isOk = true
-
-	pvsCount, vpcCount, err = tg.CheckConnections()
+	pvsCount, vpcCount, err = tg.CheckConnections(ctx)
 	if err != nil {
-		fmt.Printf("%s %s is NOTOK. Received %v checking the connections\n", tgObjectName, tg.name, err)
-		isOk = false
+		result.AddError("tg.connections", "Received %v checking the connections", err)
+		return result
 	}
 
 	if pvsCount == 1 {
-		fmt.Printf("%s %s has a connection to a %s\n", tgObjectName, tg.name, siObjectName)
+		result.AddOK("tg.connection.pvs", "has a connection to a %s", siObjectName)
 	} else {
-		isOk = false
+		result.AddNotOK("tg.connection.pvs", "expecting 1 connection to a %s, found %d", siObjectName, pvsCount).
+			WithEvidence("count", fmt.Sprintf("%d", pvsCount))
 	}
 
 	if vpcCount == 1 {
-		fmt.Printf("%s %s has a connection to a %s\n", tgObjectName, tg.name, vpcObjectName)
+		result.AddOK("tg.connection.vpc", "has a connection to a %s", vpcObjectName)
 	} else {
-		isOk = false
-	}
-
-	if isOk {
-		fmt.Printf("%s %s is OK.\n", tgObjectName, tg.name)
+		result.AddNotOK("tg.connection.vpc", "expecting 1 connection to a %s, found %d", vpcObjectName, vpcCount).
+			WithEvidence("count", fmt.Sprintf("%d", vpcCount))
 	}
-}
 
-func (tg *TransitGateway) Priority() (int, error) {
-	return 90, nil
+	return result
 }
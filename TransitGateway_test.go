@@ -0,0 +1,88 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTransitGatewayClusterStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		clusterName string
+		wantStatus  CheckStatus
+		wantChecks  []string
+	}{
+		{"connected TG", "good", CheckStatusOK, []string{"tg.connection.pvs", "tg.connection.vpc"}},
+		{"TG without a VPC connection", "bad", CheckStatusNotOK, []string{"tg.connection.vpc"}},
+		{"failed TG", "broken", CheckStatusNotOK, []string{"tg.status"}},
+		{"TG not found", "lost", CheckStatusError, []string{"tg.exists"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestClusterServices(t, tt.clusterName, "g-good", "good-vpc")
+
+			tgs, _ := NewTransitGatewayAlt(context.Background(), services)
+			if len(tgs) != 1 {
+				t.Fatalf("NewTransitGatewayAlt returned %d TGs, want 1", len(tgs))
+			}
+
+			result := tgs[0].ClusterStatus(context.Background())
+			if result.Status != tt.wantStatus {
+				t.Errorf("ClusterStatus().Status = %s, want %s%s", result.Status, tt.wantStatus, dumpChecks(result))
+			}
+			for _, id := range tt.wantChecks {
+				if !hasCheck(result, id, tt.wantStatus) {
+					t.Errorf("ClusterStatus() has no %s check %s%s", tt.wantStatus, id, dumpChecks(result))
+				}
+			}
+		})
+	}
+}
+
+func TestTransitGatewayCiStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		tgName     string
+		wantTGs    int
+		wantStatus CheckStatus
+	}{
+		{"existing TG", "good-abc-tg", 1, CheckStatusOK},
+		{"failed TG", "broken-abc-tg", 1, CheckStatusOK},
+		{"TG not found", "lost-abc-tg", 1, CheckStatusOK},
+		{"no TG in the metadata", "", 0, CheckStatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestCIServices(t, "ci-empty", "good-vpc", tt.tgName)
+
+			tgs, _ := NewTransitGatewayAlt(context.Background(), services)
+			if len(tgs) != tt.wantTGs {
+				t.Fatalf("NewTransitGatewayAlt returned %d TGs, want %d", len(tgs), tt.wantTGs)
+			}
+
+			// check-ci only looks for the leftovers of the service instance, a TG has none.
+			for _, tg := range tgs {
+				result := tg.CiStatus(context.Background(), false)
+				if result.Status != tt.wantStatus || len(result.Checks) != 0 {
+					t.Errorf("CiStatus() = %s with %d checks, want %s with none%s", result.Status, len(result.Checks), tt.wantStatus, dumpChecks(result))
+				}
+			}
+		})
+	}
+}
@@ -40,14 +40,24 @@ const (
 	vpcObjectName = "Virtual Private Cloud"
 )
 
-func NewVpc(services *Services) ([]RunnableObject, []error) {
+func init() {
+	RegisterRunnableObject(RunnableObjectRegistration{
+		Name:  vpcObjectName,
+		Key:   "vpc",
+		New:   NewVpc,
+		Modes: []RunMode{RunModeCI, RunModeCreate, RunModeJumpbox},
+		Order: 10,
+	})
+}
+
+func NewVpc(ctx context.Context, services *Services) ([]RunnableObject, []error) {
 	var (
 		vpcs     []*Vpc
 		errs     []error
 		ros      []RunnableObject
 	)
 
-	vpcs, errs = innerNewVpc(services)
+	vpcs, errs = innerNewVpc(ctx, services)
 
 	ros = make([]RunnableObject, len(vpcs))
 	// Go does not support type converting the entire array.
@@ -59,17 +69,16 @@ func NewVpc(services *Services) ([]RunnableObject, []error) {
 	return ros, errs
 }
 
-func NewVpcAlt(services *Services) ([]*Vpc, []error) {
-	return innerNewVpc(services)
+func NewVpcAlt(ctx context.Context, services *Services) ([]*Vpc, []error) {
+	return innerNewVpc(ctx, services)
 }
 
-func innerNewVpc(services *Services) ([]*Vpc, []error) {
+func innerNewVpc(ctx context.Context, services *Services) ([]*Vpc, []error) {
 	var (
 		vpcName        string
 		region         string
 		vpcRegion      string
-		vpcSvc         *vpcv1.VpcV1
-		ctx            context.Context
+		vpcSvc         VpcClient
 		cancel         context.CancelFunc
 		foundInstances []string
 		vpc            *Vpc
@@ -106,11 +115,11 @@ func innerNewVpc(services *Services) ([]*Vpc, []error) {
 
 	vpcSvc = services.GetVpcSvc()
 
-	ctx, cancel = services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	if fUseTagSearch {
-		foundInstances, err = listByTag(TagTypeVPC, services)
+		foundInstances, err = listByTag(ctx, TagTypeVPC, services)
 	} else {
 		foundInstances, err = findVpcs(vpcName, vpcSvc, ctx)
 	}
@@ -137,7 +146,7 @@ func innerNewVpc(services *Services) ([]*Vpc, []error) {
 			innerVpcName string
 		)
 
-		getOptions = vpcSvc.NewGetVPCOptions(instanceID)
+		getOptions = &vpcv1.GetVPCOptions{ID: ptr.To(instanceID)}
 
 		innerVpc, _, err = vpcSvc.GetVPCWithContext(ctx, getOptions)
 		if err != nil {
@@ -171,7 +180,7 @@ func innerNewVpc(services *Services) ([]*Vpc, []error) {
 	return vpcs, errs
 }
 
-func findVpcs(name string, vpcSvc *vpcv1.VpcV1, ctx context.Context) ([]string, error) {
+func findVpcs(name string, vpcSvc VpcClient, ctx context.Context) ([]string, error) {
 	var (
 		// type ListVpcsOptions
 		options  *vpcv1.ListVpcsOptions
@@ -198,7 +207,7 @@ func findVpcs(name string, vpcSvc *vpcv1.VpcV1, ctx context.Context) ([]string,
 		return false
 	}
 
-	options = vpcSvc.NewListVpcsOptions()
+	options = &vpcv1.ListVpcsOptions{}
 	options.SetLimit(perPage)
 
 	foundInstances = make([]string, 0)
@@ -206,7 +215,7 @@ func findVpcs(name string, vpcSvc *vpcv1.VpcV1, ctx context.Context) ([]string,
 	for moreData {
 		vpcs, response, err = vpcSvc.ListVpcsWithContext(ctx, options)
 		if err != nil {
-			log.Fatalf("Error: findVpcs: ListVpcs: response = %v, err = %v", response, err)
+			log.Debugf("Error: findVpcs: ListVpcs: response = %v, err = %v", response, err)
 			return nil, err
 		}
 
@@ -225,7 +234,7 @@ func findVpcs(name string, vpcSvc *vpcv1.VpcV1, ctx context.Context) ([]string,
 			log.Debugf("findVpcs: Next = %+v", *vpcs.Next)
 			start, err := vpcs.GetNextStart()
 			if err != nil {
-				log.Fatalf("Error: findVpcs: GetNextStart returns %v", err)
+				log.Debugf("Error: findVpcs: GetNextStart returns %v", err)
 				return nil, err
 			}
 			log.Debugf("findVpcs: start = %+v", *start)
@@ -239,10 +248,9 @@ func findVpcs(name string, vpcSvc *vpcv1.VpcV1, ctx context.Context) ([]string,
 	return foundInstances, nil
 }
 
-func (vpc Vpc) ListSubnets() ([]*vpcv1.Subnet, error) {
+func (vpc Vpc) ListSubnets(ctx context.Context) ([]*vpcv1.Subnet, error) {
 	var (
-		vpcSvc      *vpcv1.VpcV1
-		ctx         context.Context
+		vpcSvc      VpcClient
 		cancel      context.CancelFunc
 		groupID     string
 		listOptions *vpcv1.ListSubnetsOptions
@@ -260,7 +268,7 @@ func (vpc Vpc) ListSubnets() ([]*vpcv1.Subnet, error) {
 
 	vpcSvc = vpc.services.GetVpcSvc()
 
-	ctx, cancel = vpc.services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	groupID = vpc.services.GetMetadata().GetResourceGroup()
@@ -270,7 +278,7 @@ func (vpc Vpc) ListSubnets() ([]*vpcv1.Subnet, error) {
 	}
 	log.Debugf("groupID = %s", groupID)
 
-	listOptions = vpcSvc.NewListSubnetsOptions()
+	listOptions = &vpcv1.ListSubnetsOptions{}
 	listOptions.SetLimit(perPage)
 	listOptions.SetResourceGroupID(groupID)
 	log.Debugf("listOptions = %+v", listOptions)
@@ -280,7 +288,7 @@ func (vpc Vpc) ListSubnets() ([]*vpcv1.Subnet, error) {
 	for moreData {
 		subnets, response, err = vpcSvc.ListSubnetsWithContext(ctx, listOptions)
 		if err != nil {
-			log.Fatalf("Error: ListSubnets: ListSubnets: response = %v, err = %v", response, err)
+			log.Debugf("Error: ListSubnets: ListSubnets: response = %v, err = %v", response, err)
 			return nil, err
 		}
 
@@ -297,7 +305,7 @@ func (vpc Vpc) ListSubnets() ([]*vpcv1.Subnet, error) {
 			log.Debugf("ListSubnets: Next = %+v", *subnets.Next)
 			start, err := subnets.GetNextStart()
 			if err != nil {
-				log.Fatalf("Error: ListSubnets: GetNextStart returns %v", err)
+				log.Debugf("Error: ListSubnets: GetNextStart returns %v", err)
 				return nil, err
 			}
 			log.Debugf("ListSubnets: start = %+v", *start)
@@ -311,10 +319,9 @@ func (vpc Vpc) ListSubnets() ([]*vpcv1.Subnet, error) {
 	return result, nil
 }
 
-func (vpc Vpc) FindSecurityGroupsByVPC() ([]string, error) {
+func (vpc Vpc) FindSecurityGroupsByVPC(ctx context.Context) ([]string, error) {
 	var (
-		vpcSvc      *vpcv1.VpcV1
-		ctx         context.Context
+		vpcSvc      VpcClient
 		cancel      context.CancelFunc
 		groupID     string
 		getOptions  *vpcv1.GetVPCDefaultSecurityGroupOptions
@@ -330,7 +337,7 @@ func (vpc Vpc) FindSecurityGroupsByVPC() ([]string, error) {
 
 	vpcSvc = vpc.services.GetVpcSvc()
 
-	ctx, cancel = vpc.services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	groupID = vpc.services.GetMetadata().GetResourceGroup()
@@ -340,7 +347,7 @@ func (vpc Vpc) FindSecurityGroupsByVPC() ([]string, error) {
 	}
 	log.Debugf("findSecurityGroupsByVPC: groupID = %s", groupID)
 
-	getOptions = vpcSvc.NewGetVPCDefaultSecurityGroupOptions(*vpc.innerVpc.ID)
+	getOptions = &vpcv1.GetVPCDefaultSecurityGroupOptions{ID: vpc.innerVpc.ID}
 
 	defaultSG, _, err = vpcSvc.GetVPCDefaultSecurityGroupWithContext(ctx, getOptions)
 	if err != nil {
@@ -383,14 +390,12 @@ func (vpc Vpc) FindSecurityGroupsByVPC() ([]string, error) {
 	return result, nil
 }
 
-func (vpc *Vpc) FindInstance(name string) (*vpcv1.Instance, error) {
+func (vpc *Vpc) FindInstance(ctx context.Context, name string) (*vpcv1.Instance, error) {
 	var (
-		vpcSvc      *vpcv1.VpcV1
-		ctx         context.Context
+		vpcSvc      VpcClient
 		cancel      context.CancelFunc
 		groupID     string
 		listOptions *vpcv1.ListInstancesOptions
-		pager       *vpcv1.InstancesPager
 		instances   []vpcv1.Instance
 		instance    vpcv1.Instance
 		err         error
@@ -404,7 +409,7 @@ func (vpc *Vpc) FindInstance(name string) (*vpcv1.Instance, error) {
 
 	vpcSvc = vpc.services.GetVpcSvc()
 
-	ctx, cancel = vpc.services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	groupID = vpc.services.GetMetadata().GetResourceGroup()
@@ -414,17 +419,11 @@ func (vpc *Vpc) FindInstance(name string) (*vpcv1.Instance, error) {
 	}
 	log.Debugf("FindInstance: groupID = %s", groupID)
 
-	listOptions = vpcSvc.NewListInstancesOptions()
+	listOptions = &vpcv1.ListInstancesOptions{}
 	listOptions.SetResourceGroupID(groupID)
 	//listOptions.SetVPCID(*vpc.innerVpc.ID)
 
-	pager, err = vpcSvc.NewInstancesPager(listOptions)
-	if err != nil {
-		log.Fatalf("Error: FindInstance: NewInstancesPager returns %v", err)
-		return nil, err
-	}
-
-	instances, err = pager.GetAllWithContext(ctx)
+	instances, err = listAllInstances(vpcSvc, ctx, listOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -438,7 +437,7 @@ func (vpc *Vpc) FindInstance(name string) (*vpcv1.Instance, error) {
 
 		log.Debugf("FindInstance: FOUND %s %s", *instance.Name, *instance.HealthState)
 
-		getInstanceOptions := vpcSvc.NewGetInstanceOptions(*instance.ID)
+		getInstanceOptions := &vpcv1.GetInstanceOptions{ID: instance.ID}
 
 		foundInstance, _, err := vpcSvc.GetInstanceWithContext(ctx, getInstanceOptions)
 		if err != nil {
@@ -451,10 +450,9 @@ func (vpc *Vpc) FindInstance(name string) (*vpcv1.Instance, error) {
 	return nil, nil
 }
 
-func (vpc *Vpc) CreateInstance(instancePrototype *vpcv1.InstancePrototypeInstanceByImage) (*vpcv1.Instance, error) {
+func (vpc *Vpc) CreateInstance(ctx context.Context, instancePrototype *vpcv1.InstancePrototypeInstanceByImage) (*vpcv1.Instance, error) {
 	var (
-		vpcSvc   *vpcv1.VpcV1
-		ctx      context.Context
+		vpcSvc   VpcClient
 		cancel   context.CancelFunc
 		options  *vpcv1.CreateInstanceOptions
 		instance *vpcv1.Instance
@@ -467,10 +465,10 @@ func (vpc *Vpc) CreateInstance(instancePrototype *vpcv1.InstancePrototypeInstanc
 
 	vpcSvc = vpc.services.GetVpcSvc()
 
-	ctx, cancel = vpc.services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	options = vpcSvc.NewCreateInstanceOptions(instancePrototype)
+	options = &vpcv1.CreateInstanceOptions{InstancePrototype: instancePrototype}
 
 	instance, _, err = vpcSvc.CreateInstanceWithContext(ctx, options)
 	log.Debugf("instance = %+v", instance)
@@ -479,10 +477,16 @@ func (vpc *Vpc) CreateInstance(instancePrototype *vpcv1.InstancePrototypeInstanc
 	return instance, err
 }
 
-func (vpc *Vpc) GetRegionZones() ([]string, error) {
+// GetRegionZones returns the zones of the VPC region.  They are only listed once per run.
+func (vpc *Vpc) GetRegionZones(ctx context.Context) ([]string, error) {
+	return cachedCall(vpc.services.GetCache(), cacheKey("vpc.regionZones"), func() ([]string, error) {
+		return vpc.getRegionZones(ctx)
+	})
+}
+
+func (vpc *Vpc) getRegionZones(ctx context.Context) ([]string, error) {
 	var (
-		vpcSvc         *vpcv1.VpcV1
-		ctx            context.Context
+		vpcSvc         VpcClient
 		cancel         context.CancelFunc
 		vpcRegion      string
 		options        *vpcv1.ListRegionZonesOptions
@@ -497,7 +501,7 @@ func (vpc *Vpc) GetRegionZones() ([]string, error) {
 
 	vpcSvc = vpc.services.GetVpcSvc()
 
-	ctx, cancel = vpc.services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	vpcRegion, err = vpc.services.GetMetadata().GetVPCRegion()
@@ -505,7 +509,7 @@ func (vpc *Vpc) GetRegionZones() ([]string, error) {
 		return nil, err
 	}
 
-	options = vpcSvc.NewListRegionZonesOptions(vpcRegion)
+	options = &vpcv1.ListRegionZonesOptions{RegionName: ptr.To(vpcRegion)}
 
 	zoneCollection, _, err = vpcSvc.ListRegionZonesWithContext(ctx, options)
 	if err != nil {
@@ -520,16 +524,25 @@ func (vpc *Vpc) GetRegionZones() ([]string, error) {
 	return result, nil
 }
 
-func (vpc *Vpc) ListImages() ([]vpcv1.Image, error) {
+// ListImages returns the available public images, listed once per run.
+func (vpc *Vpc) ListImages(ctx context.Context) ([]vpcv1.Image, error) {
+	return cachedCall(vpc.services.GetCache(), cacheKey("vpc.images"), func() ([]vpcv1.Image, error) {
+		return vpc.listImages(ctx)
+	})
+}
+
+func (vpc *Vpc) listImages(ctx context.Context) ([]vpcv1.Image, error) {
 	var (
-		vpcSvc *vpcv1.VpcV1
-		ctx    context.Context
-		cancel context.CancelFunc
-		pager  *vpcv1.ImagesPager
-		images []vpcv1.Image
-		image  vpcv1.Image
-		result = make([]vpcv1.Image, 0)
-		err    error
+		vpcSvc   VpcClient
+		cancel   context.CancelFunc
+		options  *vpcv1.ListImagesOptions
+		page     *vpcv1.ImageCollection
+		start    *string
+		moreData = true
+		images   []vpcv1.Image
+		image    vpcv1.Image
+		result   = make([]vpcv1.Image, 0)
+		err      error
 	)
 
 	if vpc.innerVpc == nil {
@@ -538,23 +551,32 @@ func (vpc *Vpc) ListImages() ([]vpcv1.Image, error) {
 
 	vpcSvc = vpc.services.GetVpcSvc()
 
-	ctx, cancel = vpc.services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	pager, err = vpcSvc.NewImagesPager(&vpcv1.ListImagesOptions{
+	options = &vpcv1.ListImagesOptions{
 		Status: []string{
 			vpcv1.ListImagesOptionsStatusAvailableConst,
 		},
 		Visibility: ptr.To(vpcv1.ListImagesOptionsVisibilityPublicConst),
-	})
-	if err != nil {
-		log.Fatalf("Error: ListImages: NewImagesPager returns %v", err)
-		return nil, err
 	}
 
-	images, err = pager.GetAllWithContext(ctx)
-	if err != nil {
-		return nil, err
+	for moreData {
+		page, _, err = vpcSvc.ListImagesWithContext(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, page.Images...)
+
+		if page.Next != nil {
+			start, err = page.GetNextStart()
+			if err != nil {
+				return nil, err
+			}
+			options.SetStart(*start)
+		} else {
+			moreData = false
+		}
 	}
 
 	for _, image = range images {
@@ -569,16 +591,25 @@ func (vpc *Vpc) ListImages() ([]vpcv1.Image, error) {
 	return result, nil
 }
 
-func (vpc *Vpc) ListSshKeys() ([]vpcv1.Key, error) {
+// ListSshKeys returns the ssh keys of the account, listed once per run.
+func (vpc *Vpc) ListSshKeys(ctx context.Context) ([]vpcv1.Key, error) {
+	return cachedCall(vpc.services.GetCache(), cacheKey("vpc.keys"), func() ([]vpcv1.Key, error) {
+		return vpc.listSshKeys(ctx)
+	})
+}
+
+func (vpc *Vpc) listSshKeys(ctx context.Context) ([]vpcv1.Key, error) {
 	var (
-		vpcSvc *vpcv1.VpcV1
-		ctx    context.Context
-		cancel context.CancelFunc
-		pager  *vpcv1.KeysPager
-		keys   []vpcv1.Key
-		key    vpcv1.Key
-		result = make([]vpcv1.Key, 0)
-		err    error
+		vpcSvc   VpcClient
+		cancel   context.CancelFunc
+		options  *vpcv1.ListKeysOptions
+		page     *vpcv1.KeyCollection
+		start    *string
+		moreData = true
+		keys     []vpcv1.Key
+		key      vpcv1.Key
+		result   = make([]vpcv1.Key, 0)
+		err      error
 	)
 
 	if vpc.innerVpc == nil {
@@ -587,18 +618,27 @@ func (vpc *Vpc) ListSshKeys() ([]vpcv1.Key, error) {
 
 	vpcSvc = vpc.services.GetVpcSvc()
 
-	ctx, cancel = vpc.services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	pager, err = vpcSvc.NewKeysPager(&vpcv1.ListKeysOptions{})
-	if err != nil {
-		log.Fatalf("Error: ListSshKeys: NewKeysPager returns %v", err)
-		return nil, err
-	}
+	options = &vpcv1.ListKeysOptions{}
 
-	keys, err = pager.GetAllWithContext(ctx)
-	if err != nil {
-		return nil, err
+	for moreData {
+		page, _, err = vpcSvc.ListKeysWithContext(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, page.Keys...)
+
+		if page.Next != nil {
+			start, err = page.GetNextStart()
+			if err != nil {
+				return nil, err
+			}
+			options.SetStart(*start)
+		} else {
+			moreData = false
+		}
 	}
 
 	for _, key = range keys {
@@ -609,17 +649,26 @@ func (vpc *Vpc) ListSshKeys() ([]vpcv1.Key, error) {
 	return result, nil
 }
 
-func (vpc *Vpc) ListFips() ([]vpcv1.FloatingIP, error) {
+// ListFips returns the floating IPs of the resource group.  The listing is cached until
+// a floating IP is created.
+func (vpc *Vpc) ListFips(ctx context.Context) ([]vpcv1.FloatingIP, error) {
+	return cachedCall(vpc.services.GetCache(), cacheKey("vpc.fips", vpc.services.GetResourceGroupID()), func() ([]vpcv1.FloatingIP, error) {
+		return vpc.listFips(ctx)
+	})
+}
+
+func (vpc *Vpc) listFips(ctx context.Context) ([]vpcv1.FloatingIP, error) {
 	var (
-		vpcSvc  *vpcv1.VpcV1
-		ctx     context.Context
-		cancel  context.CancelFunc
-		options *vpcv1.ListFloatingIpsOptions
-		pager   *vpcv1.FloatingIpsPager
-		fips    []vpcv1.FloatingIP
-		fip     vpcv1.FloatingIP
-		result  = make([]vpcv1.FloatingIP, 0)
-		err     error
+		vpcSvc   VpcClient
+		cancel   context.CancelFunc
+		options  *vpcv1.ListFloatingIpsOptions
+		page     *vpcv1.FloatingIPCollection
+		start    *string
+		moreData = true
+		fips     []vpcv1.FloatingIP
+		fip      vpcv1.FloatingIP
+		result   = make([]vpcv1.FloatingIP, 0)
+		err      error
 	)
 
 	if vpc.innerVpc == nil {
@@ -628,20 +677,28 @@ func (vpc *Vpc) ListFips() ([]vpcv1.FloatingIP, error) {
 
 	vpcSvc = vpc.services.GetVpcSvc()
 
-	ctx, cancel = vpc.services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	options = vpcSvc.NewListFloatingIpsOptions()
+	options = &vpcv1.ListFloatingIpsOptions{}
 	options.SetResourceGroupID(vpc.services.GetResourceGroupID())
 
-	pager, err = vpcSvc.NewFloatingIpsPager(options)
-	if err != nil {
-		return nil, err
-	}
+	for moreData {
+		page, _, err = vpcSvc.ListFloatingIpsWithContext(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		fips = append(fips, page.FloatingIps...)
 
-	fips, err = pager.GetAllWithContext(ctx)
-	if err != nil {
-		return nil, err
+		if page.Next != nil {
+			start, err = page.GetNextStart()
+			if err != nil {
+				return nil, err
+			}
+			options.SetStart(*start)
+		} else {
+			moreData = false
+		}
 	}
 
 	for _, fip = range fips {
@@ -652,10 +709,9 @@ func (vpc *Vpc) ListFips() ([]vpcv1.FloatingIP, error) {
 	return result, nil
 }
 
-func (vpc *Vpc) CreateFIP(instance *vpcv1.Instance) error {
+func (vpc *Vpc) CreateFIP(ctx context.Context, instance *vpcv1.Instance) error {
 	var (
-		vpcSvc                  *vpcv1.VpcV1
-		ctx                     context.Context
+		vpcSvc                  VpcClient
 		cancel                  context.CancelFunc
 		createFloatingIPOptions *vpcv1.CreateFloatingIPOptions
 		fipName                 string
@@ -680,10 +736,10 @@ func (vpc *Vpc) CreateFIP(instance *vpcv1.Instance) error {
 
 	vpcSvc = vpc.services.GetVpcSvc()
 
-	ctx, cancel = vpc.services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	fips, err = vpc.ListFips()
+	fips, err = vpc.ListFips(ctx)
 	if err != nil {
 		return err
 	}
@@ -702,7 +758,7 @@ func (vpc *Vpc) CreateFIP(instance *vpcv1.Instance) error {
 	if foundFip == nil {
 		fmt.Println("Creating floating IP")
 
-		zones, err := vpc.GetRegionZones()
+		zones, err := vpc.GetRegionZones(ctx)
 		if err != nil {
 			return err
 		}
@@ -714,17 +770,20 @@ func (vpc *Vpc) CreateFIP(instance *vpcv1.Instance) error {
 
 		resourceGroupID = vpc.services.GetResourceGroupID()
 
-		createFloatingIPOptions = vpcSvc.NewCreateFloatingIPOptions(&vpcv1.FloatingIPPrototypeFloatingIPByZone{
-			Name: &fipName,
-			ResourceGroup: &vpcv1.ResourceGroupIdentity{
-				ID: &resourceGroupID,
+		createFloatingIPOptions = &vpcv1.CreateFloatingIPOptions{
+			FloatingIPPrototype: &vpcv1.FloatingIPPrototypeFloatingIPByZone{
+				Name: &fipName,
+				ResourceGroup: &vpcv1.ResourceGroupIdentity{
+					ID: &resourceGroupID,
+				},
+				Zone: &vpcv1.ZoneIdentityByName{
+					Name: &zone,
+				},
 			},
-			Zone: &vpcv1.ZoneIdentityByName{
-				Name: &zone,
-			},
-		})
+		}
 
 		foundFip, _, err := vpcSvc.CreateFloatingIPWithContext(ctx, createFloatingIPOptions)
+		vpc.services.GetCache().Invalidate("vpc.fips(")
 		log.Debugf("CreateFIP: foundFip = %+v", foundFip)
 		log.Debugf("CreateFIP: err = %+v", err)
 		if err != nil {
@@ -744,10 +803,10 @@ func (vpc *Vpc) CreateFIP(instance *vpcv1.Instance) error {
 		log.Debugf("CreateFIP: niface                   = %+v", niface)
 	}
 
-	listNifaceFipOptions = vpcSvc.NewListInstanceNetworkInterfaceFloatingIpsOptions(
-		*instance.ID,
-		*instance.NetworkInterfaces[0].ID,
-	)
+	listNifaceFipOptions = &vpcv1.ListInstanceNetworkInterfaceFloatingIpsOptions{
+		InstanceID:         instance.ID,
+		NetworkInterfaceID: instance.NetworkInterfaces[0].ID,
+	}
 
 	fipCollection, _, err = vpcSvc.ListInstanceNetworkInterfaceFloatingIpsWithContext(ctx, listNifaceFipOptions)
 	log.Debugf("CreateFIP: len(fipCollection).FloatingIps = %d", len(fipCollection.FloatingIps))
@@ -768,10 +827,11 @@ func (vpc *Vpc) CreateFIP(instance *vpcv1.Instance) error {
 		}
 	}
 
-	addInstanceOptions = vpcSvc.NewAddInstanceNetworkInterfaceFloatingIPOptions(
-		*instance.ID,
-		*instance.NetworkInterfaces[0].ID,
-		*fip.ID)
+	addInstanceOptions = &vpcv1.AddInstanceNetworkInterfaceFloatingIPOptions{
+		InstanceID:         instance.ID,
+		NetworkInterfaceID: instance.NetworkInterfaces[0].ID,
+		ID:                 fip.ID,
+	}
 
 	foundFip, _, err = vpcSvc.AddInstanceNetworkInterfaceFloatingIPWithContext(ctx, addInstanceOptions)
 	log.Debugf("CreateFIP: foundFip = %+v", foundFip)
@@ -813,30 +873,32 @@ func (vpc *Vpc) ObjectName() (string, error) {
 	return vpcObjectName, nil
 }
 
-func (vpc *Vpc) Run() error {
+func (vpc *Vpc) Run(ctx context.Context) error {
 	// Nothing to do here!
 	return nil
 }
 
-func (vpc *Vpc) CiStatus(shouldClean bool) {
+func (vpc *Vpc) CiStatus(ctx context.Context, shouldClean bool) *ObjectResult {
+	return NewObjectResult(vpc, vpc.name)
 }
 
-func (vpc *Vpc) ClusterStatus() {
+func (vpc *Vpc) ClusterStatus(ctx context.Context) *ObjectResult {
 	var (
-		isOk         bool
+		result       *ObjectResult
 		subnets      []*vpcv1.Subnet
 		subnet       *vpcv1.Subnet
 		countSubnets int
+		minSubnets   int
 		err          error
 	)
 
+	result = NewObjectResult(vpc, vpc.name)
+
 	if vpc.innerVpc == nil {
-		fmt.Printf("%s is NOTOK. Could not find a VPC named %s\n", vpcObjectName, vpc.name)
-		return
+		result.AddError("vpc.exists", "Could not find a VPC named %s", vpc.name)
+		return result
 	}
 
-	isOk = true
-
 	switch *vpc.innerVpc.HealthState {
 	case "ok":
 		//	case "degraded":
@@ -845,14 +907,13 @@ func (vpc *Vpc) ClusterStatus() {
 		//	case "failed":
 		//	case "deleting":
 	default:
-		fmt.Printf("%s %s is NOTOK.  The health state is not ok but %s\n", vpcObjectName, vpc.name, *vpc.innerVpc.HealthState)
-		isOk = false
+		result.AddNotOK("vpc.health", "The health state is not ok but %s", *vpc.innerVpc.HealthState).
+			WithEvidence("healthState", *vpc.innerVpc.HealthState)
 	}
 
-	subnets, err = vpc.ListSubnets()
+	subnets, err = vpc.ListSubnets(ctx)
 	if err != nil {
-		fmt.Printf("%s %s is NOTOK.  Received %v querying subnets\n", vpcObjectName, vpc.name, err)
-		isOk = false
+		result.AddError("vpc.subnets", "Received %v querying subnets", err)
 	}
 
 	countSubnets = 0
@@ -860,29 +921,26 @@ func (vpc *Vpc) ClusterStatus() {
 		countSubnets++
 
 		if *subnet.Status == "available" {
-			fmt.Printf("%s %s found subnet %s\n", vpcObjectName, vpc.name, *subnet.Name)
+			result.AddOK("vpc.subnet", "found subnet %s", *subnet.Name).
+				WithEvidence("subnet", *subnet.Name)
 		} else {
-			fmt.Printf("%s %s is NOTOK subnet %s has status %s\n", vpcObjectName, vpc.name, *subnet.Name, *subnet.Status)
-			isOk = false
+			result.AddNotOK("vpc.subnet", "subnet %s has status %s", *subnet.Name, *subnet.Status).
+				WithEvidence("subnet", *subnet.Name).
+				WithEvidence("status", *subnet.Status)
 		}
 	}
-	if countSubnets < 3 {
-		fmt.Printf("%s %s is NOTOK expecting at leasst 3 subnets, found %d\n", vpcObjectName, vpc.name, countSubnets)
-		isOk = false
+	minSubnets = vpc.services.GetMetadata().GetExpectations().VPCSubnets
+	if countSubnets < minSubnets {
+		result.AddNotOK("vpc.subnets", "expecting at least %d subnets, found %d", minSubnets, countSubnets).
+			WithEvidence("count", fmt.Sprintf("%d", countSubnets))
 	}
 
-	idRules, _ := vpc.FindSecurityGroupsByVPC()
+	idRules, _ := vpc.FindSecurityGroupsByVPC(ctx)
 	log.Debugf("idRules = %+v", idRules)
 
 	// func (vpc *VpcV1) ListSecurityGroupsWithContext(ctx context.Context, listSecurityGroupsOptions *ListSecurityGroupsOptions) (result *SecurityGroupCollection, response *core.DetailedResponse, err error) {
 	// func (vpc *VpcV1) GetSecurityGroupWithContext(ctx context.Context, getSecurityGroupOptions *GetSecurityGroupOptions) (result *SecurityGroup, response *core.DetailedResponse, err error) {
 	// func (vpc *VpcV1) ListSecurityGroupRulesWithContext(ctx context.Context, listSecurityGroupRulesOptions *ListSecurityGroupRulesOptions) (result *SecurityGroupRuleCollection, response *core.DetailedResponse, err error) {
 
-	if isOk {
-		fmt.Printf("%s %s is OK.\n", vpcObjectName, vpc.name)
-	}
-}
-
-func (vpc *Vpc) Priority() (int, error) {
-	return 100, nil
+	return result
 }
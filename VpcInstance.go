@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/IBM/go-sdk-core/v5/core"
 	"github.com/IBM/vpc-go-sdk/vpcv1"
 )
 
@@ -36,14 +37,25 @@ const (
 	vpciObjectName = "Cloud VM"
 )
 
-func NewVpcInstance(services *Services) ([]RunnableObject, []error) {
+func init() {
+	RegisterRunnableObject(RunnableObjectRegistration{
+		Name:         vpciObjectName,
+		Key:          "vsi",
+		New:          NewVpcInstance,
+		Modes:        []RunMode{RunModeCreate},
+		Dependencies: []string{vpcObjectName},
+		Order:        60,
+	})
+}
+
+func NewVpcInstance(ctx context.Context, services *Services) ([]RunnableObject, []error) {
 	var (
 		vpcis    []*VpcInstance
 		errs     []error
 		ros      []RunnableObject
 	)
 
-	vpcis, errs = innerNewVpcInstance(services)
+	vpcis, errs = innerNewVpcInstance(ctx, services)
 
 	ros = make([]RunnableObject, len(vpcis))
 	// Go does not support type converting the entire array.
@@ -55,16 +67,15 @@ func NewVpcInstance(services *Services) ([]RunnableObject, []error) {
 	return ros, errs
 }
 
-func NewVpcInstanceAlt(services *Services) ([]*VpcInstance, []error) {
-	return innerNewVpcInstance(services)
+func NewVpcInstanceAlt(ctx context.Context, services *Services) ([]*VpcInstance, []error) {
+	return innerNewVpcInstance(ctx, services)
 }
 
-func innerNewVpcInstance(services *Services) ([]*VpcInstance, []error) {
+func innerNewVpcInstance(ctx context.Context, services *Services) ([]*VpcInstance, []error) {
 	var (
 		vpcInstanceName string
 		resourceGroupID string
-		vpcSvc          *vpcv1.VpcV1
-		ctx             context.Context
+		vpcSvc          VpcClient
 		cancel          context.CancelFunc
 		foundInstances  []string
 		vpcis           []*VpcInstance
@@ -95,11 +106,11 @@ func innerNewVpcInstance(services *Services) ([]*VpcInstance, []error) {
 
 	vpcSvc = services.GetVpcSvc()
 
-	ctx, cancel = services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
 	if fUseTagSearch {
-		foundInstances, err = listByTag(TagTypeCloudInstance, services)
+		foundInstances, err = listByTag(ctx, TagTypeCloudInstance, services)
 	} else {
 		foundInstances, err = findVPCInstancesByName(vpcSvc, ctx, vpcInstanceName, resourceGroupID)
 	}
@@ -127,7 +138,7 @@ func innerNewVpcInstance(services *Services) ([]*VpcInstance, []error) {
 			instanceName       string
 		)
 
-		getInstanceOptions = vpcSvc.NewGetInstanceOptions(instanceID)
+		getInstanceOptions = &vpcv1.GetInstanceOptions{ID: &instanceID}
 
 		instance, _, err = vpcSvc.GetInstanceWithContext(ctx, getInstanceOptions)
 		if err != nil {
@@ -151,10 +162,9 @@ func innerNewVpcInstance(services *Services) ([]*VpcInstance, []error) {
 	return vpcis, errs
 }
 
-func findVPCInstancesByName(vpcSvc *vpcv1.VpcV1, ctx context.Context, name string, groupID string) ([]string, error) {
+func findVPCInstancesByName(vpcSvc VpcClient, ctx context.Context, name string, groupID string) ([]string, error) {
 	var (
 		listOptions    *vpcv1.ListInstancesOptions
-		pager          *vpcv1.InstancesPager
 		allInstances   []vpcv1.Instance
 		instance       vpcv1.Instance
 		foundInstances []string
@@ -164,16 +174,10 @@ func findVPCInstancesByName(vpcSvc *vpcv1.VpcV1, ctx context.Context, name strin
 	log.Debugf("findVPCInstancesByName: name = %s", name)
 	log.Debugf("findVPCInstancesByName: groupID = %s", groupID)
 
-	listOptions = vpcSvc.NewListInstancesOptions()
+	listOptions = &vpcv1.ListInstancesOptions{}
 	listOptions.SetResourceGroupID(groupID)
 
-	pager, err = vpcSvc.NewInstancesPager(listOptions)
-	if err != nil {
-		log.Fatalf("Error: findVPCInstancesByName: NewInstancesPager returns %v", err)
-		return nil, err
-	}
-
-	allInstances, err = pager.GetAllWithContext(ctx)
+	allInstances, err = listAllInstances(vpcSvc, ctx, listOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -193,6 +197,40 @@ func findVPCInstancesByName(vpcSvc *vpcv1.VpcV1, ctx context.Context, name strin
 	return foundInstances, nil
 }
 
+// listAllInstances returns every page of the VPC instances matching the options.
+func listAllInstances(vpcSvc VpcClient, ctx context.Context, listOptions *vpcv1.ListInstancesOptions) ([]vpcv1.Instance, error) {
+	var (
+		instances *vpcv1.InstanceCollection
+		response  *core.DetailedResponse
+		start     *string
+		moreData  = true
+		result    []vpcv1.Instance
+		err       error
+	)
+
+	for moreData {
+		instances, response, err = vpcSvc.ListInstancesWithContext(ctx, listOptions)
+		if err != nil {
+			return nil, fmt.Errorf("Error: listAllInstances: ListInstances: response = %v, err = %w", response, err)
+		}
+
+		result = append(result, instances.Instances...)
+
+		if instances.Next != nil {
+			start, err = instances.GetNextStart()
+			if err != nil {
+				return nil, err
+			}
+			log.Debugf("listAllInstances: start = %+v", *start)
+			listOptions.SetStart(*start)
+		} else {
+			moreData = false
+		}
+	}
+
+	return result, nil
+}
+
 func (vpci *VpcInstance) CRN() (string, error) {
 	if vpci.innerVpcInstance == nil || vpci.innerVpcInstance.CRN == nil {
 		return "(error)", nil
@@ -213,17 +251,24 @@ func (vpci *VpcInstance) ObjectName() (string, error) {
 	return vpciObjectName, nil
 }
 
-func (vpci *VpcInstance) Run() error {
+func (vpci *VpcInstance) Run(ctx context.Context) error {
 	return nil
 }
 
-func (vpci *VpcInstance) CiStatus(shouldClean bool) {
+func (vpci *VpcInstance) CiStatus(ctx context.Context, shouldClean bool) *ObjectResult {
+	return NewObjectResult(vpci, vpci.name)
 }
 
-func (vpci *VpcInstance) ClusterStatus() {
+func (vpci *VpcInstance) ClusterStatus(ctx context.Context) *ObjectResult {
+	var (
+		result *ObjectResult
+	)
+
+	result = NewObjectResult(vpci, vpci.name)
+
 	if vpci.innerVpcInstance == nil {
-		fmt.Printf("%s is NOTOK. Could not find a %s named %s\n", vpciObjectName, vpciObjectName, vpci.name)
-		return
+		result.AddError("vpci.exists", "Could not find a %s named %s", vpciObjectName, vpci.name)
+		return result
 	}
 
 	switch *vpci.innerVpcInstance.HealthState {
@@ -233,14 +278,11 @@ func (vpci *VpcInstance) ClusterStatus() {
 		//	case "inapplicable":
 		//	case "failed":
 		//	case "deleting":
+		result.AddOK("vpci.health", "has a health state of %s", *vpci.innerVpcInstance.HealthState)
 	default:
-		fmt.Printf("%s %s is NOTOK.  The health state is not ok but %s\n", vpciObjectName, vpci.name, *vpci.innerVpcInstance.HealthState)
-		return
+		result.AddNotOK("vpci.health", "The health state is not ok but %s", *vpci.innerVpcInstance.HealthState).
+			WithEvidence("healthState", *vpci.innerVpcInstance.HealthState)
 	}
 
-	fmt.Printf("%s %s is OK.\n", vpciObjectName, vpci.name)
-}
-
-func (vpci *VpcInstance) Priority() (int, error) {
-	return 85, nil
+	return result
 }
@@ -0,0 +1,73 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestVpcInstanceClusterStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		clusterName string
+		wantVpcis   int
+		wantStatus  CheckStatus
+		wantChecks  []string
+	}{
+		{"healthy instance", "good", 1, CheckStatusOK, []string{"vpci.health"}},
+		{"degraded instance", "bad", 1, CheckStatusNotOK, []string{"vpci.health"}},
+		// A cluster does not need a VPC instance, so none is not an error.
+		{"no instance", "lost", 0, CheckStatusOK, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestClusterServices(t, tt.clusterName, "g-good", "good-vpc")
+
+			vpcis, _ := NewVpcInstanceAlt(context.Background(), services)
+			if len(vpcis) != tt.wantVpcis {
+				t.Fatalf("NewVpcInstanceAlt returned %d instances, want %d", len(vpcis), tt.wantVpcis)
+			}
+
+			for _, vpci := range vpcis {
+				result := vpci.ClusterStatus(context.Background())
+				if result.Status != tt.wantStatus {
+					t.Errorf("ClusterStatus().Status = %s, want %s%s", result.Status, tt.wantStatus, dumpChecks(result))
+				}
+				for _, id := range tt.wantChecks {
+					if !hasCheck(result, id, tt.wantStatus) {
+						t.Errorf("ClusterStatus() has no %s check %s%s", tt.wantStatus, id, dumpChecks(result))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestVpcInstanceCiStatus(t *testing.T) {
+	services := newTestClusterServices(t, "bad", "g-good", "good-vpc")
+
+	vpcis, _ := NewVpcInstanceAlt(context.Background(), services)
+	if len(vpcis) != 1 {
+		t.Fatalf("NewVpcInstanceAlt returned %d instances, want 1", len(vpcis))
+	}
+
+	// check-ci does not look at the VPC instances, so even a degraded one reports nothing.
+	result := vpcis[0].CiStatus(context.Background(), false)
+	if result.Status != CheckStatusOK || len(result.Checks) != 0 {
+		t.Errorf("CiStatus() = %s with %d checks, want %s with none%s", result.Status, len(result.Checks), CheckStatusOK, dumpChecks(result))
+	}
+}
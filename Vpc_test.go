@@ -0,0 +1,83 @@
+// Copyright 2025 IBM Corp
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestVpcClusterStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		vpcName    string
+		wantStatus CheckStatus
+		wantChecks []string
+	}{
+		{"healthy VPC", "good-vpc", CheckStatusOK, []string{"vpc.subnet"}},
+		{"degraded VPC", "bad-vpc", CheckStatusNotOK, []string{"vpc.health", "vpc.subnet", "vpc.subnets"}},
+		{"VPC not found", "lost-vpc", CheckStatusError, []string{"vpc.exists"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestClusterServices(t, "good", "g-good", tt.vpcName)
+
+			vpcs, _ := NewVpcAlt(context.Background(), services)
+			if len(vpcs) != 1 {
+				t.Fatalf("NewVpcAlt returned %d VPCs, want 1", len(vpcs))
+			}
+
+			result := vpcs[0].ClusterStatus(context.Background())
+			if result.Status != tt.wantStatus {
+				t.Errorf("ClusterStatus().Status = %s, want %s%s", result.Status, tt.wantStatus, dumpChecks(result))
+			}
+			for _, id := range tt.wantChecks {
+				if !hasCheck(result, id, tt.wantStatus) {
+					t.Errorf("ClusterStatus() has no %s check %s%s", tt.wantStatus, id, dumpChecks(result))
+				}
+			}
+		})
+	}
+}
+
+func TestVpcCiStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		vpcName    string
+		wantStatus CheckStatus
+	}{
+		{"existing VPC", "good-vpc", CheckStatusOK},
+		{"degraded VPC", "bad-vpc", CheckStatusOK},
+		{"VPC not found", "lost-vpc", CheckStatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := newTestCIServices(t, "ci-empty", tt.vpcName, "")
+
+			vpcs, _ := NewVpcAlt(context.Background(), services)
+			if len(vpcs) != 1 {
+				t.Fatalf("NewVpcAlt returned %d VPCs, want 1", len(vpcs))
+			}
+
+			// check-ci only looks for the leftovers of the service instance, a VPC has none.
+			result := vpcs[0].CiStatus(context.Background(), false)
+			if result.Status != tt.wantStatus || len(result.Checks) != 0 {
+				t.Errorf("CiStatus() = %s with %d checks, want %s with none%s", result.Status, len(result.Checks), tt.wantStatus, dumpChecks(result))
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -25,7 +26,7 @@ const (
 type Object struct {
 }
 
-func NewObject(services *Services) ([]RunnableObject, error) {
+func NewObject(ctx context.Context, services *Services) ([]RunnableObject, error) {
 	var (
 	//		err       error
 	)
@@ -45,16 +46,14 @@ func (o *Object) ObjectName() (string, error) {
 	return "@TODO", nil
 }
 
-func (o *Object) Run() error {
+func (o *Object) Run(ctx context.Context) error {
 	return fmt.Errorf("@TODO not implemented yet")
 }
 
-func (o *Object) CiStatus(shouldClean bool) {
+func (o *Object) CiStatus(ctx context.Context, shouldClean bool) *ObjectResult {
+	return NewObjectResult(o, "")
 }
 
-func (o *Object) ClusterStatus() {
-}
-
-func (o *Object) Priority() (int, error) {
-	return -1, nil
+func (o *Object) ClusterStatus(ctx context.Context) *ObjectResult {
+	return NewObjectResult(o, "")
 }
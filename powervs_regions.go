@@ -14,11 +14,9 @@
 
 package main
 
-import (
-	"fmt"
-)
-
-// Since there is no API to query these, we have to hard-code them here.
+// These are the built-in regions of the region catalog.  The PowerVS datacenters API
+// lists the zones, but not the VPC and COS regions which go with them, so they are
+// hard-coded here.
 
 // Region describes resources associated with a region in Power VS.
 // We're using a few items from the IBM Cloud VPC offering. The region names
@@ -31,9 +29,12 @@ type Region struct {
 	VPCZones    []string
 }
 
-// Zone holds the sysTypes for a zone in a IBM Power VS region.
+// Zone holds the sysTypes for a zone in a IBM Power VS region.  The capabilities and
+// the status are only known when the zone is listed by the datacenters API.
 type Zone struct {
-	SysTypes []string
+	SysTypes     []string
+	Capabilities map[string]bool
+	Status       string
 }
 
 // Regions holds the regions for IBM Power VS, and descriptions used during the survey.
@@ -181,9 +182,5 @@ var Regions = map[string]Region{
 
 // VPCRegionForPowerVSRegion returns the VPC region for the specified PowerVS region.
 func VPCRegionForPowerVSRegion(region string) (string, error) {
-	if r, ok := Regions[region]; ok {
-		return r.VPCRegion, nil
-	}
-
-	return "", fmt.Errorf("VPC region corresponding to a PowerVS region %s not found ", region)
+	return regionCatalog.VPCRegion(region)
 }
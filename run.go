@@ -27,7 +27,7 @@ import (
 	"strings"
 )
 
-func runCommand(kubeconfig string, cmdline string) error {
+func runCommand(parent context.Context, kubeconfig string, cmdline string) error {
 	var (
 		acmdline []string
 		ctx      context.Context
@@ -37,7 +37,7 @@ func runCommand(kubeconfig string, cmdline string) error {
 		err      error
 	)
 
-	ctx, cancel = context.WithTimeout(context.Background(), defaultTimeout)
+	ctx, cancel = context.WithTimeout(parent, defaultTimeout)
 	defer cancel()
 
 	// Split the space separated line into an array of strings
@@ -64,7 +64,7 @@ func runCommand(kubeconfig string, cmdline string) error {
 	return err
 }
 
-func runSplitCommand(acmdline []string) error {
+func runSplitCommand(parent context.Context, acmdline []string) error {
 	var (
 		ctx    context.Context
 		cancel context.CancelFunc
@@ -73,7 +73,7 @@ func runSplitCommand(acmdline []string) error {
 		err    error
 	)
 
-	ctx, cancel = context.WithTimeout(context.Background(), defaultTimeout)
+	ctx, cancel = context.WithTimeout(parent, defaultTimeout)
 	defer cancel()
 
 	if len(acmdline) == 0 {
@@ -92,7 +92,7 @@ func runSplitCommand(acmdline []string) error {
 	return err
 }
 
-func runSplitCommandJson(kubeconfig string, acmdline []string) (map[string]interface{}, error) {
+func runSplitCommandJson(parent context.Context, kubeconfig string, acmdline []string) (map[string]interface{}, error) {
 	var (
 		ctx      context.Context
 		cancel   context.CancelFunc
@@ -102,7 +102,7 @@ func runSplitCommandJson(kubeconfig string, acmdline []string) (map[string]inter
 		err      error
 	)
 
-	ctx, cancel = context.WithTimeout(context.Background(), defaultTimeout)
+	ctx, cancel = context.WithTimeout(parent, defaultTimeout)
 	defer cancel()
 
 	if len(acmdline) == 0 {
@@ -132,7 +132,7 @@ func runSplitCommandJson(kubeconfig string, acmdline []string) (map[string]inter
 	return jsonData, err
 }
 
-func runTwoCommands(kubeconfig string, cmdline1 string, cmdline2 string) error {
+func runTwoCommands(parent context.Context, kubeconfig string, cmdline1 string, cmdline2 string) error {
 	var (
 		acmdline1 []string
 		acmdline2 []string
@@ -147,7 +147,7 @@ func runTwoCommands(kubeconfig string, cmdline1 string, cmdline2 string) error {
 		err       error
 	)
 
-	ctx, cancel = context.WithTimeout(context.Background(), defaultTimeout)
+	ctx, cancel = context.WithTimeout(parent, defaultTimeout)
 	defer cancel()
 
 	log.Debugf("cmdline1 = %s", cmdline1)
@@ -53,13 +53,12 @@ var (
 )
 
 // listByTag list IBM Cloud resources by matching tag.
-func listByTag(tagType TagType, services *Services) ([]string, error) {
+func listByTag(ctx context.Context, tagType TagType, services *Services) ([]string, error) {
 	var (
 		clusterName         string
 		query               string
-		ctx                 context.Context
 		cancel              context.CancelFunc
-		authenticator       *core.IamAuthenticator
+		authenticator       core.Authenticator
 		globalSearchOptions *globalsearchv2.GlobalSearchV2Options
 		searchService       *globalsearchv2.GlobalSearchV2
 		moreData                  = true
@@ -103,19 +102,13 @@ func listByTag(tagType TagType, services *Services) ([]string, error) {
 	}
 	log.Debugf("listByTag: query = %s", query)
 
-	ctx, cancel = services.GetContextWithTimeout()
+	ctx, cancel = context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	authenticator = &core.IamAuthenticator{
-		ApiKey: services.GetApiKey(),
-	}
-	err = authenticator.Validate()
-	if err != nil {
-		return nil, err
-	}
+	authenticator = services.GetAuthenticator()
 
 	globalSearchOptions = &globalsearchv2.GlobalSearchV2Options{
-		URL:           globalsearchv2.DefaultServiceURL,
+		URL:           services.GetMetadata().GetServiceEndpoint("GlobalSearch", globalsearchv2.DefaultServiceURL),
 		Authenticator: authenticator,
 	}
 
@@ -123,6 +116,7 @@ func listByTag(tagType TagType, services *Services) ([]string, error) {
 	if err != nil {
 		return nil, fmt.Errorf("listByTag: globalsearchv2.NewGlobalSearchV2: %w", err)
 	}
+	useCloudHTTPClient("GlobalSearch", searchService.Service)
 
 	result = make([]string, 0)
 